@@ -0,0 +1,211 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/archive"
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/transcript"
+)
+
+// Record is the metadata written next to an archived session's output.
+type Record struct {
+	Session  string           `json:"session"`
+	Project  string           `json:"project"`
+	Task     string           `json:"task,omitempty"`
+	Branch   string           `json:"branch,omitempty"`
+	Worktree string           `json:"worktree,omitempty"`
+	Started  time.Time        `json:"started"`
+	Ended    time.Time        `json:"ended"`
+	Duration string           `json:"duration"`
+	Turns    int              `json:"turns"`
+	Usage    transcript.Usage `json:"usage"`
+}
+
+// Archive exports an agent session's scrollback and Claude Code transcript
+// to a fresh directory under archive.ArchiveDir, records its metadata in
+// meta.json, then kills the session. The worktree, if any, is kept so its
+// branch can still be reviewed. It returns the archive directory.
+func Archive(s tmuxclient.Session) (string, error) {
+	now := time.Now()
+	dir := archive.SessionDir(s.Name, now)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := saveScrollback(s.Name, filepath.Join(dir, "scrollback.txt")); err != nil {
+		return "", err
+	}
+
+	rec := Record{
+		Session:  s.Name,
+		Project:  ProjectOf(s),
+		Task:     s.Task,
+		Branch:   s.Branch,
+		Worktree: s.Worktree,
+		Started:  s.Created,
+		Ended:    now,
+		Duration: now.Sub(s.Created).Round(time.Second).String(),
+	}
+	if path := transcript.Latest(s.Path); s.Path != "" && path != "" {
+		if err := copyFile(path, filepath.Join(dir, "transcript.jsonl")); err != nil {
+			return "", err
+		}
+		if info, err := transcript.Read(path); err == nil {
+			rec.Usage, rec.Turns = info.Usage, info.Turns
+			if rec.Task == "" {
+				rec.Task = info.Task
+			}
+		}
+	}
+	meta, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "meta.json"), meta, 0o644); err != nil {
+		return "", err
+	}
+
+	if err := tmuxclient.KillSession(context.Background(), s.Name); err != nil {
+		return dir, fmt.Errorf("archived to %s but kill failed: %w", dir, err)
+	}
+	_ = eventlog.Append(s.Name, "archive", "archived after %s ($%.2f) to %s", rec.Duration, rec.Usage.CostUSD, dir)
+	return dir, nil
+}
+
+var archiveOnKill bool
+
+// SetArchiveOnKill makes Kill, and so Remove, save the transcripts of a
+// session's panes before killing it.
+func SetArchiveOnKill(on bool) {
+	archiveOnKill = on
+}
+
+// Kill kills a session, first saving the transcripts of all its panes
+// when archiving on kill is enabled. A session whose transcripts can't be
+// saved is left running.
+func Kill(name string) error {
+	if archiveOnKill {
+		if _, err := archive.SaveTranscripts(name, time.Now()); err != nil {
+			return fmt.Errorf("save transcripts: %w", err)
+		}
+	}
+	return tmuxclient.KillSession(context.Background(), name)
+}
+
+// saveScrollback streams the session's scrollback into the file dst.
+func saveScrollback(session, dst string) error {
+	r, err := tmuxclient.StreamHistory(context.Background(), session)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// done remembers since when each agent has been idle.
+var done = struct {
+	sync.Mutex
+	after time.Duration
+	idle  map[string]time.Time
+}{idle: map[string]time.Time{}}
+
+// SetArchiveAfter enables the auto-archive policy: agents idle for d are
+// archived by ArchiveDone. Zero disables it.
+func SetArchiveAfter(d time.Duration) {
+	done.Lock()
+	done.after = d
+	done.Unlock()
+}
+
+// ArchiveDone archives detached agent sessions that finished their work:
+// idle for the configured period after at least one turn. Attached
+// sessions are left alone, since someone is looking at them. It returns
+// the archived session names.
+func ArchiveDone(sessions []tmuxclient.Session, states map[string]State, details map[string]transcript.Info) []string {
+	var archived []string
+	for _, s := range DueForArchive(sessions, states, details) {
+		if _, err := ArchiveFinished(s); err == nil {
+			archived = append(archived, s.Name)
+		}
+	}
+	return archived
+}
+
+// DueForArchive returns the sessions ArchiveDone would archive, without
+// archiving them. Each call counts towards the idle period, so call it
+// once per poll.
+func DueForArchive(sessions []tmuxclient.Session, states map[string]State, details map[string]transcript.Info) []tmuxclient.Session {
+	done.Lock()
+	defer done.Unlock()
+	if done.after <= 0 {
+		return nil
+	}
+	now := time.Now()
+	var due []tmuxclient.Session
+	for _, s := range sessions {
+		if states[s.Name] != StateIdle {
+			delete(done.idle, s.Name)
+			continue
+		}
+		since, ok := done.idle[s.Name]
+		if !ok {
+			done.idle[s.Name] = now
+			continue
+		}
+		if s.Attached || details[s.Name].Turns == 0 || now.Sub(since) < done.after {
+			continue
+		}
+		// Start a new period, so the session isn't due again while it is
+		// being archived, and is retried after another if that fails.
+		done.idle[s.Name] = now
+		due = append(due, s)
+	}
+	return due
+}
+
+// ArchiveFinished archives a session DueForArchive returned, logging a
+// failure, and returns the archive directory.
+func ArchiveFinished(s tmuxclient.Session) (string, error) {
+	dir, err := Archive(s)
+	if err != nil {
+		_ = eventlog.Append(s.Name, "archive", "auto-archive failed: %v", err)
+		return "", err
+	}
+	done.Lock()
+	delete(done.idle, s.Name)
+	done.Unlock()
+	return dir, nil
+}
@@ -0,0 +1,31 @@
+package agent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/agent"
+)
+
+// pane is a 40-line capture of an agent asking for permission.
+var pane = strings.Repeat("  ⎿  ok  	github.com/example/api/auth	0.412s\n", 30) + `
+● Bash(go test ./auth/...)
+  ⎿  Running…
+
+╭──────────────────────────────────────────────────────────╮
+│ Bash command                                             │
+│   go test ./auth/...                                     │
+│ Do you want to proceed?                                  │
+│ ❯ 1. Yes                                                 │
+│   2. No, and tell Claude what to do differently (esc)    │
+╰──────────────────────────────────────────────────────────╯
+`
+
+func BenchmarkClassify(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if st := agent.Classify(pane); st != agent.StatePermission {
+			b.Fatalf("Classify() = %v, want %v", st, agent.StatePermission)
+		}
+	}
+}
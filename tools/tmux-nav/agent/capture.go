@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"github.com/bjornslib/tmux-nav/archive"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+var autoCapture bool
+
+// SetCapture enables continuous pane capture for agent sessions: spawned
+// agents are captured from the start and EnsureCapture picks up the rest.
+func SetCapture(on bool) {
+	autoCapture = on
+}
+
+// EnsureCapture starts capturing agent sessions whose panes aren't piped
+// yet, when continuous capture is enabled.
+func EnsureCapture(sessions []tmuxclient.Session) {
+	for _, s := range Uncaptured(sessions) {
+		_ = archive.StartCapture(s.Name)
+	}
+}
+
+// Uncaptured returns the agent sessions EnsureCapture would start
+// capturing, without starting them.
+func Uncaptured(sessions []tmuxclient.Session) []tmuxclient.Session {
+	if !autoCapture {
+		return nil
+	}
+	var need []tmuxclient.Session
+	for _, s := range sessions {
+		if IsAgent(s) && !s.Piped {
+			need = append(need, s)
+		}
+	}
+	return need
+}
@@ -0,0 +1,47 @@
+// Package agent recognises Claude Code agent sessions and classifies what
+// they are doing.
+package agent
+
+import (
+	"path"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// DefaultNamePatterns are the session-name globs used by the harness when it
+// spawns agents (e.g. orch-<node>, s3-<initiative>).
+var DefaultNamePatterns = []string{"orch-*", "s3-*", "agent-*", "agent/*", "claude-*"}
+
+var namePatterns = DefaultNamePatterns
+
+// SetNamePatterns replaces the session-name globs that mark agent sessions.
+// A nil slice restores the defaults.
+func SetNamePatterns(patterns []string) {
+	if patterns == nil {
+		patterns = DefaultNamePatterns
+	}
+	namePatterns = patterns
+}
+
+// IsAgent reports whether s runs a Claude Code agent. A session counts as an
+// agent when it carries the @agent marker option, when its active pane runs
+// the claude binary, or when its name follows the naming scheme or a
+// harness naming pattern.
+func IsAgent(s tmuxclient.Session) bool {
+	if s.AgentTag != "" {
+		return true
+	}
+	if strings.HasPrefix(strings.ToLower(s.Command), "claude") {
+		return true
+	}
+	if Conforms(s.Name) {
+		return true
+	}
+	for _, p := range namePatterns {
+		if ok, _ := path.Match(p, s.Name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"sync"
+
+	"github.com/bjornslib/tmux-nav/git"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// roots caches repository roots by working directory; a directory doesn't
+// move between repositories while the navigator runs.
+var roots sync.Map
+
+// RepoRoot returns the main repository root an agent session works in, so
+// agents in linked worktrees of one repository share a root. Outside git
+// it is the project named by a configured naming scheme, else the
+// session's working directory; for non-agent sessions it is "".
+func RepoRoot(s tmuxclient.Session) string {
+	if !IsAgent(s) {
+		return ""
+	}
+	if s.Repo != "" {
+		return s.Repo
+	}
+	if s.Path == "" {
+		return ""
+	}
+	if root, ok := roots.Load(s.Path); ok {
+		return root.(string)
+	}
+	root, err := git.MainRoot(s.Path)
+	if err != nil {
+		root = s.Path
+		if project, _, ok := ParseName(s.Name); customScheme && ok && project != "" {
+			root = project
+		}
+	}
+	roots.Store(s.Path, root)
+	return root
+}
@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// EventChannel is the tmux wait-for channel signalled after every hook
+// event, so navigators can refresh immediately.
+const EventChannel = "tmux-nav-event"
+
+// HookEvent is the subset of a Claude Code hook payload tmux-nav uses.
+type HookEvent struct {
+	Name      string `json:"hook_event_name"`
+	SessionID string `json:"session_id"`
+	Message   string `json:"message"` // Notification text
+	Cwd       string `json:"cwd"`
+}
+
+// ReadHookEvent decodes a hook payload from r (Claude Code passes it on stdin).
+func ReadHookEvent(r io.Reader) (HookEvent, error) {
+	var ev HookEvent
+	if err := json.NewDecoder(r).Decode(&ev); err != nil {
+		return HookEvent{}, fmt.Errorf("hook payload: %w", err)
+	}
+	return ev, nil
+}
+
+// StateForEvent maps a hook event to the agent state it implies.
+// ok is false for events that say nothing about the state.
+func StateForEvent(ev HookEvent) (st State, ok bool) {
+	switch ev.Name {
+	case "UserPromptSubmit", "PreToolUse", "PostToolUse":
+		return StateWorking, true
+	case "PermissionRequest":
+		return StatePermission, true
+	case "Stop":
+		return StateIdle, true
+	case "Notification":
+		if strings.Contains(strings.ToLower(ev.Message), "permission") {
+			return StatePermission, true
+		}
+		return StateWaiting, true
+	}
+	return StateUnknown, false
+}
+
+// RecordHookEvent stamps the state implied by ev onto the session owning
+// the current pane ($TMUX_PANE) and signals EventChannel. It does nothing
+// outside tmux or for events that don't change the state.
+func RecordHookEvent(ev HookEvent) error {
+	pane := os.Getenv("TMUX_PANE")
+	if pane == "" {
+		return nil
+	}
+	session, err := tmuxclient.SessionOfPane(context.Background(), pane)
+	if err != nil {
+		return err
+	}
+	if ev.Name == "SessionEnd" {
+		_ = tmuxclient.UnsetOption(context.Background(), session, "@agent_state")
+		_ = tmuxclient.UnsetOption(context.Background(), session, "@agent_state_at")
+		return tmuxclient.Signal(context.Background(), EventChannel)
+	}
+	st, ok := StateForEvent(ev)
+	if !ok {
+		return nil
+	}
+	if err := tmuxclient.SetOption(context.Background(), session, "@agent_state", st.String()); err != nil {
+		return err
+	}
+	_ = tmuxclient.SetOption(context.Background(), session, "@agent_state_at", strconv.FormatInt(time.Now().Unix(), 10))
+	return tmuxclient.Signal(context.Background(), EventChannel)
+}
+
+// hookSlack is how much pane activity after a hook event is tolerated
+// before the pushed state is considered stale (rendering the state change
+// itself touches the pane).
+const hookSlack = 2 * time.Second
+
+// hookState returns the state pushed by hooks when it is still current.
+// A pushed "working" holds until the next event; other states hold only
+// while the pane has been quiet since, because typing into an idle or
+// waiting agent resumes it without a hook firing first.
+func hookState(s tmuxclient.Session) (State, bool) {
+	if s.HookState == "" {
+		return StateUnknown, false
+	}
+	st := ParseState(s.HookState)
+	if st == StateUnknown {
+		return StateUnknown, false
+	}
+	if st != StateWorking && s.LastUsed.After(s.HookAt.Add(hookSlack)) {
+		return StateUnknown, false
+	}
+	return st, true
+}
+
+// SettingsSnippet is the Claude Code settings.json fragment that wires the
+// hooks to `tmux-nav hook-event`.
+func SettingsSnippet(bin string) string {
+	events := []string{"UserPromptSubmit", "PreToolUse", "PermissionRequest", "Notification", "Stop", "SessionEnd"}
+	hooks := map[string]any{}
+	for _, e := range events {
+		hooks[e] = []any{map[string]any{
+			"hooks": []any{map[string]any{"type": "command", "command": bin + " hook-event"}},
+		}}
+	}
+	b, _ := json.MarshalIndent(map[string]any{"hooks": hooks}, "", "  ")
+	return string(b)
+}
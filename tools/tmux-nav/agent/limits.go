@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Limits caps the resources an agent session's command may use, so a
+// fleet of agents can't starve interactive sessions. The zero value
+// imposes no limits.
+type Limits struct {
+	Nice    int    // scheduling niceness, e.g. 10
+	IOClass string // ionice class: "idle" or "best-effort" (Linux)
+	CPU     int    // CPU cap in percent of one core (cpulimit, or cgroup quota)
+	Memory  string // memory cap, e.g. "4G" (cgroup only)
+	// Cgroup runs the agent in a transient systemd scope (systemd-run
+	// --user) enforcing CPU and Memory, instead of cpulimit.
+	Cgroup bool
+}
+
+// wrap returns command prefixed with the tools enforcing l. The command is
+// run through `sh -c` so shell syntax in it keeps working.
+func (l Limits) wrap(command string) (string, error) {
+	var prefix []string
+	if l.Cgroup {
+		if runtime.GOOS != "linux" {
+			return "", fmt.Errorf("cgroup limits need Linux with systemd")
+		}
+		prefix = append(prefix, "systemd-run", "--user", "--scope", "--quiet")
+		if l.CPU > 0 {
+			prefix = append(prefix, "-p", fmt.Sprintf("CPUQuota=%d%%", l.CPU))
+		}
+		if l.Memory != "" {
+			prefix = append(prefix, "-p", "MemoryMax="+l.Memory)
+		}
+		prefix = append(prefix, "--")
+	} else if l.Memory != "" {
+		return "", fmt.Errorf("a memory limit needs cgroup = true")
+	}
+	if l.Nice != 0 {
+		prefix = append(prefix, "nice", "-n", fmt.Sprint(l.Nice))
+	}
+	if l.IOClass != "" && runtime.GOOS == "linux" {
+		class, ok := ioClasses[l.IOClass]
+		if !ok {
+			return "", fmt.Errorf("unknown io class %q (idle, best-effort)", l.IOClass)
+		}
+		prefix = append(prefix, "ionice", "-c", class)
+	}
+	if l.CPU > 0 && !l.Cgroup {
+		if _, err := exec.LookPath("cpulimit"); err != nil {
+			return "", fmt.Errorf("cpu limit needs cpulimit installed (or cgroup = true)")
+		}
+		prefix = append(prefix, "cpulimit", "-l", fmt.Sprint(l.CPU), "-i", "--")
+	}
+	if len(prefix) == 0 {
+		return command, nil
+	}
+	return strings.Join(prefix, " ") + " sh -c " + shellQuote(command), nil
+}
+
+var ioClasses = map[string]string{"idle": "3", "best-effort": "2"}
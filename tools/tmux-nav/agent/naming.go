@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// DefaultNameScheme is how spawned agent sessions are named when the
+// harness doesn't define a scheme.
+const DefaultNameScheme = "agent-{project}-{task}"
+
+var (
+	nameScheme = DefaultNameScheme
+	nameRegexp = compileScheme(DefaultNameScheme)
+	// customScheme is set when the harness configured its own scheme: it is
+	// then enforced on explicit names and trusted to name projects.
+	customScheme bool
+)
+
+// SetNameScheme installs the harness's session naming scheme, e.g.
+// "agent/{project}/{task}". {project} and {task} are replaced by slugs.
+// Once a scheme is set, Spawn rejects explicit names that don't follow it;
+// "" restores the default, unenforced scheme.
+func SetNameScheme(scheme string) error {
+	customScheme = scheme != ""
+	if scheme == "" {
+		scheme = DefaultNameScheme
+	}
+	if err := ValidateScheme(scheme); err != nil {
+		return err
+	}
+	nameScheme, nameRegexp = scheme, compileScheme(scheme)
+	return nil
+}
+
+// NameScheme returns the naming scheme in use.
+func NameScheme() string {
+	return nameScheme
+}
+
+// ValidateScheme checks that scheme names every agent uniquely and yields
+// names tmux accepts.
+func ValidateScheme(scheme string) error {
+	if !strings.Contains(scheme, "{task}") {
+		return fmt.Errorf("name scheme %q must contain {task}", scheme)
+	}
+	if strings.ContainsAny(scheme, ":.") {
+		return fmt.Errorf("name scheme %q: tmux session names can't contain ':' or '.'", scheme)
+	}
+	return nil
+}
+
+// compileScheme turns a scheme into a regexp capturing project and task.
+func compileScheme(scheme string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for rest := scheme; rest != ""; {
+		i := strings.IndexByte(rest, '{')
+		j := strings.IndexByte(rest, '}')
+		if i < 0 || j < i {
+			sb.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		sb.WriteString(regexp.QuoteMeta(rest[:i]))
+		switch rest[i+1 : j] {
+		case "project":
+			sb.WriteString(`(?P<project>[a-z0-9-]+?)`)
+		case "task":
+			sb.WriteString(`(?P<task>[a-z0-9-]+)`)
+		default:
+			sb.WriteString(regexp.QuoteMeta(rest[i : j+1]))
+		}
+		rest = rest[j+1:]
+	}
+	sb.WriteString(`(-\d+)?$`) // uniqueName suffix
+	return regexp.MustCompile(sb.String())
+}
+
+// FormatName builds a session name from the scheme.
+func FormatName(project, task string) string {
+	r := strings.NewReplacer("{project}", slug(project, 0), "{task}", taskSlug(task))
+	return r.Replace(nameScheme)
+}
+
+// ParseName extracts project and task slugs from a name following the
+// scheme. ok is false for non-conforming names.
+func ParseName(name string) (project, task string, ok bool) {
+	m := nameRegexp.FindStringSubmatch(name)
+	if m == nil {
+		return "", "", false
+	}
+	if i := nameRegexp.SubexpIndex("project"); i >= 0 {
+		project = m[i]
+	}
+	return project, m[nameRegexp.SubexpIndex("task")], true
+}
+
+// Conforms reports whether name follows the naming scheme.
+func Conforms(name string) bool {
+	_, _, ok := ParseName(name)
+	return ok
+}
+
+// ConformingName proposes a scheme-conforming name for an existing agent
+// session, from its project and task (or its current name when it has no
+// task). It is unique among live sessions.
+func ConformingName(s tmuxclient.Session) string {
+	task := s.Task
+	if task == "" {
+		task = s.Name
+	}
+	return uniqueName(FormatName(ProjectOf(s), task))
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slug lowercases s into dash-separated words, cut to n bytes when n is
+// positive.
+func slug(s string, n int) string {
+	out := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if n > 0 && len(out) > n {
+		out = strings.TrimRight(out[:n], "-")
+	}
+	return out
+}
+
+// taskSlug shortens a task into a name component, or a timestamp when
+// there is no task.
+func taskSlug(task string) string {
+	if s := slug(task, 24); s != "" {
+		return s
+	}
+	return time.Now().Format("0102-1504")
+}
@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// submitDelay gives Claude Code time to absorb a paste before Enter
+// arrives; without it the Enter can land inside the paste and be lost.
+const submitDelay = 150 * time.Millisecond
+
+// SendPrompt types text into the agent's input box and submits it.
+// Multi-line text is pasted as one block so newlines don't submit early.
+func SendPrompt(session, text string) error {
+	target := tmuxclient.ActivePaneTarget(session)
+	text = strings.TrimRight(text, "\n")
+	if err := tmuxclient.PasteText(context.Background(), target, text); err != nil {
+		return err
+	}
+	time.Sleep(submitDelay)
+	return tmuxclient.SendKeys(context.Background(), target, "Enter")
+}
+
+// SendReply sends a canned reply: tmux keys first (e.g. "Escape" to stop
+// the current turn, or "2" to pick a menu option), then text as a prompt.
+// Either part may be empty.
+func SendReply(session string, keys []string, text string) error {
+	if len(keys) > 0 {
+		if err := tmuxclient.SendKeys(context.Background(), tmuxclient.ActivePaneTarget(session), keys...); err != nil {
+			return err
+		}
+		if text != "" {
+			time.Sleep(submitDelay)
+		}
+	}
+	if text == "" {
+		return nil
+	}
+	return SendPrompt(session, text)
+}
@@ -0,0 +1,39 @@
+package agent
+
+import (
+	"path"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// DefaultPruneAfter is how long a detached session must have been idle
+// for Prunable to pick it.
+const DefaultPruneAfter = 24 * time.Hour
+
+// Prunable returns the sessions to prune at now: those detached and idle
+// for longer than after (DefaultPruneAfter when zero), except the ones
+// whose names match an exclude glob.
+func Prunable(sessions []tmuxclient.Session, after time.Duration, exclude []string, now time.Time) []tmuxclient.Session {
+	if after <= 0 {
+		after = DefaultPruneAfter
+	}
+	var out []tmuxclient.Session
+	for _, s := range sessions {
+		if s.Attached || now.Sub(s.LastUsed) < after || excluded(s.Name, exclude) {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// excluded reports whether name matches any of the globs.
+func excluded(name string, globs []string) bool {
+	for _, g := range globs {
+		if ok, _ := path.Match(g, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// ErrNoPrompt is returned by Respond when the pane shows nothing to answer.
+var ErrNoPrompt = errors.New("no permission or y/n prompt visible")
+
+// Respond answers the prompt currently shown in the session's active pane.
+// The pane is re-captured first so a stale screen is never answered.
+// Claude Code permission menus are approved with "1" and denied with
+// Escape; plain y/n prompts get "y"/"n" followed by Enter.
+func Respond(session string, approve bool) error {
+	content, err := tmuxclient.CaptureText(context.Background(), session, 40)
+	if err != nil {
+		return err
+	}
+	tail := lastLines(content, tailLines)
+	target := tmuxclient.ActivePaneTarget(session)
+
+	switch {
+	case matchAny(tail, permissionPatterns):
+		if approve {
+			return tmuxclient.SendKeys(context.Background(), target, "1")
+		}
+		return tmuxclient.SendKeys(context.Background(), target, "Escape")
+
+	case matchAny(tail, []*regexp.Regexp{yesNoPattern}):
+		key := "n"
+		if approve {
+			key = "y"
+		}
+		return tmuxclient.SendKeys(context.Background(), target, key, "Enter")
+	}
+	return fmt.Errorf("%s: %w", session, ErrNoPrompt)
+}
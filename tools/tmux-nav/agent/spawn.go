@@ -0,0 +1,173 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/archive"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// DefaultCommand launches Claude Code when a project doesn't name one.
+const DefaultCommand = "claude"
+
+// Project is a harness template for spawning agent sessions.
+type Project struct {
+	Name    string
+	Dir     string            // working directory for the agent
+	Command string            // agent command; DefaultCommand when empty
+	Env     map[string]string // extra environment for the session
+	Limits  Limits            // resource limits for the agent command
+}
+
+var projects []Project
+
+// RegisterProjects installs the projects agents can be spawned from.
+func RegisterProjects(ps []Project) {
+	projects = append([]Project(nil), ps...)
+}
+
+// Projects returns the registered projects.
+func Projects() []Project {
+	return projects
+}
+
+// LookupProject finds a registered project by name.
+func LookupProject(name string) (Project, error) {
+	for _, p := range projects {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return Project{}, fmt.Errorf("unknown project %q (define it under [[agents.projects]])", name)
+}
+
+// ProjectForDir returns the project for spawning an agent in dir: the
+// registered project working there, if any, else one named after the
+// directory that runs DefaultCommand.
+func ProjectForDir(dir string) (Project, error) {
+	abs, err := filepath.Abs(expandHome(dir))
+	if err != nil {
+		return Project{}, err
+	}
+	if fi, err := os.Stat(abs); err != nil {
+		return Project{}, err
+	} else if !fi.IsDir() {
+		return Project{}, fmt.Errorf("%s is not a directory", abs)
+	}
+	for _, p := range projects {
+		if filepath.Clean(expandHome(p.Dir)) == abs {
+			return p, nil
+		}
+	}
+	return Project{Name: filepath.Base(abs), Dir: abs}, nil
+}
+
+// Spec describes an agent session to spawn.
+type Spec struct {
+	Project Project
+	Task    string // initial prompt; empty starts the agent without one
+	Name    string // session name; derived from project and task when empty
+	// Worktree runs the agent in a fresh git worktree on its own branch
+	// instead of the project directory.
+	Worktree bool
+	// Branch is the worktree's branch, checked out if it exists; empty
+	// makes a new agent/<slug> branch.
+	Branch string
+}
+
+// Spawn creates a detached agent session running the project's agent
+// command in its directory, with the task as the initial prompt. The session
+// is stamped with TMUX_NAV_* environment variables and the @agent/@task
+// options so it is recognised as an agent. It returns the session name.
+func Spawn(spec Spec) (string, error) {
+	p := spec.Project
+	name := spec.Name
+	if name == "" {
+		name = uniqueName(FormatName(p.Name, spec.Task))
+	} else if customScheme && !Conforms(name) {
+		return "", fmt.Errorf("session name %q doesn't follow the naming scheme %q (e.g. %q)",
+			name, nameScheme, FormatName(p.Name, spec.Task))
+	}
+	command := p.Command
+	if command == "" {
+		command = DefaultCommand
+	}
+	if spec.Task != "" {
+		command += " " + shellQuote(spec.Task)
+	}
+	command, err := p.Limits.wrap(command)
+	if err != nil {
+		return "", fmt.Errorf("project %s: %w", p.Name, err)
+	}
+
+	env := map[string]string{
+		"TMUX_NAV_AGENT":   "1",
+		"TMUX_NAV_PROJECT": p.Name,
+		"TMUX_NAV_TASK":    spec.Task,
+		"TMUX_NAV_STARTED": time.Now().UTC().Format(time.RFC3339),
+	}
+	for k, v := range p.Env {
+		env[k] = v
+	}
+
+	dir := expandHome(p.Dir)
+	var repo, branch string
+	if spec.Worktree {
+		repo, dir, branch, err = worktreeFor(p, worktreeSlug(name), spec.Branch)
+		if err != nil {
+			return "", err
+		}
+		env["TMUX_NAV_WORKTREE"] = dir
+	}
+
+	err = tmuxclient.NewSession(context.Background(), tmuxclient.NewSessionOptions{
+		Name:    name,
+		Dir:     dir,
+		Command: command,
+		Env:     env,
+	})
+	if err != nil {
+		return "", err
+	}
+	armSupervision(name)
+	_ = tmuxclient.SetOption(context.Background(), name, "@agent", p.Name)
+	if spec.Task != "" {
+		_ = tmuxclient.SetOption(context.Background(), name, "@task", spec.Task)
+	}
+	if spec.Worktree {
+		_ = tmuxclient.SetOption(context.Background(), name, "@worktree", dir)
+		_ = tmuxclient.SetOption(context.Background(), name, "@branch", branch)
+		_ = tmuxclient.SetOption(context.Background(), name, "@repo", repo)
+	}
+	if autoCapture {
+		_ = archive.StartCapture(name)
+	}
+	return name, nil
+}
+
+// uniqueName appends -2, -3, … until no session has the name.
+func uniqueName(base string) string {
+	name := base
+	for i := 2; tmuxclient.HasSession(context.Background(), name); i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	return name
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, path[1:])
+	}
+	return path
+}
+
+// shellQuote single-quotes s for the shell tmux runs commands with.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
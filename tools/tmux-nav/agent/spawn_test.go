@@ -0,0 +1,29 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectForDir(t *testing.T) {
+	api, scratch := filepath.Join(t.TempDir(), "api"), filepath.Join(t.TempDir(), "scratch")
+	for _, d := range []string{api, scratch} {
+		if err := os.Mkdir(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	saved := projects
+	t.Cleanup(func() { projects = saved })
+	RegisterProjects([]Project{{Name: "backend", Dir: api, Command: "claude --model opus"}})
+
+	if p, err := ProjectForDir(api + "/"); err != nil || p.Name != "backend" {
+		t.Errorf("ProjectForDir(api) = %+v, %v; want the registered project", p, err)
+	}
+	if p, err := ProjectForDir(scratch); err != nil || p.Name != "scratch" || p.Dir != scratch || p.Command != "" {
+		t.Errorf("ProjectForDir(scratch) = %+v, %v; want a project named after the directory", p, err)
+	}
+	if _, err := ProjectForDir(filepath.Join(scratch, "missing")); err == nil {
+		t.Error("ProjectForDir(missing) succeeded")
+	}
+}
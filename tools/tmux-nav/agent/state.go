@@ -0,0 +1,187 @@
+package agent
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// State is what an agent session is currently doing, as inferred from its
+// pane content.
+type State int
+
+const (
+	// StateUnknown means the pane didn't match any known pattern.
+	StateUnknown State = iota
+	// StateWorking means the agent is generating or running tools.
+	StateWorking
+	// StateWaiting means the agent is blocked on user input.
+	StateWaiting
+	// StateIdle means the agent finished its turn and shows an empty prompt.
+	StateIdle
+	// StateErrored means the agent hit an API or runtime error.
+	StateErrored
+	// StatePermission means the agent is asking to be allowed to run a tool.
+	StatePermission
+	// StateRateLimited means the API refused the agent with a rate-limit,
+	// overloaded or usage-limit error; it can continue once the window
+	// passes.
+	StateRateLimited
+)
+
+// String returns the short label used in columns and JSON.
+func (s State) String() string {
+	switch s {
+	case StateWorking:
+		return "working"
+	case StateWaiting:
+		return "waiting"
+	case StateIdle:
+		return "idle"
+	case StateErrored:
+		return "error"
+	case StatePermission:
+		return "permission"
+	case StateRateLimited:
+		return "rate-limited"
+	}
+	return "unknown"
+}
+
+// ParseState is the inverse of String; unrecognised labels yield
+// StateUnknown.
+func ParseState(label string) State {
+	for st := StateUnknown; st <= StateRateLimited; st++ {
+		if st.String() == label {
+			return st
+		}
+	}
+	return StateUnknown
+}
+
+// NeedsAttention reports whether a human has to act before the agent can
+// continue.
+func (s State) NeedsAttention() bool {
+	return s == StateWaiting || s == StatePermission
+}
+
+// MarshalText implements encoding.TextMarshaler so states serialise as labels.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// tailLines is how many trailing non-empty lines Classify inspects; Claude
+// Code keeps its status line and prompt at the bottom of the pane.
+const tailLines = 15
+
+// Patterns are checked in order; the first state with a match wins.
+var (
+	workingPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)esc to interrupt`),
+		regexp.MustCompile(`^[✻✽✶✳✢·*] \S.*…`),
+	}
+	permissionPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`Do you want to`),
+		regexp.MustCompile(`❯ \d+\. Yes`),
+	}
+	waitingPatterns = []*regexp.Regexp{
+		yesNoPattern,
+		regexp.MustCompile(`(?i)press enter to continue`),
+		regexp.MustCompile(`(?i)enter to (select|confirm)`),
+	}
+	yesNoPattern      = regexp.MustCompile(`(?i)\((y/n)\)|\[y/N\]|\[Y/n\]`)
+	rateLimitPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)API Error.*(429|529|rate.?limit|overloaded)`),
+		regexp.MustCompile(`rate_limit_error|overloaded_error`),
+		regexp.MustCompile(`(?i)usage limit reached|limit reached.*resets`),
+	}
+	errorPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`API Error`),
+		regexp.MustCompile(`(?i)internal server error`),
+		regexp.MustCompile(`(?i)request timed out`),
+		regexp.MustCompile(`⎿\s+Error:`),
+	}
+	idlePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\? for shortcuts`),
+		regexp.MustCompile(`^│ > \s*│?$`),
+		regexp.MustCompile(`^> \s*$`),
+	}
+)
+
+// Classify infers the agent state from captured pane text (without escape
+// sequences).
+func Classify(content string) State {
+	tail := lastLines(content, tailLines)
+	for _, c := range []struct {
+		state    State
+		patterns []*regexp.Regexp
+	}{
+		{StateWorking, workingPatterns},
+		{StatePermission, permissionPatterns},
+		{StateWaiting, waitingPatterns},
+		{StateRateLimited, rateLimitPatterns},
+		{StateErrored, errorPatterns},
+		{StateIdle, idlePatterns},
+	} {
+		if matchAny(tail, c.patterns) {
+			return c.state
+		}
+	}
+	return StateUnknown
+}
+
+// Detect captures the session's active pane and classifies it.
+func Detect(session string) (State, error) {
+	content, err := tmuxclient.CaptureText(context.Background(), session, 40)
+	if err != nil {
+		return StateUnknown, err
+	}
+	return Classify(content), nil
+}
+
+func matchAny(lines []string, patterns []*regexp.Regexp) bool {
+	for _, l := range lines {
+		for _, p := range patterns {
+			if p.MatchString(l) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lastLines returns up to n trailing non-blank lines, trimmed of padding.
+func lastLines(content string, n int) []string {
+	all := strings.Split(content, "\n")
+	var out []string
+	for i := len(all) - 1; i >= 0 && len(out) < n; i-- {
+		l := strings.TrimSpace(all[i])
+		if l == "" {
+			continue
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+// DetectAll classifies every agent session in sessions, keyed by name.
+// States pushed by Claude Code hooks are used when current, saving a
+// capture. Non-agent sessions and sessions whose capture fails are left out.
+func DetectAll(sessions []tmuxclient.Session) map[string]State {
+	states := make(map[string]State)
+	for _, s := range sessions {
+		if !IsAgent(s) {
+			continue
+		}
+		if st, ok := hookState(s); ok {
+			states[s.Name] = st
+			continue
+		}
+		if st, err := Detect(s.Name); err == nil {
+			states[s.Name] = st
+		}
+	}
+	return states
+}
@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// DefaultStuckAfter is how long a working agent's output may stay unchanged
+// before it is flagged as possibly stuck.
+const DefaultStuckAfter = 10 * time.Minute
+
+// stuckTracker remembers each working agent's last output fingerprint and
+// when it last changed.
+type stuckTracker struct {
+	mu      sync.Mutex
+	after   time.Duration
+	prints  map[string]uint64
+	changed map[string]time.Time
+}
+
+var stuck = &stuckTracker{
+	after:   DefaultStuckAfter,
+	prints:  map[string]uint64{},
+	changed: map[string]time.Time{},
+}
+
+// SetStuckAfter sets the stuck threshold; zero restores the default.
+func SetStuckAfter(d time.Duration) {
+	if d <= 0 {
+		d = DefaultStuckAfter
+	}
+	stuck.mu.Lock()
+	stuck.after = d
+	stuck.mu.Unlock()
+}
+
+// StuckSessions returns the working agents whose output hasn't changed for
+// the stuck threshold. It must be called on every refresh so changes are
+// seen; agents that aren't working are forgotten.
+func StuckSessions(states map[string]State, now time.Time) map[string]bool {
+	stuck.mu.Lock()
+	defer stuck.mu.Unlock()
+
+	out := map[string]bool{}
+	for name := range stuck.prints {
+		if states[name] != StateWorking {
+			delete(stuck.prints, name)
+			delete(stuck.changed, name)
+		}
+	}
+	for name, st := range states {
+		if st != StateWorking {
+			continue
+		}
+		fp, err := fingerprint(name)
+		if err != nil {
+			continue
+		}
+		if prev, ok := stuck.prints[name]; !ok || prev != fp {
+			stuck.prints[name] = fp
+			stuck.changed[name] = now
+			continue
+		}
+		if now.Sub(stuck.changed[name]) >= stuck.after {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+// fingerprint hashes the pane's content, leaving out Claude Code's spinner
+// and status lines, whose elapsed-time counters tick even when nothing
+// else happens.
+func fingerprint(session string) (uint64, error) {
+	content, err := tmuxclient.CaptureText(context.Background(), session, 40)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if matchAny([]string{line}, workingPatterns) {
+			continue
+		}
+		h.Write([]byte(line))
+		h.Write([]byte{'\n'})
+	}
+	return h.Sum64(), nil
+}
+
+// Interrupt stops the agent's current turn (Escape in Claude Code).
+func Interrupt(session string) error {
+	return tmuxclient.SendKeys(context.Background(), tmuxclient.ActivePaneTarget(session), "Escape")
+}
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// DefaultMaxRestarts is how often a crashed agent is respawned before
+// supervision gives up on it.
+const DefaultMaxRestarts = 3
+
+var supervisor = struct {
+	sync.Mutex
+	on          bool
+	maxRestarts int
+	armed       map[string]bool // sessions with remain-on-exit set
+	gaveUp      map[string]bool // sessions past the restart limit
+	unsure      map[string]bool // dead panes seen once without a status
+}{
+	maxRestarts: DefaultMaxRestarts,
+	armed:       map[string]bool{},
+	gaveUp:      map[string]bool{},
+	unsure:      map[string]bool{},
+}
+
+// SetSupervise enables restarting crashed agent sessions, up to
+// maxRestarts times each (DefaultMaxRestarts when zero).
+func SetSupervise(on bool, maxRestarts int) {
+	if maxRestarts <= 0 {
+		maxRestarts = DefaultMaxRestarts
+	}
+	supervisor.Lock()
+	supervisor.on = on
+	supervisor.maxRestarts = maxRestarts
+	supervisor.Unlock()
+}
+
+// armSupervision keeps a freshly spawned agent's pane open on exit so a
+// crash can be restarted, when supervision is enabled.
+func armSupervision(session string) {
+	supervisor.Lock()
+	defer supervisor.Unlock()
+	if supervisor.on && tmuxclient.SetWindowOption(context.Background(), session, "remain-on-exit", "on") == nil {
+		supervisor.armed[session] = true
+	}
+}
+
+// Supervise respawns agent sessions whose pane process died with a failure
+// status, recording each restart in the event log. Agent panes are kept
+// open after exit (remain-on-exit) so their command can be respawned; a
+// clean exit (status 0) is left alone. It does nothing unless supervision
+// is enabled.
+func Supervise(sessions []tmuxclient.Session) {
+	for _, s := range NeedsSupervision(sessions) {
+		SuperviseSession(s)
+	}
+}
+
+// NeedsSupervision returns the agent sessions Supervise would act on: those
+// whose panes aren't kept open on exit yet, and those that died with a
+// failure status. It changes nothing, so callers can order the work with
+// their own operations on each session.
+func NeedsSupervision(sessions []tmuxclient.Session) []tmuxclient.Session {
+	supervisor.Lock()
+	defer supervisor.Unlock()
+	if !supervisor.on {
+		return nil
+	}
+	var need []tmuxclient.Session
+	for _, s := range sessions {
+		if IsAgent(s) && (!supervisor.armed[s.Name] || s.Dead && s.DeadStatus != "0" && !supervisor.gaveUp[s.Name]) {
+			need = append(need, s)
+		}
+	}
+	return need
+}
+
+// SuperviseSession does Supervise's work for one session.
+func SuperviseSession(s tmuxclient.Session) {
+	supervisor.Lock()
+	defer supervisor.Unlock()
+	if !supervisor.on || !IsAgent(s) {
+		return
+	}
+	if !supervisor.armed[s.Name] {
+		if tmuxclient.SetWindowOption(context.Background(), s.Name, "remain-on-exit", "on") == nil {
+			supervisor.armed[s.Name] = true
+		}
+	}
+	if !s.Dead || s.DeadStatus == "0" || supervisor.gaveUp[s.Name] {
+		return
+	}
+	// tmux may report the pane dead before its exit status; give it
+	// one more round before assuming a crash.
+	status := s.DeadStatus
+	if status == "" {
+		if !supervisor.unsure[s.Name] {
+			supervisor.unsure[s.Name] = true
+			return
+		}
+		status = "unknown"
+	}
+	delete(supervisor.unsure, s.Name)
+	if s.Restarts >= supervisor.maxRestarts {
+		supervisor.gaveUp[s.Name] = true
+		_ = eventlog.Append(s.Name, "crash", "exited (status %s); not restarting after %d restarts", status, s.Restarts)
+		return
+	}
+	if err := tmuxclient.RespawnPane(context.Background(), tmuxclient.ActivePaneTarget(s.Name)+s.ActivePane); err != nil {
+		_ = eventlog.Append(s.Name, "crash", "exited (status %s); restart failed: %v", status, err)
+		return
+	}
+	restarts := s.Restarts + 1
+	_ = tmuxclient.SetOption(context.Background(), s.Name, "@restarts", strconv.Itoa(restarts))
+	_ = eventlog.Append(s.Name, "restart", "exited (status %s); restarted (%d/%d)", status, restarts, supervisor.maxRestarts)
+}
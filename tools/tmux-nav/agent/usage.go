@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"path/filepath"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/transcript"
+)
+
+// Transcript returns what the Claude Code transcript belonging to the
+// session's working directory records. ok is false when none exists.
+func Transcript(s tmuxclient.Session) (info transcript.Info, ok bool) {
+	if s.Path == "" {
+		return transcript.Info{}, false
+	}
+	path := transcript.Latest(s.Path)
+	if path == "" {
+		return transcript.Info{}, false
+	}
+	info, err := transcript.Read(path)
+	return info, err == nil
+}
+
+// TranscriptAll returns transcript info for every agent session that has a
+// transcript, keyed by session name.
+func TranscriptAll(sessions []tmuxclient.Session) map[string]transcript.Info {
+	out := make(map[string]transcript.Info)
+	for _, s := range sessions {
+		if !IsAgent(s) {
+			continue
+		}
+		if info, ok := Transcript(s); ok {
+			out[s.Name] = info
+		}
+	}
+	return out
+}
+
+// ProjectOf names the project an agent session belongs to: its @agent tag,
+// else the project in its name under a configured naming scheme, else the
+// repository (or working directory) it runs in.
+func ProjectOf(s tmuxclient.Session) string {
+	project, _, named := ParseName(s.Name)
+	switch {
+	case s.AgentTag != "" && s.AgentTag != "1":
+		return s.AgentTag
+	case customScheme && named && project != "":
+		return project
+	case s.Repo != "":
+		return filepath.Base(s.Repo)
+	case s.Path != "":
+		return filepath.Base(s.Path)
+	}
+	return s.Name
+}
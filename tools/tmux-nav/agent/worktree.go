@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/bjornslib/tmux-nav/git"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// worktreeFor creates a fresh worktree for a task, next to the project's
+// repository in <repo>-worktrees/<slug>, on branch onBranch (agent/<slug>
+// when empty). It returns the repository root, worktree path and branch.
+func worktreeFor(p Project, slug, onBranch string) (repo, path, branch string, err error) {
+	repo, err = git.Toplevel(expandHome(p.Dir))
+	if err != nil {
+		return "", "", "", err
+	}
+	path = filepath.Join(repo+"-worktrees", slug)
+	branch = onBranch
+	if branch == "" {
+		branch = "agent/" + slug
+	}
+	if err := git.AddWorktree(repo, path, branch, ""); err != nil {
+		return "", "", "", err
+	}
+	return repo, path, branch, nil
+}
+
+// Remove kills an agent session and, when it was spawned in a worktree,
+// removes the worktree too, even if the session was gone already.
+// deleteBranch also deletes the agent's branch; force discards
+// uncommitted changes and unmerged work.
+func Remove(s tmuxclient.Session, deleteBranch, force bool) error {
+	if err := Kill(s.Name); err != nil && !errors.Is(err, tmuxclient.ErrSessionNotFound) {
+		return fmt.Errorf("kill %s: %w", s.Name, err)
+	}
+	if s.Worktree == "" {
+		return nil
+	}
+	repo := s.Repo
+	if repo == "" {
+		repo = s.Worktree
+	}
+	var errs []error
+	if err := git.RemoveWorktree(repo, s.Worktree, force); err != nil {
+		errs = append(errs, err)
+	}
+	if deleteBranch && s.Branch != "" {
+		if err := git.DeleteBranch(repo, s.Branch, force); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// worktreeSlug turns a session name into a directory/branch-safe slug.
+func worktreeSlug(session string) string {
+	if project, task, ok := ParseName(session); ok && project != "" {
+		return project + "-" + task
+	} else if ok {
+		return task
+	}
+	return slug(session, 0)
+}
@@ -0,0 +1,44 @@
+// Package archive keeps a durable record of agent sessions' output: live
+// pane logs captured through pipe-pane, with size-based rotation, and
+// transcripts of every pane's scrollback saved on demand.
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// DataDir returns tmux-nav's data directory
+// ($XDG_DATA_HOME/tmux-nav, default ~/.local/share/tmux-nav).
+func DataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "tmux-nav")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "share", "tmux-nav")
+}
+
+// LogDir returns the directory holding a session's captured pane logs.
+func LogDir(session string) string {
+	return filepath.Join(DataDir(), "logs", safeName(session))
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// safeName makes a session name usable as a single path component.
+func safeName(session string) string {
+	return unsafeName.ReplaceAllString(session, "_")
+}
+
+// ArchiveDir returns the directory holding archived agent sessions.
+func ArchiveDir() string {
+	return filepath.Join(DataDir(), "archive")
+}
+
+// SessionDir returns a fresh archive directory name for session, stamped
+// with t.
+func SessionDir(session string, t time.Time) string {
+	return filepath.Join(ArchiveDir(), safeName(session)+"-"+t.Format("20060102-150405"))
+}
@@ -0,0 +1,122 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// Rotation limits for captured pane logs.
+const (
+	DefaultMaxBytes = 10 << 20 // rotate after 10 MiB
+	DefaultKeep     = 10       // files kept per session
+)
+
+// StartCapture pipes the session's active pane into `tmux-nav
+// capture-writer`, which appends to rotating log files under LogDir. It is a
+// no-op when the pane is already being piped.
+func StartCapture(session string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("%s capture-writer --session %s", shellQuote(self), shellQuote(session))
+	// -o only opens a pipe if none exists, so repeated calls are harmless.
+	if out, err := exec.Command("tmux", "pipe-pane", "-o", "-t", tmuxclient.ActivePaneTarget(session), cmd).CombinedOutput(); err != nil {
+		return fmt.Errorf("pipe-pane: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return tmuxclient.SetOption(context.Background(), session, "@capture", "1")
+}
+
+// StopCapture closes the session's pipe-pane, if any.
+func StopCapture(session string) error {
+	if err := exec.Command("tmux", "pipe-pane", "-t", tmuxclient.ActivePaneTarget(session)).Run(); err != nil {
+		return fmt.Errorf("pipe-pane: %w", err)
+	}
+	return tmuxclient.UnsetOption(context.Background(), session, "@capture")
+}
+
+// RotatingWriter appends to timestamped files in a directory, starting a
+// new file once the current one exceeds maxBytes and deleting the oldest
+// files beyond keep.
+type RotatingWriter struct {
+	dir      string
+	maxBytes int64
+	keep     int
+	f        *os.File
+	size     int64
+}
+
+// NewRotatingWriter creates dir if needed and opens a fresh log file.
+func NewRotatingWriter(dir string, maxBytes int64, keep int) (*RotatingWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	w := &RotatingWriter{dir: dir, maxBytes: maxBytes, keep: keep}
+	return w, w.rotate()
+}
+
+// Write implements io.Writer.
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	if w.size+int64(len(p)) > w.maxBytes && w.size > 0 {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file.
+func (w *RotatingWriter) Close() error {
+	return w.f.Close()
+}
+
+func (w *RotatingWriter) rotate() error {
+	if w.f != nil {
+		w.f.Close()
+	}
+	name := time.Now().Format("20060102-150405.000") + ".log"
+	f, err := os.OpenFile(filepath.Join(w.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	w.f, w.size = f, 0
+	w.prune()
+	return nil
+}
+
+// prune deletes the oldest log files beyond the keep limit.
+func (w *RotatingWriter) prune() {
+	files, _ := filepath.Glob(filepath.Join(w.dir, "*.log"))
+	sort.Strings(files) // timestamped names sort chronologically
+	for len(files) > w.keep {
+		os.Remove(files[0])
+		files = files[1:]
+	}
+}
+
+// CopyToLogs copies r (pane output from pipe-pane) into the session's
+// rotating log files until r is closed.
+func CopyToLogs(session string, r io.Reader) error {
+	w, err := NewRotatingWriter(LogDir(session), DefaultMaxBytes, DefaultKeep)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,65 @@
+package archive_test
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/archive"
+)
+
+// logs returns the contents of dir's log files, oldest first.
+func logs(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	var out []string
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, string(b))
+	}
+	return out
+}
+
+func TestRotatingWriter(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	w, err := archive.NewRotatingWriter(dir, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	for _, s := range []string{"12345678", "abcd", "ef", "0123456789AB"} {
+		// Log files are named by the millisecond they were opened.
+		time.Sleep(2 * time.Millisecond)
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := logs(t, dir)
+	if want := []string{"abcdef", "0123456789AB"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("logs = %q, want %q: a new file once 10 bytes would be exceeded, the oldest pruned beyond 2", got, want)
+	}
+}
+
+func TestCopyToLogs(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir := archive.LogDir("api/refactor: 2")
+	if want := filepath.Join(os.Getenv("XDG_DATA_HOME"), "tmux-nav", "logs", "api_refactor_2"); dir != want {
+		t.Errorf("LogDir = %s, want %s", dir, want)
+	}
+	if err := archive.CopyToLogs("api/refactor: 2", strings.NewReader("$ go test\nok\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := logs(t, dir); len(got) != 1 || got[0] != "$ go test\nok\n" {
+		t.Errorf("logs = %q, want the pane output in one file", got)
+	}
+}
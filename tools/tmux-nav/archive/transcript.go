@@ -0,0 +1,66 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// TranscriptDir returns the directory holding a session's saved
+// transcripts.
+func TranscriptDir(session string) string {
+	return filepath.Join(DataDir(), "transcripts", safeName(session))
+}
+
+// SaveTranscripts writes the whole scrollback of every pane of session to
+// TranscriptDir, one file per pane named <time>-<window>.<pane>.log, and
+// returns the files written.
+func SaveTranscripts(session string, t time.Time) ([]string, error) {
+	ctx := context.Background()
+	windows, err := tmuxclient.ListWindows(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	dir := TranscriptDir(session)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, w := range windows {
+		panes, err := tmuxclient.ListPanes(ctx, session, w.Index)
+		if err != nil {
+			return files, err
+		}
+		for _, p := range panes {
+			path := filepath.Join(dir, fmt.Sprintf("%s-%d.%d.log", t.Format("20060102-150405"), w.Index, p.Index))
+			if err := savePane(ctx, session, w.Index, p.Index, path); err != nil {
+				return files, err
+			}
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// savePane streams one pane's scrollback into the file dst.
+func savePane(ctx context.Context, session string, window, pane int, dst string) error {
+	r, err := tmuxclient.StreamPaneHistory(ctx, session, window, pane)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,308 @@
+package attach
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// IsInsideTmux returns true when the process is running inside a tmux session.
+func IsInsideTmux() bool {
+	return os.Getenv("TMUX") != ""
+}
+
+// IsITerm2 returns true when the terminal emulator is iTerm2.
+func IsITerm2() bool {
+	return os.Getenv("TERM_PROGRAM") == "iTerm.app"
+}
+
+// Strategy describes how to attach to a session.
+type Strategy int
+
+const (
+	// SameWindowCC attaches in-place via tmux CC mode (iTerm2 + inside tmux).
+	SameWindowCC Strategy = iota
+	// SwitchClient switches the tmux client (inside tmux, not iTerm2).
+	SwitchClient
+	// NewTabCC opens a new iTerm2 tab then attaches via CC mode.
+	NewTabCC
+	// PlainAttach falls back to a regular tmux attach in the same terminal.
+	PlainAttach
+	// NewTerminal opens a new terminal window (Linux desktop, outside tmux)
+	// using the command template in TMUX_NAV_TERMINAL.
+	NewTerminal
+	// NewTabGnome opens a new GNOME Terminal tab and attaches there.
+	NewTabGnome
+	// NewTabKonsole opens a new Konsole tab (via qdbus) and attaches there.
+	NewTabKonsole
+	// NewTabWindowsTerminal opens a new Windows Terminal tab (under WSL)
+	// and attaches there.
+	NewTabWindowsTerminal
+	// NewWindowCC opens a new iTerm2 window then attaches via CC mode, for
+	// watching several sessions side by side.
+	NewWindowCC
+)
+
+// DetectStrategy picks the best attachment strategy for the current environment.
+func DetectStrategy() Strategy {
+	insideTmux := IsInsideTmux()
+	isITerm := IsITerm2()
+
+	switch {
+	case insideTmux && isITerm:
+		return SameWindowCC
+	case insideTmux && !isITerm:
+		return SwitchClient
+	case !insideTmux && isITerm:
+		return NewTabCC
+	case !insideTmux && IsGnomeTerminal():
+		return NewTabGnome
+	case !insideTmux && IsKonsole():
+		return NewTabKonsole
+	case !insideTmux && IsWindowsTerminal():
+		return NewTabWindowsTerminal
+	case IsLinuxDesktop() && terminalTemplate() != "":
+		return NewTerminal
+	default:
+		return PlainAttach
+	}
+}
+
+// Options tweaks how Attach connects to a session, independent of strategy.
+type Options struct {
+	// DetachOthers detaches every other client from the target session so
+	// the new client is the only one attached (like `tmux attach -d`).
+	DetachOthers bool
+	// Window and Pane, when set, are selected right after attaching so the
+	// client lands on that window/pane instead of the session's active one.
+	Window string
+	Pane   string
+}
+
+// selectArgs returns the tmux commands (chained with ";") that select the
+// requested window and pane once attached. Empty when none was requested.
+func (o Options) selectArgs(session string) []string {
+	t := tmuxclient.Target{Session: session, Window: o.Window, Pane: o.Pane}
+	var args []string
+	if w := t.WindowTarget(); w != "" {
+		args = append(args, ";", "select-window", "-t", w)
+	}
+	if p := t.PaneTarget(); p != "" {
+		args = append(args, ";", "select-pane", "-t", p)
+	}
+	return args
+}
+
+// Attach attaches to `session` using the appropriate strategy.
+// For strategies that exec-replace the process (SameWindowCC, PlainAttach)
+// this function does not return on success; SwitchClient returns once the
+// client has switched.
+func Attach(session string, strategy Strategy, opts Options) error {
+	switch strategy {
+	case SameWindowCC:
+		return execReplace("tmux", attachArgs(session, opts, "-CC")...)
+
+	case SwitchClient:
+		if opts.DetachOthers {
+			detachOthers(session)
+		}
+		args := append([]string{"switch-client", "-t", tmuxclient.ExactTarget(session)}, opts.selectArgs(session)...)
+		return exec.Command("tmux", args...).Run()
+
+	case NewTabCC:
+		return openNewITerm2Tab(session, opts)
+
+	case NewWindowCC:
+		return openNewITerm2Window(session, opts)
+
+	case PlainAttach:
+		return execReplace("tmux", attachArgs(session, opts)...)
+
+	case NewTerminal:
+		return openNewTerminal(session, opts)
+
+	case NewTabGnome:
+		return openNewGnomeTab(session, opts)
+
+	case NewTabKonsole:
+		return openNewKonsoleTab(session, opts)
+
+	case NewTabWindowsTerminal:
+		return openNewWindowsTerminalTab(session, opts)
+	}
+	if t, ok := lookupTemplate(strategy); ok {
+		return runTemplate(t, session, opts)
+	}
+	return fmt.Errorf("unknown strategy %d", strategy)
+}
+
+// detachOthers detaches the clients attached to session other than the
+// one we run in, which may already be among them. (detach-client -s would
+// take ours along, and -a reaches clients of every session.)
+func detachOthers(session string) {
+	self, _ := exec.Command("tmux", "display-message", "-p", "#{client_tty}").Output()
+	out, err := exec.Command("tmux", "list-clients", "-t", tmuxclient.ExactTarget(session), "-F", "#{client_tty}").Output()
+	if err != nil {
+		return
+	}
+	for _, tty := range strings.Fields(string(out)) {
+		if tty != strings.TrimSpace(string(self)) {
+			_ = exec.Command("tmux", "detach-client", "-t", tty).Run()
+		}
+	}
+}
+
+// attachArgs builds the argv (minus the tmux binary) for `tmux attach`,
+// with any global flags (e.g. -CC) placed before the command.
+func attachArgs(session string, opts Options, global ...string) []string {
+	args := append(global, "attach")
+	if opts.DetachOthers {
+		args = append(args, "-d")
+	}
+	args = append(args, "-t", tmuxclient.ExactTarget(session))
+	return append(args, opts.selectArgs(session)...)
+}
+
+// Strategies lists every attach strategy: built-ins in declaration order,
+// then registered templates.
+func Strategies() []Strategy {
+	all := []Strategy{SameWindowCC, SwitchClient, NewTabCC, PlainAttach, NewTerminal, NewTabGnome, NewTabKonsole, NewTabWindowsTerminal, NewWindowCC}
+	for i := range templates {
+		all = append(all, firstTemplate+Strategy(i))
+	}
+	return all
+}
+
+// CommandLine returns a shell command that performs the attach by hand, for
+// users who want to copy and run it themselves.
+func CommandLine(session string, strategy Strategy, opts Options) string {
+	switch strategy {
+	case SameWindowCC, NewTabCC, NewWindowCC:
+		return shellJoin(append([]string{"tmux"}, attachArgs(session, opts, "-CC")...))
+	case SwitchClient:
+		args := append([]string{"tmux", "switch-client", "-t", tmuxclient.ExactTarget(session)}, opts.selectArgs(session)...)
+		return shellJoin(args)
+	}
+	if t, ok := lookupTemplate(strategy); ok {
+		if line, err := renderAttach(t.Cmd, session, opts); err == nil {
+			return line
+		}
+	}
+	return shellJoin(append([]string{"tmux"}, attachArgs(session, opts)...))
+}
+
+// StrategyLabel returns a human-readable description of the strategy.
+func StrategyLabel(s Strategy) string {
+	switch s {
+	case SameWindowCC:
+		return "attach (iTerm2 CC, same window)"
+	case SwitchClient:
+		return "switch-client (inside tmux)"
+	case NewTabCC:
+		return "open new iTerm2 tab"
+	case PlainAttach:
+		return "attach (plain tmux)"
+	case NewTerminal:
+		return "open new terminal window"
+	case NewTabGnome:
+		return "open new GNOME Terminal tab"
+	case NewTabKonsole:
+		return "open new Konsole tab"
+	case NewTabWindowsTerminal:
+		return "open new Windows Terminal tab"
+	case NewWindowCC:
+		return "open new iTerm2 window"
+	}
+	if t, ok := lookupTemplate(s); ok {
+		return "template: " + t.Name
+	}
+	return "attach"
+}
+
+// itermProfile is the iTerm2 profile new tabs and windows open with.
+var itermProfile string
+
+// SetITermProfile sets the iTerm2 profile the NewTabCC and NewWindowCC
+// strategies open with, e.g. one with its own colours for agents. Empty
+// means the default profile.
+func SetITermProfile(name string) {
+	itermProfile = strings.TrimSpace(name)
+}
+
+// profileClause is the AppleScript naming the profile to create with.
+func profileClause() string {
+	if itermProfile == "" {
+		return "default profile"
+	}
+	return `profile "` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(itermProfile) + `"`
+}
+
+// openNewITerm2Tab uses AppleScript to open a new iTerm2 tab and attach.
+func openNewITerm2Tab(session string, opts Options) error {
+	return runITerm2Script(`
+tell application "iTerm2"
+  tell current window
+    create tab with %[1]s
+    tell current session
+      write text "%[2]s"
+    end tell
+  end tell
+end tell
+`, session, opts)
+}
+
+// openNewITerm2Window uses AppleScript to open a new iTerm2 window and
+// attach.
+func openNewITerm2Window(session string, opts Options) error {
+	return runITerm2Script(`
+tell application "iTerm2"
+  set w to (create window with %[1]s)
+  tell current session of w
+    write text "%[2]s"
+  end tell
+end tell
+`, session, opts)
+}
+
+// runITerm2Script runs an AppleScript that creates a tab or window with
+// the profile at %[1]s and types the `tmux -CC attach` command for
+// session at %[2]s.
+func runITerm2Script(script, session string, opts Options) error {
+	// Escape single quotes in session name for shell safety.
+	safe := strings.ReplaceAll(tmuxclient.ExactTarget(session), "'", `'"'"'`)
+	detach := ""
+	if opts.DetachOthers {
+		detach = "-d "
+	}
+	// Window/pane selection is chained onto the attach; the ";" separators
+	// must be escaped from the shell that runs the command.
+	var sel strings.Builder
+	for _, a := range opts.selectArgs(session) {
+		if a == ";" {
+			sel.WriteString(` \\;`)
+			continue
+		}
+		sel.WriteString(" '" + strings.ReplaceAll(a, "'", `'"'"'`) + "'")
+	}
+	attachCmd := fmt.Sprintf("tmux -CC attach %s-t '%s'%s", detach, safe, sel.String())
+	cmd := exec.Command("osascript", "-e", fmt.Sprintf(script, profileClause(), attachCmd))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("osascript: %w\n%s", err, out)
+	}
+	return nil
+}
+
+// execReplace replaces the current process with the given command (Unix exec).
+// On non-Unix systems this falls through to a regular Run().
+func execReplace(name string, args ...string) error {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return err
+	}
+	// syscall.Exec replaces the process image; use os/exec.Cmd on unsupported OSes.
+	return syscallExec(path, append([]string{name}, args...), os.Environ())
+}
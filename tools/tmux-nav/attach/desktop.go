@@ -0,0 +1,93 @@
+package attach
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/platform"
+)
+
+// IsGnomeTerminal returns true when running inside GNOME Terminal.
+func IsGnomeTerminal() bool {
+	return os.Getenv("GNOME_TERMINAL_SCREEN") != "" || os.Getenv("GNOME_TERMINAL_SERVICE") != ""
+}
+
+// IsKonsole returns true when running inside KDE Konsole.
+func IsKonsole() bool {
+	return os.Getenv("KONSOLE_DBUS_SERVICE") != "" || os.Getenv("KONSOLE_VERSION") != ""
+}
+
+// IsWindowsTerminal returns true when running under WSL inside Windows
+// Terminal.
+func IsWindowsTerminal() bool {
+	return platform.Current().Kind == platform.WSL && os.Getenv("WT_SESSION") != ""
+}
+
+// openNewGnomeTab opens a new GNOME Terminal tab running the attach.
+func openNewGnomeTab(session string, opts Options) error {
+	args := append([]string{"--tab", "--", "tmux"}, attachArgs(session, opts)...)
+	out, err := exec.Command("gnome-terminal", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("gnome-terminal: %w\n%s", err, out)
+	}
+	return nil
+}
+
+// openNewKonsoleTab opens a new Konsole tab over D-Bus and runs the attach
+// in it. When D-Bus is unavailable it falls back to `konsole --new-tab`.
+func openNewKonsoleTab(session string, opts Options) error {
+	argv := append([]string{"tmux"}, attachArgs(session, opts)...)
+
+	service := os.Getenv("KONSOLE_DBUS_SERVICE")
+	window := os.Getenv("KONSOLE_DBUS_WINDOW")
+	qdbus := findQdbus()
+	if service == "" || window == "" || qdbus == "" {
+		args := append([]string{"--new-tab", "-e"}, argv...)
+		out, err := exec.Command("konsole", args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("konsole: %w\n%s", err, out)
+		}
+		return nil
+	}
+
+	out, err := exec.Command(qdbus, service, window, "newSession").Output()
+	if err != nil {
+		return fmt.Errorf("konsole newSession: %w", err)
+	}
+	id := strings.TrimSpace(string(out))
+	out, err = exec.Command(qdbus, service, "/Sessions/"+id, "runCommand", shellJoin(argv)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("konsole runCommand: %w\n%s", err, out)
+	}
+	return nil
+}
+
+// openNewWindowsTerminalTab opens a new tab in the current Windows Terminal
+// window running the attach in this WSL distribution.
+func openNewWindowsTerminalTab(session string, opts Options) error {
+	args := []string{"-w", "0", "new-tab", "wsl.exe", "-d", os.Getenv("WSL_DISTRO_NAME"), "--", "tmux"}
+	for _, a := range attachArgs(session, opts) {
+		if a == ";" {
+			a = `\;` // wt.exe splits its own commands on bare semicolons
+		}
+		args = append(args, a)
+	}
+	out, err := exec.Command("wt.exe", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("wt.exe: %w\n%s", err, out)
+	}
+	return nil
+}
+
+// findQdbus returns the first available qdbus binary (names vary across
+// Qt versions and distributions), or "" when none is installed.
+func findQdbus() string {
+	for _, name := range []string{"qdbus", "qdbus6", "qdbus-qt6", "qdbus-qt5"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	return ""
+}
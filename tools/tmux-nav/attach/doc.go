@@ -0,0 +1,11 @@
+// Package attach attaches a terminal to a tmux session, choosing how from
+// the environment: switching the client inside tmux, opening an iTerm2 or
+// desktop terminal tab, or taking over the current terminal.
+//
+//	s := attach.DetectStrategy()
+//	err := attach.Attach("api", s, attach.Options{Window: "1"})
+//
+// Strategies can be extended with shell command templates (see
+// RegisterTemplates). Part of the module's stable API; see package
+// tmuxclient.
+package attach
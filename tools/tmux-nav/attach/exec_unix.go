@@ -1,6 +1,6 @@
 //go:build !windows
 
-package iterm2
+package attach
 
 import "syscall"
 
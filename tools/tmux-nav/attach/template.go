@@ -0,0 +1,117 @@
+package attach
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// Template is a user-defined attach strategy: a shell command template
+// rendered with templateVars, e.g. `ssh -t jump {{q .Cmd}}`.
+type Template struct {
+	Name string
+	Cmd  string
+	// Background starts the command and returns immediately instead of
+	// handing the terminal over to it.
+	Background bool
+}
+
+// firstTemplate is the Strategy value of the first registered
+// template; templates are numbered consecutively from there.
+const firstTemplate Strategy = 100
+
+var templates []Template
+
+// RegisterTemplates installs user-defined strategies, replacing any
+// previously registered ones. They become selectable by name and appear in
+// Strategies() after the built-ins.
+func RegisterTemplates(ts []Template) {
+	templates = append([]Template(nil), ts...)
+}
+
+// lookupTemplate returns the template behind strategy s, if it is one.
+func lookupTemplate(s Strategy) (Template, bool) {
+	i := int(s - firstTemplate)
+	if i < 0 || i >= len(templates) {
+		return Template{}, false
+	}
+	return templates[i], true
+}
+
+// builtinNames maps the stable names of built-in strategies.
+var builtinNames = map[Strategy]string{
+	SameWindowCC:          "same-window",
+	SwitchClient:          "switch",
+	NewTabCC:              "new-tab",
+	PlainAttach:           "plain",
+	NewTerminal:           "new-terminal",
+	NewTabGnome:           "gnome-tab",
+	NewTabKonsole:         "konsole-tab",
+	NewTabWindowsTerminal: "wt-tab",
+	NewWindowCC:           "new-window",
+}
+
+// StrategyName returns the name used to select s in config and flags.
+func StrategyName(s Strategy) string {
+	if t, ok := lookupTemplate(s); ok {
+		return t.Name
+	}
+	return builtinNames[s]
+}
+
+// ParseStrategy looks up a strategy by name, built-in or template.
+func ParseStrategy(name string) (Strategy, error) {
+	for s, n := range builtinNames {
+		if n == name {
+			return s, nil
+		}
+	}
+	for i, t := range templates {
+		if t.Name == name {
+			return firstTemplate + Strategy(i), nil
+		}
+	}
+	var names []string
+	for _, s := range Strategies() {
+		names = append(names, StrategyName(s))
+	}
+	return 0, fmt.Errorf("unknown attach strategy %q (want one of %s)", name, strings.Join(names, ", "))
+}
+
+// runTemplate renders t for the target and runs it through `sh -c`.
+func runTemplate(t Template, session string, opts Options) error {
+	line, err := renderAttach(t.Cmd, session, opts)
+	if err != nil {
+		return fmt.Errorf("strategy %s: %w", t.Name, err)
+	}
+	if !t.Background {
+		return execReplace("sh", "-c", line)
+	}
+	cmd := exec.Command("sh", "-c", line)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("strategy %s: %w", t.Name, err)
+	}
+	return cmd.Process.Release()
+}
+
+// newTemplateVars collects the fields available to command templates.
+// Unset fields stay empty, so {{if .Window}} still works.
+func newTemplateVars(session string, opts Options) templateVars {
+	quote := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return shellQuote(s)
+	}
+	return templateVars{
+		Session: shellQuote(session),
+		Window:  quote(opts.Window),
+		Pane:    quote(opts.Pane),
+		Target:  shellQuote(tmuxclient.Target{Session: session, Window: opts.Window, Pane: opts.Pane}.String()),
+		Cmd:     shellJoin(append([]string{"tmux"}, attachArgs(session, opts)...)),
+	}
+}
@@ -0,0 +1,57 @@
+package attach
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateQuotesFields(t *testing.T) {
+	dir := t.TempDir()
+	const name = "a b;touch x"
+	for _, tmpl := range []string{
+		"printf '%s\\n' {{.Session}} {{.Target}}",
+		"sh -c {{q .Cmd}}",
+	} {
+		line, err := renderAttach(tmpl, name, Options{Window: "$(touch x)"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(tmpl, "sh -c") {
+			// Stand in for tmux: the inner shell prints the argv it was given.
+			line = strings.Replace(line, "'tmux ", "'printf %s\\\\n ", 1)
+		}
+		cmd := exec.Command("sh", "-c", line)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "x")); err == nil {
+			t.Fatalf("%s ran the session name:\n%s", line, out)
+		}
+		if !strings.Contains(string(out), name) {
+			t.Errorf("%s printed %q, want the name as one word", line, out)
+		}
+	}
+}
+
+func TestTemplateRefusesDroppedOptions(t *testing.T) {
+	for _, c := range []struct {
+		tmpl string
+		opts Options
+		ok   bool
+	}{
+		{"ssh -t jump tmux attach -t {{.Session}}", Options{}, true},
+		{"ssh -t jump tmux attach -t {{.Session}}", Options{DetachOthers: true}, false},
+		{"ssh -t jump tmux attach -t {{.Session}}", Options{Window: "2"}, false},
+		{"ssh -t jump tmux attach -t {{.Target}}", Options{Window: "2"}, true},
+		{"ssh -t jump {{q .Cmd}}", Options{DetachOthers: true, Pane: "1"}, true},
+	} {
+		if _, err := renderAttach(c.tmpl, "api", c.opts); (err == nil) != c.ok {
+			t.Errorf("renderAttach(%q, %+v) error = %v, want ok %v", c.tmpl, c.opts, err, c.ok)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package attach
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/bjornslib/tmux-nav/platform"
+)
+
+// TerminalEnv names the environment variable the tmux-nav command reads the
+// terminal template from, overriding attach.terminal in its config.
+const TerminalEnv = "TMUX_NAV_TERMINAL"
+
+// terminal is the command template used by the NewTerminal strategy.
+var terminal string
+
+// SetTerminal sets the command template the NewTerminal strategy opens,
+// e.g. `foot -e {{.Cmd}}` or `x-terminal-emulator -e {{.Cmd}}`. The
+// template is rendered with templateVars and run through `sh -c`.
+func SetTerminal(tmpl string) {
+	terminal = strings.TrimSpace(tmpl)
+}
+
+// templateVars is the data available to command templates (the terminal
+// template and user-defined strategies). Every field is shell-quoted, so
+// a session named `a b;touch x` stays one word; `{{q .Cmd}}` quotes a
+// field again for a command that hands it to another shell, like ssh.
+type templateVars struct {
+	Session string // target session name
+	Window  string // requested window, if any
+	Pane    string // requested pane, if any
+	Target  string // session[:window[.pane]]
+	Cmd     string // full tmux attach command line
+}
+
+// IsLinuxDesktop returns true on Linux (including WSLg) with an X11 or
+// Wayland display, where spawning a new terminal window is possible.
+func IsLinuxDesktop() bool {
+	p := platform.Current()
+	return p.Kind != platform.MacOS && p.Display != ""
+}
+
+// terminalTemplate returns the configured terminal command template, or ""
+// when none is set.
+func terminalTemplate() string {
+	return terminal
+}
+
+// openNewTerminal renders the terminal template for `session` and starts it
+// in the background, leaving the current process untouched.
+func openNewTerminal(session string, opts Options) error {
+	tmpl := terminalTemplate()
+	if tmpl == "" {
+		return fmt.Errorf("no terminal configured: set attach.terminal or %s (e.g. 'foot -e {{.Cmd}}')", TerminalEnv)
+	}
+	line, err := renderAttach(tmpl, session, opts)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("sh", "-c", line)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("spawn terminal: %w", err)
+	}
+	// The terminal outlives us; don't wait for it.
+	return cmd.Process.Release()
+}
+
+// renderAttach renders a command template attaching to session. A
+// template that leaves out {{.Cmd}} can't pass on -d, nor the window and
+// pane without {{.Target}}, {{.Window}} or {{.Pane}}, so it is refused
+// when opts asks for them rather than attaching without.
+func renderAttach(tmpl, session string, opts Options) (string, error) {
+	used, err := renderCommand(tmpl, templateVars{Session: "@Session@", Window: "@Window@", Pane: "@Pane@", Target: "@Target@", Cmd: "@Cmd@"})
+	if err != nil {
+		return "", err
+	}
+	uses := func(fields ...string) bool {
+		return slices.ContainsFunc(fields, func(f string) bool { return strings.Contains(used, "@"+f+"@") })
+	}
+	switch {
+	case opts.DetachOthers && !uses("Cmd"):
+		return "", fmt.Errorf("command template: detaching other clients needs {{.Cmd}}")
+	case (opts.Window != "" || opts.Pane != "") && !uses("Cmd", "Target", "Window", "Pane"):
+		return "", fmt.Errorf("command template: selecting a window or pane needs {{.Cmd}} or {{.Target}}")
+	}
+	return renderCommand(tmpl, newTemplateVars(session, opts))
+}
+
+// renderCommand executes a command template.
+func renderCommand(tmpl string, vars templateVars) (string, error) {
+	t, err := template.New("cmd").Option("missingkey=error").Funcs(template.FuncMap{"q": shellQuote}).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("command template: %w", err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, vars); err != nil {
+		return "", fmt.Errorf("command template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// shellJoin quotes argv for a POSIX shell. A bare ";" (tmux command
+// separator) is escaped so the shell passes it through to tmux.
+func shellJoin(argv []string) string {
+	parts := make([]string, len(argv))
+	for i, a := range argv {
+		parts[i] = shellQuote(a)
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote single-quotes s unless it is made only of safe characters.
+func shellQuote(s string) string {
+	if s == ";" {
+		return `\;`
+	}
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:@%+,") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
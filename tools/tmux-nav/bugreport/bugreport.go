@@ -0,0 +1,127 @@
+// Package bugreport writes debug bundles: a directory with everything
+// needed to report a crash — the panic and its stack, a snapshot of the
+// navigator's state, the tmux server's sessions, windows and panes, the
+// recent event log and the versions involved.
+package bugreport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/platform"
+	"github.com/bjornslib/tmux-nav/trace"
+)
+
+// Report is a crash to bundle.
+type Report struct {
+	Panic  any
+	Stack  []byte
+	During string // what was running, e.g. "Update tea.KeyMsg"
+	State  string // snapshot of the program's state
+}
+
+// file is one file of the bundle.
+type file struct{ name, body string }
+
+// tmuxTimeout bounds each tmux command collected; a wedged server must not
+// hang the crash handler.
+const tmuxTimeout = 2 * time.Second
+
+// Write writes the bundle for r to a fresh temporary directory and returns
+// its path. Parts that can't be collected are noted in their files rather
+// than failing the bundle.
+func Write(r Report) (string, error) {
+	dir, err := os.MkdirTemp("", "tmux-nav-crash-")
+	if err != nil {
+		return "", err
+	}
+	files := []file{
+		{"panic.txt", fmt.Sprintf("panic: %v\nduring: %s\ntime: %s\n\n%s", r.Panic, r.During, time.Now().Format(time.RFC3339), r.Stack)},
+		{"state.txt", r.State},
+		{"versions.txt", versions()},
+		{"tmux.txt", tmuxSnapshot()},
+		{"events.jsonl", events()},
+	}
+	if trace.Enabled() {
+		var sb strings.Builder
+		for _, s := range trace.Summary() {
+			sb.WriteString(s.String() + "\n")
+		}
+		files = append(files, file{"trace.txt", sb.String()})
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.name), []byte(f.body), 0o600); err != nil {
+			return dir, err
+		}
+	}
+	return dir, nil
+}
+
+// versions lists tmux-nav's build, the Go runtime, the platform, the
+// terminal and tmux.
+func versions() string {
+	var sb strings.Builder
+	version, revision := "unknown", ""
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version = info.Main.Version
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				revision = " (" + s.Value + ")"
+			}
+		}
+	}
+	fmt.Fprintf(&sb, "tmux-nav  %s%s\n", version, revision)
+	fmt.Fprintf(&sb, "go        %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&sb, "platform  %s\n", platform.Current())
+	fmt.Fprintf(&sb, "terminal  TERM=%s TERM_PROGRAM=%s\n", os.Getenv("TERM"), os.Getenv("TERM_PROGRAM"))
+	fmt.Fprintf(&sb, "tmux      %s", tmuxOutput("-V"))
+	return sb.String()
+}
+
+// tmuxSnapshot lists the server's sessions, windows and panes.
+func tmuxSnapshot() string {
+	var sb strings.Builder
+	for _, args := range [][]string{
+		{"list-sessions"},
+		{"list-windows", "-a"},
+		{"list-panes", "-a", "-F", "#{session_name}:#{window_index}.#{pane_index} #{pane_current_command} #{pane_width}x#{pane_height} dead=#{pane_dead}"},
+	} {
+		fmt.Fprintf(&sb, "$ tmux %s\n%s\n", args[0], tmuxOutput(args...))
+	}
+	return sb.String()
+}
+
+// tmuxOutput runs tmux directly, not through tmuxclient: its runner may be
+// a control-mode connection to a server that is part of the problem.
+func tmuxOutput(args ...string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), tmuxTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "tmux", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Sprintf("%s(%v)\n", out, err)
+	}
+	return string(out)
+}
+
+// events returns the tail of the event log as JSON lines.
+func events() string {
+	entries, err := eventlog.Tail(200)
+	if err != nil {
+		return fmt.Sprintf("(%v)\n", err)
+	}
+	var sb strings.Builder
+	for _, e := range entries {
+		line, _ := json.Marshal(e)
+		sb.Write(append(line, '\n'))
+	}
+	return sb.String()
+}
@@ -0,0 +1,39 @@
+package bugreport_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/bugreport"
+	"github.com/bjornslib/tmux-nav/eventlog"
+)
+
+func TestWrite(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("TMUX_TMPDIR", t.TempDir()) // no server: its output is noted, not fatal
+	eventlog.Append("api", "restart", "restarted")
+
+	dir, err := bugreport.Write(bugreport.Report{Panic: "boom", Stack: []byte("goroutine 1"), During: "View", State: "cursor 0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"panic.txt":    "panic: boom\nduring: View",
+		"state.txt":    "cursor 0",
+		"versions.txt": "go ",
+		"tmux.txt":     "$ tmux list-panes",
+		"events.jsonl": `"restarted"`,
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !strings.Contains(string(b), want) {
+			t.Errorf("%s = %q, want it to contain %q", name, b, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// cliArgs holds the flags and positional arguments of a subcommand.
+// Flags may appear anywhere on the command line, before or after positionals.
+type cliArgs struct {
+	flags map[string]string
+	pos   []string
+}
+
+// parseArgs splits args into flags and positionals. Flags listed in
+// valueFlags consume the following argument (or an inline `--flag=value`);
+// every other `--flag` is treated as a boolean switch. A bare `--` ends flag
+// parsing.
+func parseArgs(args []string, valueFlags ...string) cliArgs {
+	takesValue := make(map[string]bool, len(valueFlags))
+	for _, f := range valueFlags {
+		takesValue[f] = true
+	}
+
+	a := cliArgs{flags: map[string]string{}}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			a.pos = append(a.pos, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			a.pos = append(a.pos, arg)
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		if k, v, ok := strings.Cut(name, "="); ok {
+			a.flags[k] = v
+			continue
+		}
+		if takesValue[name] && i+1 < len(args) {
+			a.flags[name] = args[i+1]
+			i++
+			continue
+		}
+		a.flags[name] = "true"
+	}
+	return a
+}
+
+// leadingFlags splits the flags given before a subcommand, as in
+// `tmux-nav --strategy tab attach api`, from the subcommand and its
+// arguments. Only valueFlags are taken there; anything else, -h included,
+// starts the subcommand.
+func leadingFlags(args []string, valueFlags ...string) (cliArgs, []string) {
+	i := 0
+	for i < len(args) && strings.HasPrefix(args[i], "--") {
+		name, _, inline := strings.Cut(strings.TrimPrefix(args[i], "--"), "=")
+		if !slices.Contains(valueFlags, name) {
+			break
+		}
+		i++
+		if !inline && i < len(args) {
+			i++
+		}
+	}
+	return parseArgs(args[:i], valueFlags...), args[i:]
+}
+
+// has reports whether the flag was given.
+func (a cliArgs) has(name string) bool {
+	_, ok := a.flags[name]
+	return ok
+}
+
+// get returns the flag's value, or def when absent.
+func (a cliArgs) get(name, def string) string {
+	if v, ok := a.flags[name]; ok {
+		return v
+	}
+	return def
+}
+
+// arg returns the i-th positional argument, or "" when missing.
+func (a cliArgs) arg(i int) string {
+	if i < len(a.pos) {
+		return a.pos[i]
+	}
+	return ""
+}
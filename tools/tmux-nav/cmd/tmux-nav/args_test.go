@@ -0,0 +1,51 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args  []string
+		flags map[string]string
+		pos   []string
+	}{
+		{[]string{"api", "--json"}, map[string]string{"json": "true"}, []string{"api"}},
+		{[]string{"--dir", "/src", "api", "--cmd=htop"}, map[string]string{"dir": "/src", "cmd": "htop"}, []string{"api"}},
+		{[]string{"-dir", "/src", "-"}, map[string]string{"dir": "/src"}, []string{"-"}},
+		{[]string{"api", "--", "--json", "x"}, map[string]string{}, []string{"api", "--json", "x"}},
+		{[]string{"api", "--dir"}, map[string]string{"dir": "true"}, []string{"api"}},
+	}
+	for _, tt := range tests {
+		a := parseArgs(tt.args, "dir", "cmd")
+		if !maps.Equal(a.flags, tt.flags) || !slices.Equal(a.pos, tt.pos) {
+			t.Errorf("parseArgs(%q) = %v %q, want %v %q", tt.args, a.flags, a.pos, tt.flags, tt.pos)
+		}
+	}
+}
+
+func TestLeadingFlags(t *testing.T) {
+	tests := []struct {
+		args  []string
+		flags map[string]string
+		rest  []string
+	}{
+		{nil, map[string]string{}, nil},
+		{[]string{"--strategy", "tab"}, map[string]string{"strategy": "tab"}, nil},
+		{[]string{"--strategy=tab", "--profile", "Work", "attach", "api", "--strategy", "window"},
+			map[string]string{"strategy": "tab", "profile": "Work"}, []string{"attach", "api", "--strategy", "window"}},
+		{[]string{"--profile", "Work", "list", "--json"}, map[string]string{"profile": "Work"}, []string{"list", "--json"}},
+		// Not a leading flag: a subcommand (or an unknown one) starts here.
+		{[]string{"--help"}, map[string]string{}, []string{"--help"}},
+		{[]string{"--strategyx", "tab"}, map[string]string{}, []string{"--strategyx", "tab"}},
+		{[]string{"list", "--strategy", "tab"}, map[string]string{}, []string{"list", "--strategy", "tab"}},
+	}
+	for _, tt := range tests {
+		global, rest := leadingFlags(tt.args, "strategy", "profile")
+		if !maps.Equal(global.flags, tt.flags) || !slices.Equal(rest, tt.rest) {
+			t.Errorf("leadingFlags(%q) = %v %q, want %v %q", tt.args, global.flags, rest, tt.flags, tt.rest)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/attach"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// runAgent implements `tmux-nav agent <subcommand>`.
+func runAgent(argv []string) {
+	if len(argv) == 0 {
+		die("agent requires a subcommand (new, rm, archive, fix-names)", nil)
+	}
+	switch argv[0] {
+	case "new":
+		args := parseArgs(argv[1:], "task", "prompt", "name", "repo")
+		var project agent.Project
+		var err error
+		switch {
+		case args.has("repo"):
+			project, err = agent.ProjectForDir(args.get("repo", ""))
+		case args.arg(0) != "":
+			project, err = agent.LookupProject(args.arg(0))
+		default:
+			die("agent new requires a project name or --repo", nil)
+		}
+		if err != nil {
+			die("agent new:", err)
+		}
+		name, err := agent.Spawn(agent.Spec{
+			Project:  project,
+			Task:     args.get("prompt", args.get("task", "")),
+			Name:     args.get("name", ""),
+			Worktree: args.has("worktree"),
+		})
+		if err != nil {
+			die("agent new:", err)
+		}
+		fmt.Println("started", name)
+		if args.has("attach") {
+			if err := attachSession(name, pickStrategy(), attach.Options{}); err != nil {
+				die("attach:", err)
+			}
+		}
+
+	case "rm":
+		args := parseArgs(argv[1:])
+		if args.arg(0) == "" {
+			die("agent rm requires a session name", nil)
+		}
+		s, err := findSession(args.arg(0))
+		if err != nil {
+			die("agent rm:", err)
+		}
+		if err := agent.Remove(s, args.has("delete-branch"), args.has("force")); err != nil {
+			die("agent rm:", err)
+		}
+		fmt.Println("removed", s.Name)
+
+	case "archive":
+		args := parseArgs(argv[1:])
+		if args.arg(0) == "" {
+			die("agent archive requires a session name", nil)
+		}
+		s, err := findSession(args.arg(0))
+		if err != nil {
+			die("agent archive:", err)
+		}
+		dir, err := agent.Archive(s)
+		if err != nil {
+			die("agent archive:", err)
+		}
+		fmt.Println("archived", s.Name, "to", dir)
+
+	case "fix-names":
+		args := parseArgs(argv[1:])
+		sessions, err := tmuxclient.ListSessions(context.Background())
+		if err != nil {
+			die("agent fix-names:", err)
+		}
+		for _, s := range sessions {
+			if !agent.IsAgent(s) || agent.Conforms(s.Name) {
+				continue
+			}
+			name := agent.ConformingName(s)
+			if args.has("dry-run") {
+				fmt.Printf("%s → %s\n", s.Name, name)
+				continue
+			}
+			if err := tmuxclient.RenameSession(context.Background(), s.Name, name); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", s.Name, err)
+				continue
+			}
+			fmt.Printf("renamed %s → %s\n", s.Name, name)
+		}
+
+	default:
+		die("unknown agent subcommand: "+argv[0], nil)
+	}
+}
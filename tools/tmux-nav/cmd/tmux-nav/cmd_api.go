@@ -0,0 +1,219 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// apiHandler serves `tmux-nav serve`'s JSON API: the session operations
+// of the command line, for dashboards and remote scripts. Every request
+// must carry token as a bearer token; whoever holds it can run any command
+// as this user. Sessions are named exactly here; partial names are for
+// people.
+func apiHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/sessions", apiList)
+	mux.HandleFunc("POST /api/sessions", apiNewSession)
+	mux.HandleFunc("GET /api/sessions/{name}", apiPeek)
+	mux.HandleFunc("DELETE /api/sessions/{name}", apiKill)
+	mux.HandleFunc("POST /api/sessions/{name}/send", apiSend)
+	mux.HandleFunc("POST /api/agents", apiNewAgent)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			apiError(w, http.StatusUnauthorized, errors.New("missing or wrong bearer token"))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func apiList(w http.ResponseWriter, r *http.Request) {
+	sessions, err := tmuxclient.ListSessions(r.Context())
+	if err != nil {
+		apiError(w, http.StatusBadGateway, err)
+		return
+	}
+	apiReply(w, http.StatusOK, sessionRecords(sessions, agent.DetectAll(sessions)))
+}
+
+// apiPeek returns the session's last lines, 40 or ?lines=N.
+func apiPeek(w http.ResponseWriter, r *http.Request) {
+	s, ok := apiSession(w, r)
+	if !ok {
+		return
+	}
+	lines := 40
+	if v := r.URL.Query().Get("lines"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			apiError(w, http.StatusBadRequest, fmt.Errorf("lines=%q is not a positive number", v))
+			return
+		}
+		lines = n
+	}
+	text, err := tmuxclient.CaptureText(r.Context(), s.Name, lines)
+	if err != nil {
+		apiError(w, http.StatusBadGateway, err)
+		return
+	}
+	apiReply(w, http.StatusOK, newPeekRecord(s, text))
+}
+
+func apiKill(w http.ResponseWriter, r *http.Request) {
+	s, ok := apiSession(w, r)
+	if !ok {
+		return
+	}
+	if err := agent.Kill(s.Name); err != nil {
+		apiError(w, http.StatusBadGateway, err)
+		return
+	}
+	apiReply(w, http.StatusOK, map[string]string{"killed": s.Name})
+}
+
+// apiSend sends {"text": ...} to the session, as `send` or, to agents,
+// `prompt` would.
+func apiSend(w http.ResponseWriter, r *http.Request) {
+	s, ok := apiSession(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Text string `json:"text"`
+	}
+	if !apiBody(w, r, &body) {
+		return
+	}
+	if err := sendTo(r.Context(), s, body.Text); err != nil {
+		apiError(w, http.StatusBadGateway, err)
+		return
+	}
+	apiReply(w, http.StatusOK, map[string]string{"sent": s.Name})
+}
+
+// apiNewSession creates a plain session, as `new` does.
+func apiNewSession(w http.ResponseWriter, r *http.Request) {
+	var opts struct {
+		Name    string `json:"name"`
+		Dir     string `json:"dir"`
+		Command string `json:"command"`
+	}
+	if !apiBody(w, r, &opts) {
+		return
+	}
+	if err := tmuxclient.CheckName(opts.Name); err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+	dir, err := apiDir(opts.Dir)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+	if tmuxclient.HasSession(r.Context(), opts.Name) {
+		apiError(w, http.StatusConflict, fmt.Errorf("session %q already exists", opts.Name))
+		return
+	}
+	err = tmuxclient.NewSession(r.Context(), tmuxclient.NewSessionOptions{Name: opts.Name, Dir: dir, Command: opts.Command})
+	if err != nil {
+		apiError(w, http.StatusBadGateway, err)
+		return
+	}
+	apiReply(w, http.StatusCreated, map[string]string{"session": opts.Name})
+}
+
+// apiNewAgent spawns an agent, as `agent new` does.
+func apiNewAgent(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Project  string `json:"project"`
+		Repo     string `json:"repo"`
+		Prompt   string `json:"prompt"`
+		Name     string `json:"name"`
+		Worktree bool   `json:"worktree"`
+	}
+	if !apiBody(w, r, &body) {
+		return
+	}
+	repo, err := apiDir(body.Repo)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+	project, err := agentProject(body.Project, repo)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err)
+		return
+	}
+	name, err := agent.Spawn(agent.Spec{Project: project, Task: body.Prompt, Name: body.Name, Worktree: body.Worktree})
+	if err != nil {
+		apiError(w, http.StatusBadGateway, err)
+		return
+	}
+	apiReply(w, http.StatusCreated, map[string]string{"session": name})
+}
+
+// apiDir checks a directory given in a request. It must be absolute, or
+// start with ~ for the home directory: a relative one would resolve
+// against wherever serve happened to start. "" is left for the default.
+func apiDir(dir string) (string, error) {
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, dir[1:])
+	}
+	if dir != "" && !filepath.IsAbs(dir) {
+		return "", fmt.Errorf("dir %q is not absolute", dir)
+	}
+	return dir, nil
+}
+
+// apiSession finds the session named in the path, replying 404 when there
+// is none.
+func apiSession(w http.ResponseWriter, r *http.Request) (tmuxclient.Session, bool) {
+	name := r.PathValue("name")
+	sessions, err := tmuxclient.ListSessions(r.Context())
+	if err != nil {
+		apiError(w, http.StatusBadGateway, err)
+		return tmuxclient.Session{}, false
+	}
+	i := slices.IndexFunc(sessions, func(s tmuxclient.Session) bool { return s.Name == name })
+	if i < 0 {
+		apiError(w, http.StatusNotFound, fmt.Errorf("no session %q", name))
+		return tmuxclient.Session{}, false
+	}
+	return sessions[i], true
+}
+
+// apiBody decodes the JSON request body into v, replying 400 when it
+// can't.
+func apiBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(v); err != nil {
+		apiError(w, http.StatusBadRequest, fmt.Errorf("request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func apiReply(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func apiError(w http.ResponseWriter, status int, err error) {
+	apiReply(w, status, map[string]string{"error": err.Error()})
+}
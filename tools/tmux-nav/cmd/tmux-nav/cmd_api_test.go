@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+)
+
+func TestAPINewSession(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	tests := []struct {
+		token, body string
+		status      int
+		dir         string // -c given to new-session
+	}{
+		{token: "wrong", body: `{"name":"api","dir":"/src/api"}`, status: http.StatusUnauthorized},
+		{token: "s3cret", body: `{"name":"api","dir":"src/api"}`, status: http.StatusBadRequest},
+		{token: "s3cret", body: `{"name":"api","dir":"../api"}`, status: http.StatusBadRequest},
+		{token: "s3cret", body: `{"name":"api","dir":"/src/api"}`, status: http.StatusCreated, dir: "/src/api"},
+		{token: "s3cret", body: `{"name":"api","dir":"~/src/api"}`, status: http.StatusCreated, dir: filepath.Join(home, "src/api")},
+		{token: "s3cret", body: `{"name":"api"}`, status: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		f := tmuxtest.New().
+			On("has-session", "", errors.New("can't find session: api")).
+			On("new-session", "", nil)
+		tmuxtest.Install(t, f)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/sessions", strings.NewReader(tt.body))
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		rec := httptest.NewRecorder()
+		apiHandler("s3cret").ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s with token %q: status %d, want %d: %s", tt.body, tt.token, rec.Code, tt.status, rec.Body)
+			continue
+		}
+		i := slices.IndexFunc(f.Calls(), func(c []string) bool { return c[0] == "new-session" })
+		if tt.status != http.StatusCreated {
+			if i >= 0 {
+				t.Errorf("%s with token %q created a session: %q", tt.body, tt.token, f.Calls()[i])
+			}
+			continue
+		}
+		call := f.Calls()[i]
+		if j := slices.Index(call, "-c"); (j >= 0) != (tt.dir != "") || (j >= 0 && call[j+1] != tt.dir) {
+			t.Errorf("%s: ran %q, want the session started in %q", tt.body, call, tt.dir)
+		}
+	}
+}
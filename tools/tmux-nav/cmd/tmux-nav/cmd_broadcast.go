@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// runBroadcast implements `tmux-nav broadcast`: send the same prompt to
+// every agent session, or those whose names match --filter.
+func runBroadcast(argv []string) {
+	args := parseArgs(argv, "filter")
+	text := strings.Join(args.pos, " ")
+	if text == "" {
+		die("broadcast requires the text to send", nil)
+	}
+	if text == "-" {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			die("broadcast:", err)
+		}
+		text = string(b)
+	}
+	filter := args.get("filter", "*")
+	if _, err := path.Match(filter, ""); err != nil {
+		die("broadcast: --filter "+filter+":", err)
+	}
+	sessions, err := tmuxclient.ListSessions(context.Background())
+	if err != nil {
+		die("broadcast:", err)
+	}
+	var targets []string
+	for _, s := range sessions {
+		if ok, _ := path.Match(filter, s.Name); ok && agent.IsAgent(s) {
+			targets = append(targets, s.Name)
+		}
+	}
+	if len(targets) == 0 {
+		die(fmt.Sprintf("broadcast: no agent sessions match %q", filter), nil)
+	}
+	failed := false
+	for _, name := range targets {
+		if args.has("dry-run") {
+			fmt.Println("would send to", name)
+			continue
+		}
+		if err := agent.SendPrompt(name, text); err != nil {
+			fmt.Fprintf(os.Stderr, "broadcast: %s: %v\n", name, err)
+			failed = true
+			continue
+		}
+		fmt.Println("sent to", name)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/archive"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// runCapture implements `tmux-nav capture start|stop [sessions...]`.
+// Without session names it applies to every agent session. `capture
+// export` and `capture grep` read a session's whole history.
+func runCapture(argv []string) {
+	if len(argv) == 0 {
+		die("capture requires a subcommand (start, stop, export, grep)", nil)
+	}
+	switch argv[0] {
+	case "export":
+		runCaptureExport(argv[1:])
+		return
+	case "grep":
+		runCaptureGrep(argv[1:])
+		return
+	}
+	args := parseArgs(argv[1:])
+	names := args.pos
+	if len(names) == 0 {
+		sessions, err := tmuxclient.ListSessions(context.Background())
+		if err != nil {
+			die("capture:", err)
+		}
+		for _, s := range sessions {
+			if agent.IsAgent(s) {
+				names = append(names, s.Name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		var err error
+		switch argv[0] {
+		case "start":
+			err = archive.StartCapture(name)
+		case "stop":
+			err = archive.StopCapture(name)
+		default:
+			die("unknown capture subcommand: "+argv[0], nil)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			continue
+		}
+		fmt.Printf("%s %s → %s\n", argv[0], name, archive.LogDir(name))
+	}
+}
+
+// runCaptureExport implements `tmux-nav capture export <s> [--out FILE]`,
+// streaming the history so deep scrollback never sits in memory.
+func runCaptureExport(argv []string) {
+	args := parseArgs(argv, "out")
+	session := args.arg(0)
+	if session == "" {
+		die("capture export requires a session name", nil)
+	}
+	r, err := tmuxclient.StreamHistory(context.Background(), session)
+	if err != nil {
+		die("capture export:", err)
+	}
+	defer r.Close()
+	var w io.Writer = os.Stdout
+	if path := args.get("out", ""); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			die("capture export:", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		die("capture export:", err)
+	}
+}
+
+// runCaptureGrep implements `tmux-nav capture grep <s> <pattern>`, printing
+// the matching history lines with their line numbers.
+func runCaptureGrep(argv []string) {
+	args := parseArgs(argv)
+	session, pattern := args.arg(0), args.arg(1)
+	if session == "" || pattern == "" {
+		die("capture grep requires a session name and a pattern", nil)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		die("capture grep:", err)
+	}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	found := false
+	err = tmuxclient.SearchHistory(context.Background(), session, re, func(m tmuxclient.Match) error {
+		found = true
+		_, err := fmt.Fprintf(w, "%d:%s\n", m.Line, m.Text)
+		return err
+	})
+	if err != nil {
+		w.Flush()
+		die("capture grep:", err)
+	}
+	if !found {
+		w.Flush()
+		os.Exit(1)
+	}
+}
+
+// runCaptureWriter is the pipe-pane end of `capture start`: it copies the
+// pane output arriving on stdin into rotating log files.
+func runCaptureWriter(argv []string) {
+	args := parseArgs(argv, "session")
+	session := args.get("session", "")
+	if session == "" {
+		die("capture-writer requires --session", nil)
+	}
+	if err := archive.CopyToLogs(session, os.Stdin); err != nil {
+		die("capture-writer:", err)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bjornslib/tmux-nav/config"
+)
+
+// runConfig implements `tmux-nav config show`: print the settings given in
+// files, the environment and flags, or with --effective every setting and
+// the layer it came from.
+func runConfig(argv []string) {
+	args := parseArgs(argv)
+	if args.arg(0) != "show" {
+		die("config requires a subcommand: show", nil)
+	}
+	effective := args.has("effective")
+	for _, s := range config.Settings() {
+		src := cfg.Origin(s.Key)
+		if src == config.FromDefault && !effective {
+			continue
+		}
+		v, _ := cfg.Get(s.Key)
+		if effective {
+			fmt.Printf("%-32s # %s\n", s.Key+" = "+v, describeSource(src, s))
+		} else {
+			fmt.Printf("%s = %s\n", s.Key, v)
+		}
+	}
+	if effective {
+		fmt.Printf("# %d projects, %d templates, %d schedules, %d macros, %d plugins, %d notify rules\n",
+			len(cfg.Agents.Projects), len(cfg.Agents.Templates), len(cfg.Schedules),
+			len(cfg.Macros), len(cfg.Plugins), len(cfg.Notify.Rules))
+	}
+}
+
+// describeSource names where a setting came from, with the variable for
+// environment overrides.
+func describeSource(src config.Source, s config.Setting) string {
+	switch src {
+	case config.FromEnv:
+		return "env " + s.Env
+	case config.FromFile:
+		return "file"
+	}
+	return string(src)
+}
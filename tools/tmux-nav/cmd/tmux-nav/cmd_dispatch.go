@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/dispatch"
+)
+
+// runDispatch implements `tmux-nav dispatch`: queue a task and hand queued
+// tasks to idle agents, or list the queue.
+func runDispatch(argv []string) {
+	args := parseArgs(argv, "project", "max")
+	overrideFlags(args, map[string]string{"max": "agents.max_agents"})
+	if args.has("list") {
+		printQueue("dispatch", args.has("json"))
+		return
+	}
+
+	if text := strings.Join(args.pos, " "); text != "" {
+		t, err := dispatch.Enqueue(text, args.get("project", ""))
+		if err != nil {
+			die("dispatch:", err)
+		}
+		fmt.Printf("queued #%d\n", t.ID)
+	}
+	assigned, err := dispatch.Drain(cfg.Agents.MaxAgents)
+	for _, t := range assigned {
+		fmt.Printf("#%d → %s\n", t.ID, t.Session)
+	}
+	if err != nil {
+		die("dispatch:", err)
+	}
+}
+
+// runQueue implements `tmux-nav queue`: add tasks for the supervisor (or
+// the next dispatch) to hand to idle agents, and list the queue.
+func runQueue(argv []string) {
+	if len(argv) == 0 {
+		die("queue requires a subcommand (add, list)", nil)
+	}
+	switch argv[0] {
+	case "add":
+		args := parseArgs(argv[1:], "project")
+		text := strings.Join(args.pos, " ")
+		if text == "" {
+			die("queue add requires the task text", nil)
+		}
+		t, err := dispatch.Enqueue(text, args.get("project", ""))
+		if err != nil {
+			die("queue add:", err)
+		}
+		fmt.Printf("queued #%d\n", t.ID)
+
+	case "list":
+		args := parseArgs(argv[1:])
+		printQueue("queue list", args.has("json"))
+
+	default:
+		die("unknown queue subcommand: "+argv[0], nil)
+	}
+}
+
+// printQueue prints the queued and assigned tasks, one per line or as
+// JSON.
+func printQueue(cmd string, asJSON bool) {
+	q, err := dispatch.Load()
+	if err != nil {
+		die(cmd+":", err)
+	}
+	if asJSON {
+		printJSON(q.Tasks)
+		return
+	}
+	for _, t := range q.Tasks {
+		fmt.Printf("#%-4d %-8s %-28s %s\n", t.ID, t.Status, t.Session, t.Text)
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// runKeys implements `tmux-nav keys`: print every navigator action with
+// the keys bound to it after applying [keys].
+func runKeys() {
+	for _, b := range keymap().Bindings() {
+		keys := strings.Join(b.Keys, ", ")
+		if keys == "" {
+			keys = "(unbound)"
+		}
+		fmt.Printf("%-17s %-18s %s\n", b.Action, keys, b.Help)
+	}
+}
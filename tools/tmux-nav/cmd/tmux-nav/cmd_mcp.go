@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/mcp"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// runMCP implements `tmux-nav mcp`: a Model Context Protocol server on
+// stdio, letting a supervising assistant list, read, prompt and spawn the
+// sessions this harness manages.
+func runMCP() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	s := &mcp.Server{Name: "tmux-nav", Version: "1", Tools: mcpTools}
+	if err := s.Serve(ctx, os.Stdin, os.Stdout); err != nil {
+		die("mcp:", err)
+	}
+}
+
+// schema builds the JSON Schema of an object with string properties, the
+// required ones first.
+func schema(required []string, optional ...string) map[string]any {
+	props := map[string]any{}
+	for _, p := range append(required, optional...) {
+		props[p] = map[string]any{"type": "string"}
+	}
+	return map[string]any{"type": "object", "properties": props, "required": append([]string{}, required...)}
+}
+
+// withBool adds an optional boolean property to an object schema.
+func withBool(schema map[string]any, name string) map[string]any {
+	schema["properties"].(map[string]any)[name] = map[string]any{"type": "boolean"}
+	return schema
+}
+
+var mcpTools = []mcp.Tool{
+	{
+		Name:        "list_sessions",
+		Description: "List the tmux sessions, with each agent session's state (working, idle, waiting for permission, …), branch and cost.",
+		InputSchema: schema(nil),
+		Call: func(ctx context.Context, _ json.RawMessage) (string, error) {
+			sessions, err := tmuxclient.ListSessions(ctx)
+			if err != nil {
+				return "", err
+			}
+			return jsonText(sessionRecords(sessions, agent.DetectAll(sessions)))
+		},
+	},
+	{
+		Name:        "peek_session",
+		Description: "Read the last lines a session's active pane shows. Session names may be abbreviated.",
+		InputSchema: schema([]string{"session"}),
+		Call: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var a struct{ Session string }
+			if err := json.Unmarshal(args, &a); err != nil {
+				return "", err
+			}
+			s, err := findSession(a.Session)
+			if err != nil {
+				return "", err
+			}
+			text, err := tmuxclient.CaptureText(ctx, s.Name, 40)
+			if err != nil {
+				return "", err
+			}
+			return jsonText(newPeekRecord(s, text))
+		},
+	},
+	{
+		Name:        "send_to_session",
+		Description: "Send text to a session and press Enter: as a prompt to an agent session, or typed as a line into any other.",
+		InputSchema: schema([]string{"session", "text"}),
+		Call: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var a struct{ Session, Text string }
+			if err := json.Unmarshal(args, &a); err != nil {
+				return "", err
+			}
+			s, err := findSession(a.Session)
+			if err != nil {
+				return "", err
+			}
+			if err := sendTo(ctx, s, a.Text); err != nil {
+				return "", err
+			}
+			return "sent to " + s.Name, nil
+		},
+	},
+	{
+		Name: "create_agent_session",
+		Description: "Start a Claude Code agent session with an initial prompt, from a configured project or in a repository directory. " +
+			"Set worktree to give it its own git worktree and branch.",
+		InputSchema: withBool(schema([]string{"prompt"}, "project", "repo", "name"), "worktree"),
+		Call: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var a struct {
+				Prompt, Project, Repo, Name string
+				Worktree                    bool
+			}
+			if err := json.Unmarshal(args, &a); err != nil {
+				return "", err
+			}
+			project, err := agentProject(a.Project, a.Repo)
+			if err != nil {
+				return "", err
+			}
+			name, err := agent.Spawn(agent.Spec{Project: project, Task: a.Prompt, Name: a.Name, Worktree: a.Worktree})
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("started %s", name), nil
+		},
+	},
+}
+
+// sendTo sends text to a session and presses Enter: as a prompt to an
+// agent, typed as a line into anything else.
+func sendTo(ctx context.Context, s tmuxclient.Session, text string) error {
+	if agent.IsAgent(s) {
+		return agent.SendPrompt(s.Name, text)
+	}
+	return tmuxclient.SendLine(ctx, tmuxclient.ActivePaneTarget(s.Name), text)
+}
+
+// agentProject returns the project to spawn an agent from: the repository
+// directory repo's, if given, else the configured project named project.
+func agentProject(project, repo string) (agent.Project, error) {
+	switch {
+	case repo != "":
+		return agent.ProjectForDir(repo)
+	case project != "":
+		return agent.LookupProject(project)
+	}
+	return agent.Project{}, errors.New("give a project or a repo")
+}
+
+// jsonText renders a tool result as indented JSON.
+func jsonText(v any) (string, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	return string(b), err
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/attach"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/mattn/go-isatty"
+)
+
+// runPick implements `tmux-nav pick`, the two ends of an fzf pipeline:
+//
+//	tmux-nav pick | fzf --preview 'tmux-nav peek {1}' | tmux-nav pick
+//
+// Run from a terminal (or with --print) it prints one line per session,
+// its name first and a tab before the details. With a line piped in, it
+// attaches to the session named at the start of it.
+func runPick(argv []string) {
+	args := parseArgs(argv)
+	if args.has("print") || isatty.IsTerminal(os.Stdin.Fd()) {
+		printPickLines()
+		return
+	}
+
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	name, _, _ := strings.Cut(strings.TrimRight(line, "\r\n"), "\t")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		os.Exit(1) // nothing picked, e.g. fzf was cancelled
+	}
+	name = sessionName("pick", name)
+	// tmux attach needs a terminal, and ours is the pipe fzf wrote to.
+	if err := reopenTTY(); err != nil {
+		die("pick:", err)
+	}
+	if err := attachSession(name, pickStrategy(), attach.Options{}); err != nil {
+		die("attach:", err)
+	}
+}
+
+func printPickLines() {
+	sessions, err := tmuxclient.ListSessions(context.Background())
+	if err != nil {
+		die("pick:", err)
+	}
+	states := agent.DetectAll(sessions)
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	for _, s := range sessions {
+		status, kind := sessionStatus(s, states)
+		fmt.Fprintf(w, "%s\t%dw  %s  %s\n", s.Name, s.Windows, status, kind)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/bjornslib/tmux-nav/attach"
+	"github.com/bjornslib/tmux-nav/platform"
+)
+
+// runPlatform implements `tmux-nav platform`: report the detected platform
+// and how each desktop feature is done on it, or what to install.
+func runPlatform() {
+	p := platform.Current()
+	fmt.Printf("%-10s %s\n", "platform", p)
+	tmux, err := exec.LookPath("tmux")
+	if err != nil {
+		tmux = "none — install with " + p.InstallTmux()
+	}
+	fmt.Printf("%-10s %s\n", "tmux", tmux)
+	s := pickStrategy()
+	fmt.Printf("%-10s %s (%s)\n", "attach", attach.StrategyName(s), attach.StrategyLabel(s))
+	for _, sup := range p.Audit() {
+		helper := sup.Helper
+		if helper == "" {
+			helper = "none — install " + sup.Hint
+		}
+		fmt.Printf("%-10s %s\n", sup.Action, helper)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/attach"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// runPopup implements `tmux-nav popup`: the navigator in a tmux
+// display-popup over the current pane. Inside the popup (--inside) we are
+// a tmux client's child, so the picked session is always reached with
+// switch-client, whatever the terminal: attaching there would nest tmux.
+func runPopup(argv []string) {
+	args := parseArgs(argv, "width", "height")
+	if args.has("inside") {
+		runTUI(attach.SwitchClient)
+		return
+	}
+	if !attach.IsInsideTmux() {
+		die("popup: run it inside tmux (or bind it to a key with install-keys)", nil)
+	}
+	err := tmuxclient.DisplayPopup(context.Background(), args.get("width", "80%"), args.get("height", "80%"), popupCommand())
+	if err != nil {
+		die("popup:", err)
+	}
+}
+
+// runInstallKeys implements `tmux-nav install-keys`: bind prefix+<key> to
+// the popup in the running server, and print the tmux.conf line that
+// binds it in servers to come.
+func runInstallKeys(argv []string) {
+	args := parseArgs(argv, "key", "width", "height")
+	key := args.get("key", "S")
+	bind := []string{"display-popup", "-E", "-w", args.get("width", "80%"), "-h", args.get("height", "80%"), popupCommand()}
+
+	line := "bind-key " + key + " " + strings.Join(bind[:len(bind)-1], " ") + " " + tmuxQuote(bind[len(bind)-1])
+	err := tmuxclient.BindKey(context.Background(), key, bind...)
+	switch {
+	case errors.Is(err, tmuxclient.ErrNoServer):
+		fmt.Println("No tmux server is running; to bind prefix+" + key + ", add to ~/.tmux.conf:")
+	case err != nil:
+		die("install-keys:", err)
+	default:
+		fmt.Println("Bound prefix+" + key + " to the navigator popup. To keep it, add to ~/.tmux.conf:")
+	}
+	fmt.Println("  " + line)
+}
+
+// popupCommand is the shell command the popup runs.
+func popupCommand() string {
+	self, err := os.Executable()
+	if err != nil {
+		self = "tmux-nav"
+	}
+	return shellQuote(self) + " popup --inside"
+}
+
+// shellQuote single-quotes s unless it is made only of safe characters.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:@%+,") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// tmuxQuote double-quotes s as one argument in a tmux configuration file.
+func tmuxQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(s) + `"`
+}
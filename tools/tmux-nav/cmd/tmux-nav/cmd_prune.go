@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// runPrune implements `tmux-nav prune`: kill detached sessions idle for
+// longer than prune.after, removing agents' worktrees with them.
+func runPrune(argv []string) {
+	args := parseArgs(argv, "older-than", "exclude")
+	overrideFlags(args, map[string]string{"older-than": "prune.after"})
+	exclude := cfg.Prune.Exclude
+	if e := args.get("exclude", ""); e != "" {
+		exclude = append(exclude, strings.Split(e, ",")...)
+	}
+	sessions, err := tmuxclient.ListSessions(context.Background())
+	if err != nil {
+		die("prune:", err)
+	}
+	now := time.Now()
+	stale := agent.Prunable(sessions, cfg.Prune.After, exclude, now)
+	if len(stale) == 0 {
+		fmt.Println("nothing to prune")
+		return
+	}
+	failed := false
+	for _, s := range stale {
+		idle := now.Sub(s.LastUsed).Round(time.Second)
+		if args.has("dry-run") {
+			fmt.Printf("would kill %s (idle %s)\n", s.Name, idle)
+			continue
+		}
+		if err := agent.Remove(s, false, false); err != nil {
+			fmt.Fprintln(os.Stderr, "prune:", err)
+			failed = true
+			continue
+		}
+		_ = eventlog.Append(s.Name, "prune", "killed after %s idle", idle)
+		fmt.Printf("killed %s (idle %s)\n", s.Name, idle)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/metrics"
+	"github.com/bjornslib/tmux-nav/schedule"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// runServe implements `tmux-nav serve`, the background daemon: it launches
+// scheduled agent runs, does the same upkeep as `supervise` and serves
+// Prometheus metrics and, when enabled, the JSON API, until interrupted.
+func runServe(argv []string) {
+	args := parseArgs(argv, "max", "metrics", "addr")
+	overrideFlags(args, map[string]string{"max": "agents.max_agents", "metrics": "serve.metrics", "addr": "serve.addr"})
+	limit := cfg.Agents.MaxAgents
+	sched := schedule.NewScheduler(scheduledJobs())
+
+	var collector *metrics.Collector
+	if addr := cfg.Serve.Metrics; addr != "off" {
+		collector = metrics.New()
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", collector)
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			die("serve: metrics:", err)
+		}
+		fmt.Printf("metrics on http://%s/metrics\n", ln.Addr())
+		go func() {
+			if err := http.Serve(ln, mux); err != nil {
+				fmt.Fprintln(os.Stderr, "serve: metrics:", err)
+			}
+		}()
+	}
+
+	if addr := cfg.Serve.Addr; addr != "off" {
+		if cfg.Serve.Token == "" {
+			die("serve: the API needs serve.token (or TMUX_NAV_API_TOKEN) set", nil)
+		}
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			die("serve: api:", err)
+		}
+		fmt.Printf("api on http://%s/api/\n", ln.Addr())
+		go func() {
+			if err := http.Serve(ln, apiHandler(cfg.Serve.Token)); err != nil {
+				fmt.Fprintln(os.Stderr, "serve: api:", err)
+			}
+		}()
+	}
+
+	now := time.Now()
+	for _, j := range sched.Jobs() {
+		next := "never"
+		if t := j.Spec.Next(now); !t.IsZero() {
+			next = t.Format("Mon 02 Jan 15:04")
+		}
+		fmt.Printf("schedule %-20s %-14s next %s\n", j.Name, j.Spec, next)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	var conn *tmuxclient.Conn
+	for {
+		// Keep one control-mode connection for all tmux traffic,
+		// reconnecting when its session goes away.
+		if conn == nil || conn.Closed() {
+			conn, _ = tmuxclient.Connect(ctx)
+		}
+		sched.Tick(time.Now())
+		housekeep(limit)
+		if collector != nil {
+			observe(collector)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// observe feeds the metrics collector the current sessions and agent
+// states.
+func observe(c *metrics.Collector) {
+	sessions, err := tmuxclient.ListSessions(context.Background())
+	if err != nil {
+		return
+	}
+	agents := slices.DeleteFunc(slices.Clone(sessions), func(s tmuxclient.Session) bool { return !agent.IsAgent(s) })
+	states := agent.DetectAll(agents)
+	c.Observe(sessions, states, agent.StuckSessions(states, time.Now()))
+}
+
+// scheduledJobs converts the configured [[schedules]]; config validation
+// already checked the cron expressions.
+func scheduledJobs() []schedule.Job {
+	jobs := make([]schedule.Job, 0, len(cfg.Schedules))
+	for _, s := range cfg.Schedules {
+		spec, err := schedule.Parse(s.Cron)
+		if err != nil {
+			die("serve:", err)
+		}
+		jobs = append(jobs, schedule.Job{
+			Name:     s.Name,
+			Spec:     spec,
+			Project:  s.Project,
+			Task:     s.Task,
+			Worktree: s.Worktree,
+		})
+	}
+	return jobs
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/transcript"
+)
+
+// runStats implements `tmux-nav stats`.
+func runStats(argv []string) {
+	args := parseArgs(argv)
+	if !args.has("costs") {
+		die("stats: nothing to show (try --costs)", nil)
+	}
+	sessions, err := tmuxclient.ListSessions(context.Background())
+	if err != nil {
+		die("stats:", err)
+	}
+	infos := agent.TranscriptAll(sessions)
+	if args.has("json") {
+		usage := make(map[string]transcript.Usage, len(infos))
+		for name, info := range infos {
+			usage[name] = info.Usage
+		}
+		printJSON(usage)
+		return
+	}
+
+	perProject := map[string]*transcript.Usage{}
+	var total transcript.Usage
+
+	fmt.Printf("%-32s  %-16s  %10s  %10s  %9s\n", "SESSION", "PROJECT", "IN", "OUT", "COST")
+	for _, s := range sessions {
+		info, ok := infos[s.Name]
+		if !ok {
+			continue
+		}
+		u := info.Usage
+		project := agent.ProjectOf(s)
+		fmt.Printf("%-32s  %-16s  %10d  %10d  %9s\n", s.Name, project,
+			u.InputTokens+u.CacheWriteTokens+u.CacheReadTokens, u.OutputTokens, formatCost(u.CostUSD))
+		if perProject[project] == nil {
+			perProject[project] = &transcript.Usage{}
+		}
+		perProject[project].Add(u)
+		total.Add(u)
+	}
+
+	projects := make([]string, 0, len(perProject))
+	for p := range perProject {
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return perProject[projects[i]].CostUSD > perProject[projects[j]].CostUSD
+	})
+	fmt.Println("\n--- Per project ---")
+	for _, p := range projects {
+		u := perProject[p]
+		fmt.Printf("  %-30s  %12d tokens  %9s\n", p, u.Tokens(), formatCost(u.CostUSD))
+	}
+	fmt.Printf("  %-30s  %12d tokens  %9s\n", "total", total.Tokens(), formatCost(total.CostUSD))
+}
+
+func formatCost(usd float64) string {
+	return fmt.Sprintf("$%.2f", usd)
+}
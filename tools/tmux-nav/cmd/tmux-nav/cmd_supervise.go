@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/dispatch"
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// runSupervise implements `tmux-nav supervise`: restart crashed agent
+// sessions and dispatch queued tasks without the TUI running, until
+// interrupted.
+func runSupervise(argv []string) {
+	args := parseArgs(argv, "interval", "max")
+	interval, err := time.ParseDuration(args.get("interval", "5s"))
+	if err != nil || interval <= 0 {
+		die("supervise: invalid --interval", err)
+	}
+	overrideFlags(args, map[string]string{"max": "agents.max_agents"})
+	agent.SetSupervise(true, cfg.Agents.MaxRestarts)
+	limit := cfg.Agents.MaxAgents
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Printf("supervising agent sessions every %s; restarts are logged to %s\n", interval, eventlog.Path())
+	var conn *tmuxclient.Conn
+	for {
+		if conn == nil || conn.Closed() {
+			conn, _ = tmuxclient.Connect(ctx)
+		}
+		housekeep(limit)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// housekeep runs one round of background upkeep: restarting crashed agents
+// (when supervision is enabled), archiving finished ones (when
+// auto-archive is enabled) and dispatching queued tasks.
+func housekeep(limit int) {
+	sessions, err := tmuxclient.ListSessions(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "supervise:", err)
+	}
+	agent.Supervise(sessions)
+	if cfg.Agents.ArchiveAfter > 0 {
+		agents := slices.DeleteFunc(slices.Clone(sessions), func(s tmuxclient.Session) bool { return !agent.IsAgent(s) })
+		agent.ArchiveDone(agents, agent.DetectAll(agents), agent.TranscriptAll(agents))
+	}
+	if _, err := dispatch.Drain(limit); err != nil {
+		fmt.Fprintln(os.Stderr, "dispatch:", err)
+	}
+}
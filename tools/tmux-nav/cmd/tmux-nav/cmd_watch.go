@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"regexp"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/notify"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// watchLines is how much of each pane --pattern looks at per poll.
+const watchLines = 100
+
+// runWatch implements `tmux-nav watch`: follow the agent sessions without
+// the TUI and print an event whenever one finishes, errors out or waits on
+// a human, until interrupted. --pattern also alerts when any session (or
+// those matching --filter) prints a line matching a regular expression.
+// --notify raises desktop notifications too; webhooks configured under
+// [notify] receive the events as well.
+func runWatch(argv []string) {
+	args := parseArgs(argv, "interval", "waiting-after", "pattern", "filter", "cooldown")
+	interval, err := time.ParseDuration(args.get("interval", "2s"))
+	if err != nil || interval <= 0 {
+		die("watch: invalid --interval", err)
+	}
+	overrideFlags(args, map[string]string{"notify": "notify.desktop"})
+	rules := notifyRules()
+	if v, ok := args.flags["waiting-after"]; ok {
+		after, err := time.ParseDuration(v)
+		if err != nil {
+			die("watch: invalid --waiting-after", err)
+		}
+		rules = withWaitingAfter(rules, after)
+	}
+	var matcher *notify.Matcher
+	if p := args.get("pattern", ""); p != "" {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			die("watch: invalid --pattern", err)
+		}
+		cooldown, err := time.ParseDuration(args.get("cooldown", "1m"))
+		if err != nil {
+			die("watch: invalid --cooldown", err)
+		}
+		matcher = notify.NewMatcher(re, cooldown)
+	}
+	filter := args.get("filter", "*")
+	if _, err := path.Match(filter, ""); err != nil {
+		die("watch: invalid --filter", err)
+	}
+	sinks := notifySinks()
+	if len(sinks) > 0 {
+		sinks = append(sinks, logSink{})
+	}
+	n := notify.New(rules, append(sinks, printSink{})...)
+	n.OnError(func(err error) { fmt.Fprintln(os.Stderr, "notify:", err) })
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Printf("watching agent sessions every %s", interval)
+	if matcher != nil {
+		fmt.Printf(", alerting on /%s/", args.get("pattern", ""))
+	}
+	if cfg.Notify.Desktop {
+		fmt.Print(", with desktop notifications")
+	}
+	fmt.Println()
+	for ctx.Err() == nil {
+		sessions, err := tmuxclient.ListSessions(context.Background())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "watch:", err)
+		}
+		if matcher != nil {
+			n.Deliver(scanSessions(matcher, sessions, filter))
+		}
+		agents := slices.DeleteFunc(sessions, func(s tmuxclient.Session) bool { return !agent.IsAgent(s) })
+		n.Observe(agent.DetectAll(agents), time.Now())
+		// Claude Code hooks signal state changes; don't wait out the
+		// interval for them. Only when waiting fails (no tmux server, say)
+		// do we sleep the interval instead.
+		err = tmuxclient.WaitFor(ctx, agent.EventChannel, interval)
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			select {
+			case <-ctx.Done():
+			case <-time.After(interval):
+			}
+		}
+	}
+}
+
+// scanSessions feeds the panes of the sessions whose names match filter to
+// matcher and returns the alerts raised.
+func scanSessions(matcher *notify.Matcher, sessions []tmuxclient.Session, filter string) []notify.Event {
+	now := time.Now()
+	live := map[string]bool{}
+	var events []notify.Event
+	for _, s := range sessions {
+		if ok, _ := path.Match(filter, s.Name); !ok {
+			continue
+		}
+		live[s.Name] = true
+		text, err := tmuxclient.CaptureText(context.Background(), s.Name, watchLines)
+		if err != nil {
+			continue
+		}
+		if e, ok := matcher.Scan(s.Name, text, now); ok {
+			events = append(events, e)
+		}
+	}
+	matcher.Forget(live)
+	return events
+}
+
+// withWaitingAfter returns rules notifying about waiting agents after d,
+// starting from the defaults when none are configured.
+func withWaitingAfter(rules []notify.Rule, d time.Duration) []notify.Rule {
+	if rules == nil {
+		rules = slices.Clone(notify.DefaultRules)
+	}
+	for i := range rules {
+		if rules[i].Kind == notify.Waiting {
+			rules[i].After = d
+		}
+	}
+	return rules
+}
+
+// printSink prints events to stdout, one line each.
+type printSink struct{}
+
+func (printSink) Send(e notify.Event) error {
+	_, err := fmt.Printf("%s  %s\n", e.Time.Format("15:04:05"), e.Text())
+	return err
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/attach"
+	"github.com/bjornslib/tmux-nav/git"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// runWorktree implements `tmux-nav worktree <subcommand>`: an agent per
+// git worktree of the current repository, in a session named repo/branch.
+func runWorktree(argv []string) {
+	if len(argv) == 0 {
+		die("worktree requires a subcommand (new, rm)", nil)
+	}
+	switch argv[0] {
+	case "new":
+		args := parseArgs(argv[1:], "task", "prompt", "name")
+		branch := args.arg(0)
+		if branch == "" {
+			die("worktree new requires a branch name", nil)
+		}
+		repo, err := git.MainRoot(".")
+		if err != nil {
+			die("worktree new:", err)
+		}
+		project, err := agent.ProjectForDir(repo)
+		if err != nil {
+			die("worktree new:", err)
+		}
+		name := args.get("name", tmuxclient.SafeName(filepath.Base(repo)+"/"+branch))
+		if tmuxclient.HasSession(context.Background(), name) {
+			die("worktree new:", fmt.Errorf("session %q already exists", name))
+		}
+		name, err = agent.Spawn(agent.Spec{
+			Project:  project,
+			Task:     args.get("prompt", args.get("task", "")),
+			Name:     name,
+			Worktree: true,
+			Branch:   branch,
+		})
+		if err != nil {
+			die("worktree new:", err)
+		}
+		fmt.Println("started", name)
+		if args.has("attach") {
+			if err := attachSession(name, pickStrategy(), attach.Options{}); err != nil {
+				die("attach:", err)
+			}
+		}
+
+	case "rm":
+		args := parseArgs(argv[1:])
+		if args.arg(0) == "" {
+			die("worktree rm requires a session name", nil)
+		}
+		s, err := findSession(args.arg(0))
+		if err != nil {
+			die("worktree rm:", err)
+		}
+		if s.Worktree == "" {
+			die("worktree rm:", fmt.Errorf("%s has no worktree; use kill", s.Name))
+		}
+		if err := agent.Remove(s, args.has("delete-branch"), args.has("force")); err != nil {
+			die("worktree rm:", err)
+		}
+		fmt.Println("removed", s.Name, "and", s.Worktree)
+
+	default:
+		die("unknown worktree subcommand: "+argv[0], nil)
+	}
+}
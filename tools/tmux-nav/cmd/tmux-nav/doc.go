@@ -0,0 +1,4 @@
+// Command tmux-nav navigates and supervises tmux sessions and the coding
+// agents running in them, on top of the tmuxclient, attach and navui
+// packages.
+package main
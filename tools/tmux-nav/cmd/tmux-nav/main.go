@@ -0,0 +1,685 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/archive"
+	"github.com/bjornslib/tmux-nav/attach"
+	"github.com/bjornslib/tmux-nav/bugreport"
+	"github.com/bjornslib/tmux-nav/config"
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/fleet"
+	"github.com/bjornslib/tmux-nav/navui"
+	"github.com/bjornslib/tmux-nav/platform"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/trace"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const usage = `tmux-nav — interactive tmux session navigator
+
+Usage:
+  tmux-nav           Launch interactive TUI
+      --strategy S   Attach the way S says (see Built-in strategies below)
+                     instead of the detected or configured way
+      --profile P    Open iTerm2 tabs and windows with profile P; both
+                     may also come before any command below
+  tmux-nav list      List sessions (plain text)
+      --json         Emit session records as JSON
+      --project P    Only agent sessions of project P
+  tmux-nav peek <s>  Peek at session <s>
+      --json         Emit the active pane and its last lines as JSON
+  tmux-nav attach <s> Attach to session <s> (or <s>:<window>[.<pane>])
+      --detach-others  Detach all other clients from <s> (like tmux attach -d)
+      --strategy S   Attach the way S says instead of the detected way
+      --profile P    Open iTerm2 tabs and windows with profile P
+  tmux-nav pick      Print one line per session for fzf, or attach to the
+                     session on the line piped back in:
+                     tmux-nav pick | fzf --preview 'tmux-nav peek {1}' | tmux-nav pick
+      --print        Print the lines even when stdin isn't a terminal
+  tmux-nav popup [--width 80%] [--height 80%]
+                     From inside tmux, open the navigator in a popup; picking
+                     a session switches the client to it
+  tmux-nav install-keys [--key S] [--width 80%] [--height 80%]
+                     Bind prefix+<key> to the popup in the running tmux server
+                     and print the line that keeps it in ~/.tmux.conf
+  tmux-nav kill <s>  Kill session <s>
+  tmux-nav new <name> [--dir PATH] [--cmd "..."] [--attach]
+                     Create a detached session, optionally running a command
+                     in it, and attach to it with --attach
+  tmux-nav rename <old> <new>  Rename session <old> to <new>
+  tmux-nav prompt <s> <text>  Type a prompt into agent session <s> and submit it
+                     (use - to read a multi-line prompt from stdin)
+  tmux-nav archive <s>
+                     Save the whole scrollback of every pane of <s> under
+                     ~/.local/share/tmux-nav/transcripts/<s>/ (see
+                     [agents] archive_on_kill to do it on every kill)
+  tmux-nav broadcast <text> [--filter GLOB] [--dry-run]
+                     Send the same prompt to every agent session, or those
+                     whose names match GLOB (use - to read it from stdin)
+  tmux-nav send <s> [--window W] <text>
+                     Type a line into session <s> (its active pane, or window
+                     W's) and press Enter, without attaching
+  tmux-nav agent new <project> [--task "..."] [--name N] [--worktree] [--attach]
+                     Spawn a Claude Code agent from a configured project,
+                     optionally in a fresh git worktree on branch agent/<slug>
+      --repo PATH    Spawn in the repository at PATH instead of a project
+                     (using the project configured there, if any)
+      --prompt "..." Same as --task: the agent's initial prompt
+  tmux-nav agent rm <s> [--delete-branch] [--force]
+                     Kill agent session <s> and remove its worktree
+  tmux-nav agent fix-names [--dry-run]
+                     Rename agent sessions that don't follow [agents] name_scheme
+  tmux-nav agent archive <s>
+                     Save <s>'s scrollback, transcript and metadata (task,
+                     duration, cost) under ~/.local/share/tmux-nav/archive/,
+                     then kill it
+  tmux-nav worktree new <branch> [--prompt "..."] [--name N] [--attach]
+                     Add a git worktree of the current repository on <branch>
+                     (created from HEAD unless it exists) and start an agent
+                     in it, in a session named <repo>/<branch>
+  tmux-nav worktree rm <s> [--delete-branch] [--force]
+                     Kill session <s> and remove its worktree
+  tmux-nav capture start|stop [<s>...]
+                     Continuously log pane output of <s> (default: all agents)
+                     to ~/.local/share/tmux-nav/logs/<s>/, rotated at 10 MiB
+  tmux-nav capture export <s> [--out FILE]
+                     Write <s>'s whole scrollback to FILE (default: stdout)
+  tmux-nav capture grep <s> <pattern>
+                     Print the lines of <s>'s scrollback matching the regular
+                     expression <pattern>, with their line numbers
+  tmux-nav hook-event [--print-settings]
+                     Record a Claude Code hook event (JSON on stdin) so the
+                     navigator updates agent state instantly; --print-settings
+                     prints the settings.json hooks block to install
+  tmux-nav stats --costs [--json]
+                     Token usage and estimated cost per agent session and project
+  tmux-nav dispatch ["task"] [--project P] [--max N]
+                     Queue a task and hand queued tasks to idle agents,
+                     spawning agents of --project up to N (default 4)
+      --list [--json]  Show queued and assigned tasks
+  tmux-nav queue add "task" [--project P]
+                     Queue a task without dispatching it now; the supervisor
+                     (or the next dispatch) hands it to an idle agent
+  tmux-nav queue list [--json]
+                     Show queued and assigned tasks
+  tmux-nav mcp        Serve list_sessions, peek_session, send_to_session and
+                     create_agent_session to an MCP client over stdio, e.g.
+                     claude mcp add tmux-nav -- tmux-nav mcp
+  tmux-nav watch [--notify] [--interval 2s] [--waiting-after 1m]
+                     Print when agents finish, error out or wait for input;
+                     --notify also shows desktop notifications
+      --pattern RE   Also alert when a session prints a line matching RE,
+                     e.g. "rate limit|Traceback|error"
+      --filter GLOB  Only scan sessions whose names match GLOB
+      --cooldown D   Alert about a session at most once per D (default 1m)
+  tmux-nav supervise [--interval 5s]
+                     Restart crashed agent sessions (respawn-pane) without the
+                     TUI running, and keep dispatching queued tasks; restarts
+                     are recorded in the event log
+  tmux-nav prune [--older-than 24h] [--exclude GLOB,...] [--dry-run]
+                     Kill detached sessions idle longer than prune.after,
+                     removing agents' worktrees; P does the same in the TUI
+  tmux-nav serve [--max N] [--metrics ADDR] [--addr ADDR]
+                     Run the background daemon: launch [[schedules]] agent
+                     runs (history in the TUI log view, L), do the same
+                     upkeep as supervise and serve Prometheus metrics on
+                     ADDR/metrics (default localhost:9464; "off" disables),
+                     unauthenticated
+      --addr ADDR    Also serve a JSON API on ADDR, authenticated with
+                     serve.token: GET /api/sessions, GET, DELETE
+                     /api/sessions/{name}, POST /api/sessions/{name}/send,
+                     POST /api/sessions and POST /api/agents. The token
+                     lets its holder run any command as you; dirs must be
+                     absolute or start with ~
+  tmux-nav orchestrate [--config fleet.yaml] [--max N]
+                     Launch and supervise the agents described in a fleet file
+                     (default: the tasks in harness.yaml)
+  tmux-nav config show [--effective]
+                     Print the settings given in files, the environment and
+                     flags; --effective prints every setting with its source
+  tmux-nav keys      Print the navigator's key bindings, as remapped in [keys]
+  tmux-nav platform  Show the detected platform (macOS, Linux, WSL) and the
+                     tools used for attaching, the clipboard, notifications
+                     and opening directories, or what to install
+  tmux-nav -h        Show this help
+
+  Commands taking a session <s> accept part of its name: a unique prefix,
+  as tmux -t does, or a fuzzy match ("apfl" for agent/api/fix-login). When
+  it matches several sessions you are asked which one.
+
+Environment:
+  Settings layer as defaults < config and harness files < environment <
+  flags. Every single-valued setting has a variable TMUX_NAV_<SECTION>_<KEY>,
+  e.g. TMUX_NAV_AGENTS_MAX_AGENTS=8 or TMUX_NAV_TUI_PREVIEW_MAX_BYTES=65536.
+
+  TMUX_NAV_STRATEGY  attach.strategy: how to attach (see Built-in
+                     strategies below), overriding detection, which can
+                     guess wrong e.g. under tmux in SSH in iTerm2.
+
+  TMUX_NAV_TERMINAL  attach.terminal: on a Linux desktop outside tmux, open
+                     attached sessions in a new terminal window using this
+                     command template, e.g. 'foot -e {{.Cmd}}' or
+                     'x-terminal-emulator -e {{.Cmd}}'. Template fields:
+                     {{.Cmd}} (attach command), {{.Session}}, all
+                     shell-quoted.
+
+Config (~/.config/tmux-nav/config.toml):
+  [attach]
+  strategy = "ssh-jump"        # default strategy (built-in name or template)
+  terminal = "foot -e {{.Cmd}}"  # new-terminal command (see TMUX_NAV_TERMINAL)
+  profile  = "agents"          # iTerm2 profile for new-tab/new-window
+
+  [[attach.templates]]         # custom strategy, selectable by name
+  name = "ssh-jump"
+  cmd  = "ssh -t jump {{q .Cmd}}"  # q quotes again for the remote shell
+  # background = true          # for templates that open a new window
+
+  Built-in strategies: same-window, switch, new-tab, new-window (iTerm2),
+  plain, new-terminal, gnome-tab, konsole-tab, wt-tab. Template fields: {{.Session}}, {{.Window}},
+  {{.Pane}}, {{.Target}}, {{.Cmd}}, each shell-quoted; without {{.Cmd}} a template can't detach
+  other clients, nor pick a window or pane without {{.Target}}.
+
+  [[agents.projects]]          # template for "agent new <project>"
+  name    = "api"
+  dir     = "~/code/api"
+  command = "claude"           # agent command (default: claude)
+  limits  = { nice = 10, io = "idle", cpu = 200 }
+                               # cpu via cpulimit; add cgroup = true and
+                               # memory = "4G" to use a systemd scope
+
+  [agents]
+  name_scheme = "agent/{project}/{task}"  # enforced for new agent sessions
+  capture = true               # log every agent pane continuously
+  stuck_after = "10m"          # flag working agents with frozen output
+  supervise = true             # respawn crashed agents (also in the TUI)
+  max_restarts = 3
+  max_agents = 4               # agent limit for dispatch
+  pr_status = true             # show worktree branches' PR/CI/review via gh
+  archive_after = "30m"        # archive + kill agents idle this long when done
+  archive_on_kill = true       # save every pane's scrollback before a kill
+
+  [[schedules]]                # agent runs launched by "tmux-nav serve"
+  name    = "nightly-deps"
+  cron    = "0 2 * * *"        # 5-field cron, or @hourly/@daily/@nightly/@weekly
+  project = "api"
+  task    = "Update dependencies and open a PR"
+
+  [notify]
+  desktop = true               # notify-send / osascript / Windows (WSL)
+  [[notify.rules]]             # default: finished, error, waiting > 1m
+  event = "waiting"            # finished | error | waiting
+  after = "2m"
+  match = "agent-*"            # optional session glob
+  [[notify.webhooks]]          # POST events as JSON or Slack messages
+  url    = "https://hooks.slack.com/services/..."
+  format = "slack"             # json | slack
+
+  [[macros]]                   # canned reply, keyed in the attention view (!)
+  name = "continue"            # default: c continue, Y yes to all,
+  key  = "c"                   # s stop and summarize
+  text = "continue"
+  # keys = ["Escape"]          # tmux keys to send before the text
+
+  [[agents.templates]]         # shared settings; projects set template = "opus"
+  name    = "opus"
+  command = "claude --model opus"
+
+  [tui]
+  preview_max_bytes = 262144   # cap per cached preview; pgup/pgdn in the
+                               # navigator fetch older scrollback on demand
+  preview_lines = 40           # lines of a pane the preview captures
+  split = 50                   # the list's share of the width (of the height
+                               # when stacked), 20-80; < and > adjust it
+  stack_below = 100            # below this many columns, show the preview
+                               # under the list instead of beside it; 0 never
+  refresh = "2s"               # poll interval while sessions change; backs
+                               # off while nothing does
+  ignore = ["_*", "scratch"]   # session-name globs the navigator hides
+  theme = "light"              # dark (default), light or high-contrast
+
+  [tui.colors]                 # override single theme colours (ANSI number
+  selected = "#d75f00"         # or hex): title, selected, text, muted,
+                               # border, error, warning, attached, agent,
+                               # working, waiting, idle, stuck
+
+  [keys]                       # rebind navigator actions (tmux-nav keys
+  down = ["n", "down"]         # lists them); a rebound key leaves the
+  up   = ["e", "up"]           # action it was bound to by default, [] unbinds
+  deny = ["N"]
+
+  [prune]                      # tmux-nav prune and P in the navigator
+  after   = "24h"              # kill detached sessions idle this long
+  exclude = ["dotfiles", "_*"] # session-name globs never pruned
+
+  [serve]
+  metrics = "localhost:9464"   # /metrics address for serve, no auth; "off" disables
+  addr    = "127.0.0.1:7878"   # JSON API address for serve; "off" (default) disables
+  token   = "..."              # bearer token the API requires (or TMUX_NAV_API_TOKEN);
+                               # it can run any command, so guard it like a key
+
+  [debug]
+  trace = true                 # time every tmux call and UI update; slow
+                               # ones and a summary go to the event log (L)
+  trace_file = "/tmp/tmux-nav.trace"  # also write each call as a JSON line
+
+  [[plugins]]                  # adds list columns, preview tabs (cycled
+  name    = "jira"             # with tab) and key-bound actions; speaks
+  command = "tmux-nav-jira"    # JSON on stdin/stdout (go doc ./plugin)
+
+Harness (~/.config/tmux-nav/harness.yaml, or $TMUX_NAV_HARNESS):
+  The whole harness in one reviewable file; its settings override
+  config.toml and its lists replace config.toml's.
+
+  concurrency: 4               # agent limit for dispatch and orchestrate
+  templates: [{name: opus, command: claude --model opus}]
+  projects:  [{name: api, dir: ~/code/api, template: opus}]
+  notify:    {desktop: true, rules: [{event: waiting, after: 2m}]}
+  archive:   {after: 2h}
+  schedules: [{name: nightly, cron: "@nightly", project: api, task: Update deps}]
+  tasks:     [{project: api, prompt: Refactor auth, worktree: true}]
+`
+
+func main() {
+	loadConfig()
+	defer trace.Stop()
+
+	global, argv := leadingFlags(os.Args[1:], "strategy", "profile")
+	attachFlags(global)
+	if len(argv) == 0 {
+		runTUI(pickStrategy())
+		return
+	}
+
+	cmd, argv := argv[0], argv[1:]
+	switch cmd {
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+
+	case "list":
+		args := parseArgs(argv, "project")
+		sessions, err := tmuxclient.ListSessions(context.Background())
+		if err != nil {
+			die("list:", err)
+		}
+		if project := args.get("project", ""); project != "" {
+			sessions = slices.DeleteFunc(sessions, func(s tmuxclient.Session) bool {
+				return !agent.IsAgent(s) || agent.ProjectOf(s) != project
+			})
+		}
+		states := agent.DetectAll(sessions)
+		if args.has("json") {
+			printJSON(sessionRecords(sessions, states))
+			return
+		}
+		if len(sessions) == 0 {
+			if errors.Is(tmuxclient.Ping(context.Background()), tmuxclient.ErrNoServer) {
+				fmt.Println("(no tmux server running — start one with `tmux new`, or `tmux-nav` and press n)")
+			} else {
+				fmt.Println("(no sessions)")
+			}
+			return
+		}
+		for _, s := range sessions {
+			status, kind := sessionStatus(s, states)
+			fmt.Printf("%-40s  %dw  %s  %s\n", s.Name, s.Windows, status, kind)
+		}
+
+	case "peek":
+		args := parseArgs(argv)
+		name := args.arg(0)
+		if name == "" {
+			die("peek requires a session name", nil)
+		}
+		name = sessionName("peek", name)
+		if args.has("json") {
+			sessions, err := tmuxclient.ListSessions(context.Background())
+			if err != nil {
+				die("peek:", err)
+			}
+			i := slices.IndexFunc(sessions, func(s tmuxclient.Session) bool { return s.Name == name })
+			if i < 0 {
+				die("peek:", fmt.Errorf("no session %q", name))
+			}
+			text, err := tmuxclient.CaptureText(context.Background(), name, 40)
+			if err != nil {
+				die("peek:", err)
+			}
+			printJSON(newPeekRecord(sessions[i], text))
+			return
+		}
+		out, err := tmuxclient.CapturePanes(context.Background(), name, 40)
+		if err != nil {
+			die("peek:", err)
+		}
+		fmt.Print(out)
+
+	case "pick":
+		runPick(argv)
+
+	case "popup":
+		runPopup(argv)
+
+	case "install-keys":
+		runInstallKeys(argv)
+
+	case "attach":
+		args := parseArgs(argv, "strategy", "profile")
+		attachFlags(args)
+		if args.arg(0) == "" {
+			die("attach requires a session name", nil)
+		}
+		target := tmuxclient.ParseTarget(args.arg(0))
+		target.Session = sessionName("attach", target.Session)
+		strategy := pickStrategy()
+		opts := attach.Options{
+			DetachOthers: args.has("detach-others"),
+			Window:       target.Window,
+			Pane:         target.Pane,
+		}
+		if err := attachSession(target.Session, strategy, opts); err != nil {
+			die("attach:", err)
+		}
+
+	case "kill":
+		if len(argv) == 0 {
+			die("kill requires a session name", nil)
+		}
+		name := sessionName("kill", argv[0])
+		if err := agent.Kill(name); err != nil {
+			die("kill:", err)
+		}
+		fmt.Println("killed", name)
+
+	case "rename":
+		args := parseArgs(argv)
+		old, name := args.arg(0), args.arg(1)
+		if old == "" || name == "" {
+			die("rename requires the session's name and its new name", nil)
+		}
+		if err := tmuxclient.CheckName(name); err != nil {
+			die("rename:", err)
+		}
+		old = sessionName("rename", old)
+		if err := tmuxclient.RenameSession(context.Background(), old, name); err != nil {
+			die("rename:", err)
+		}
+		_ = eventlog.Append(name, "rename", "renamed from %s", old)
+		fmt.Printf("renamed %s → %s\n", old, name)
+
+	case "new":
+		args := parseArgs(argv, "dir", "cmd")
+		name := args.arg(0)
+		if name == "" {
+			die("new requires a session name", nil)
+		}
+		if err := tmuxclient.CheckName(name); err != nil {
+			die("new:", err)
+		}
+		if tmuxclient.HasSession(context.Background(), name) {
+			die("new:", fmt.Errorf("session %q already exists", name))
+		}
+		opts := tmuxclient.NewSessionOptions{Name: name, Command: args.get("cmd", "")}
+		if dir := args.get("dir", ""); dir != "" {
+			// The tmux server resolves relative paths against its own
+			// directory, not ours.
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				die("new:", err)
+			}
+			opts.Dir = abs
+		}
+		if err := tmuxclient.NewSession(context.Background(), opts); err != nil {
+			die("new:", err)
+		}
+		_ = eventlog.Append(name, "new", "created")
+		if !args.has("attach") {
+			fmt.Println("created", name)
+			return
+		}
+		if err := attachSession(name, pickStrategy(), attach.Options{}); err != nil {
+			die("attach:", err)
+		}
+
+	case "agent":
+		runAgent(argv)
+
+	case "worktree":
+		runWorktree(argv)
+
+	case "capture":
+		runCapture(argv)
+
+	case "capture-writer":
+		// Internal: the pipe-pane end of `capture start`.
+		runCaptureWriter(argv)
+
+	case "hook-event":
+		args := parseArgs(argv)
+		if args.has("print-settings") {
+			self, _ := os.Executable()
+			fmt.Println(agent.SettingsSnippet(self))
+			return
+		}
+		ev, err := agent.ReadHookEvent(os.Stdin)
+		if err != nil {
+			die("hook-event:", err)
+		}
+		// Never fail the hook: a broken navigator must not block the agent.
+		if err := agent.RecordHookEvent(ev); err != nil {
+			fmt.Fprintln(os.Stderr, "hook-event:", err)
+		}
+
+	case "stats":
+		runStats(argv)
+
+	case "config":
+		runConfig(argv)
+
+	case "keys":
+		runKeys()
+
+	case "platform":
+		runPlatform()
+
+	case "supervise":
+		runSupervise(argv)
+
+	case "prune":
+		runPrune(argv)
+
+	case "watch":
+		runWatch(argv)
+
+	case "serve":
+		runServe(argv)
+
+	case "dispatch":
+		runDispatch(argv)
+
+	case "queue":
+		runQueue(argv)
+
+	case "mcp":
+		runMCP()
+
+	case "orchestrate":
+		args := parseArgs(argv, "config", "max")
+		overrideFlags(args, map[string]string{"max": "agents.max_agents"})
+		path := args.get("config", args.arg(0))
+		harness := path == ""
+		if harness {
+			path = config.HarnessPath()
+			if _, err := os.Stat(path); err != nil {
+				die("orchestrate requires --config <fleet.yaml> or "+path, nil)
+			}
+		}
+		fc, err := fleet.Load(path)
+		if err != nil {
+			die("orchestrate:", err)
+		}
+		if harness {
+			// The harness projects are registered already, templates
+			// resolved, and its concurrency layered under env and flags.
+			fc.Projects = nil
+			fc.Concurrency = cfg.Agents.MaxAgents
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := fleet.Orchestrate(ctx, fc, os.Stdout); err != nil {
+			die("orchestrate:", err)
+		}
+
+	case "prompt":
+		args := parseArgs(argv)
+		if args.arg(0) == "" || args.arg(1) == "" {
+			die("prompt requires a session name and text", nil)
+		}
+		name := sessionName("prompt", args.arg(0))
+		text := strings.Join(args.pos[1:], " ")
+		if text == "-" {
+			b, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				die("prompt:", err)
+			}
+			text = string(b)
+		}
+		if err := agent.SendPrompt(name, text); err != nil {
+			die("prompt:", err)
+		}
+
+	case "archive":
+		args := parseArgs(argv)
+		if args.arg(0) == "" {
+			die("archive requires a session name", nil)
+		}
+		name := sessionName("archive", args.arg(0))
+		files, err := archive.SaveTranscripts(name, time.Now())
+		if err != nil {
+			die("archive:", err)
+		}
+		for _, f := range files {
+			fmt.Println(f)
+		}
+
+	case "broadcast":
+		runBroadcast(argv)
+
+	case "send":
+		args := parseArgs(argv, "window")
+		if args.arg(0) == "" || len(args.pos) < 2 {
+			die("send requires a session name and text", nil)
+		}
+		name := sessionName("send", args.arg(0))
+		target := tmuxclient.ActivePaneTarget(name)
+		if w := args.get("window", ""); w != "" {
+			target = tmuxclient.Target{Session: name, Window: w}.WindowTarget()
+		}
+		if err := tmuxclient.SendLine(context.Background(), target, strings.Join(args.pos[1:], " ")); err != nil {
+			die("send:", err)
+		}
+
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n%s", cmd, usage)
+		os.Exit(1)
+	}
+}
+
+// runTUI runs the navigator, attaching with strategy to the session picked
+// in it.
+func runTUI(strategy attach.Strategy) {
+	navui.UseTheme(theme())
+	m := navui.New()
+	m.Strategy = strategy
+	m.Notifier = newNotifier()
+	m.PRStatus = cfg.Agents.PRStatus
+	m.Macros = macros()
+	m.PreviewMaxBytes = cfg.TUI.PreviewMaxBytes
+	m.PreviewLines = cfg.TUI.PreviewLines
+	m.StackBelow = cfg.TUI.StackBelow
+	m.Split = cfg.TUI.Split
+	m.Refresh = cfg.TUI.Refresh
+	m.Ignore = cfg.TUI.Ignore
+	m.PruneAfter = cfg.Prune.After
+	m.PruneExclude = cfg.Prune.Exclude
+	m.Plugins = plugins()
+	m.Keys = keymap()
+	for {
+		p := tea.NewProgram(m, tea.WithAltScreen())
+		finalModel, err := p.Run()
+		if errors.Is(err, tea.ErrProgramPanic) {
+			reportCrash()
+		}
+		if err != nil {
+			die("tui:", err)
+		}
+
+		// After TUI exits, handle attachment if the user selected a session.
+		fm, ok := finalModel.(navui.Model)
+		if !ok {
+			return
+		}
+		fm.Close()
+		if fm.AttachSession == "" {
+			return
+		}
+		opts := attach.Options{
+			DetachOthers: fm.DetachOthers,
+			Window:       fm.AttachWindow,
+			Pane:         fm.AttachPane,
+		}
+		err = attachSession(fm.AttachSession, fm.Strategy, opts)
+		if err == nil {
+			return
+		}
+
+		// Reopen the navigator on the failed session with recovery options.
+		m = fm.Reopen().WithAttachError(fm.AttachSession, opts, err)
+	}
+}
+
+// reportCrash writes a debug bundle for the panic that ended the
+// navigator, which has already restored the terminal, and says where it is.
+func reportCrash() {
+	c := navui.LastCrash()
+	if c == nil {
+		return
+	}
+	_ = eventlog.Append("", "crash", "navigator panicked during %s: %v", c.During, c.Value)
+	dir, err := bugreport.Write(bugreport.Report{Panic: c.Value, Stack: c.Stack, During: c.During, State: c.State})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tmux-nav crashed; writing the debug bundle failed:", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "tmux-nav crashed: %v\nA debug bundle is in %s — please attach it to a bug report.\n", c.Value, dir)
+}
+
+// attachSession attaches to session, logging the attach first since strategies
+// that replace the process don't return.
+func attachSession(session string, strategy attach.Strategy, opts attach.Options) error {
+	_ = eventlog.Append(session, "attach", "attached (%s)", attach.StrategyName(strategy))
+	return attach.Attach(session, strategy, opts)
+}
+
+func die(msg string, err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %v\n", msg, err)
+		if errors.Is(err, tmuxclient.ErrTmuxNotInstalled) {
+			fmt.Fprintf(os.Stderr, "tmux-nav drives tmux; install it with %s\n", platform.Current().InstallTmux())
+		}
+	} else {
+		fmt.Fprintln(os.Stderr, msg)
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// sessionRecord is the JSON shape of a session in `list --json`: its
+// tmuxclient.Record plus what tmux-nav knows about it as an agent.
+type sessionRecord struct {
+	tmuxclient.Record
+	Agent   bool     `json:"agent"`
+	State   string   `json:"state,omitempty"`
+	CostUSD *float64 `json:"cost_usd,omitempty"`
+}
+
+func sessionRecords(sessions []tmuxclient.Session, states map[string]agent.State) []sessionRecord {
+	recs := make([]sessionRecord, 0, len(sessions))
+	for _, s := range sessions {
+		rec := sessionRecord{Record: s.Record(), Agent: agent.IsAgent(s)}
+		if st, ok := states[s.Name]; ok {
+			rec.State = st.String()
+		}
+		if info, ok := agent.Transcript(s); ok && rec.Agent {
+			rec.CostUSD = &info.Usage.CostUSD
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+// sessionStatus returns the plain-text columns of `list` after the name:
+// whether s is attached ("att") or not ("det"), and what kind of agent it
+// is, if any ("agent" or "agent:<state>").
+func sessionStatus(s tmuxclient.Session, states map[string]agent.State) (status, kind string) {
+	status = "det"
+	if s.Attached {
+		status = "att"
+	}
+	if st, ok := states[s.Name]; ok {
+		kind = "agent:" + st.String()
+	} else if agent.IsAgent(s) {
+		kind = "agent"
+	}
+	return status, kind
+}
+
+// peekRecord is the JSON shape of `peek --json`: the session's active
+// pane and its last lines of output as plain text.
+type peekRecord struct {
+	Session    string    `json:"session"`
+	ActivePane string    `json:"active_pane,omitempty"`
+	Command    string    `json:"command,omitempty"`
+	Cwd        string    `json:"cwd,omitempty"`
+	CapturedAt time.Time `json:"captured_at"`
+	Lines      []string  `json:"lines"`
+}
+
+func newPeekRecord(s tmuxclient.Session, text string) peekRecord {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		lines = []string{}
+	}
+	return peekRecord{
+		Session:    s.Name,
+		ActivePane: s.ActivePane,
+		Command:    s.Command,
+		Cwd:        s.Path,
+		CapturedAt: time.Now(),
+		Lines:      lines,
+	}
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		die("json:", err)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+func TestSessionRecords(t *testing.T) {
+	// One transcript for the agent's directory.
+	t.Setenv("CLAUDE_CONFIG_DIR", t.TempDir())
+	dir := filepath.Join(os.Getenv("CLAUDE_CONFIG_DIR"), "projects", "-src-api")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	line := `{"type":"assistant","costUSD":1.25,"message":{"id":"m1","usage":{"output_tokens":10}}}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "s.jsonl"), []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	used := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	sessions := []tmuxclient.Session{
+		{Name: "api", Windows: 2, Attached: true, LastUsed: used, ActivePane: "1.0", Command: "claude", Path: "/src/api",
+			AgentTag: "api", Worktree: "/src/api-wt", Branch: "feature", HookState: "working"},
+		{Name: "notes", Windows: 1, LastUsed: used, Command: "zsh", Path: "/src/notes"},
+	}
+	recs := sessionRecords(sessions, map[string]agent.State{"api": agent.StateWorking})
+	b, err := json.Marshal(recs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"name":"api","windows":2,"attached":true,"last_used":"2026-01-01T09:00:00Z","active_pane":"1.0","command":"claude",` +
+		`"cwd":"/src/api","worktree":"/src/api-wt","branch":"feature","agent":true,"state":"working","cost_usd":1.25},` +
+		`{"name":"notes","windows":1,"attached":false,"last_used":"2026-01-01T09:00:00Z","command":"zsh","cwd":"/src/notes","agent":false}]`
+	if string(b) != want {
+		t.Errorf("records =\n%s\nwant\n%s", b, want)
+	}
+}
+
+func TestNewPeekRecord(t *testing.T) {
+	s := tmuxclient.Session{Name: "api", ActivePane: "0.1", Command: "claude", Path: "/src/api"}
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"$ go test\nok\n\n\n", []string{"$ go test", "ok"}},
+		{"  indented\n", []string{"  indented"}},
+		{"", []string{}},
+		{"\n\n", []string{}},
+	}
+	for _, tt := range tests {
+		rec := newPeekRecord(s, tt.text)
+		b, _ := json.Marshal(rec.Lines)
+		want, _ := json.Marshal(tt.want)
+		if string(b) != string(want) {
+			t.Errorf("newPeekRecord(%q).Lines = %s, want %s", tt.text, b, want)
+		}
+		if rec.Session != "api" || rec.ActivePane != "0.1" || rec.Cwd != "/src/api" || rec.CapturedAt.IsZero() {
+			t.Errorf("newPeekRecord = %+v, want the session's pane details and a capture time", rec)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/fuzzy"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/mattn/go-isatty"
+)
+
+// findSession returns the live session a name given on the command line
+// means: the one named exactly so, else the one it is a prefix or fuzzy
+// match of. When it could mean several, the user picks one if stdin is a
+// terminal; otherwise it is an error.
+func findSession(name string) (tmuxclient.Session, error) {
+	sessions, err := tmuxclient.ListSessions(context.Background())
+	if err != nil {
+		return tmuxclient.Session{}, err
+	}
+	names := make([]string, len(sessions))
+	for i, s := range sessions {
+		names[i] = s.Name
+	}
+	matches := fuzzy.Resolve(name, names)
+	if len(matches) == 0 {
+		return tmuxclient.Session{}, fmt.Errorf("no session matches %q", name)
+	}
+	pick := matches[0]
+	if len(matches) > 1 {
+		if pick, err = pickSession(name, matches); err != nil {
+			return tmuxclient.Session{}, err
+		}
+	}
+	return sessions[slices.Index(names, pick)], nil
+}
+
+// sessionName resolves a session name given on the command line, dying
+// with the command's name when it can't.
+func sessionName(cmd, name string) string {
+	s, err := findSession(name)
+	if err != nil {
+		die(cmd+":", err)
+	}
+	return s.Name
+}
+
+// pickSession asks which of the sessions name matches was meant.
+func pickSession(name string, matches []string) (string, error) {
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("%q matches %d sessions: %s", name, len(matches), strings.Join(matches, ", "))
+	}
+	fmt.Fprintf(os.Stderr, "%q matches %d sessions:\n", name, len(matches))
+	for i, m := range matches {
+		fmt.Fprintf(os.Stderr, "  %2d) %s\n", i+1, m)
+	}
+	fmt.Fprintf(os.Stderr, "which one? [1-%d] ", len(matches))
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(matches) {
+		return "", fmt.Errorf("no session picked")
+	}
+	return matches[n-1], nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+)
+
+// fakeSessions serves sessions with the given names (all plain shells) to
+// list-sessions.
+func fakeSessions(t *testing.T, names ...string) {
+	t.Helper()
+	var out strings.Builder
+	for _, n := range names {
+		out.WriteString(strings.Join([]string{n, "2", "", "1700000000", "0.1", "zsh", "/src/" + n,
+			"", "", "", "", "0", "", "", "0", "", "0", "1690000000", "", "50", "0"}, "|^|") + "\n")
+	}
+	tmuxtest.Install(t, tmuxtest.New().On("list-sessions", out.String(), nil))
+	tmuxclient.SetCacheTTL(0)
+	t.Cleanup(func() { tmuxclient.SetCacheTTL(tmuxclient.DefaultCacheTTL) })
+}
+
+func TestFindSession(t *testing.T) {
+	fakeSessions(t, "api", "api-refactor", "web-docs", "web-tests")
+	stdin, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	saved := os.Stdin
+	os.Stdin = stdin
+	t.Cleanup(func() { os.Stdin = saved })
+
+	tests := []struct {
+		name, want, err string
+	}{
+		{name: "api", want: "api"}, // exact beats the prefix of api-refactor
+		{name: "api-r", want: "api-refactor"},
+		{name: "wdocs", want: "web-docs"},
+		{name: "web", err: `"web" matches 2 sessions: web-docs, web-tests`},
+		{name: "zzz", err: `no session matches "zzz"`},
+	}
+	for _, tt := range tests {
+		s, err := findSession(tt.name)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("findSession(%q) = %q, %v; want error %q", tt.name, s.Name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || s.Name != tt.want || s.Path != "/src/"+tt.want {
+			t.Errorf("findSession(%q) = %+v, %v; want session %s", tt.name, s, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/attach"
+	"github.com/bjornslib/tmux-nav/config"
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/navui"
+	"github.com/bjornslib/tmux-nav/notify"
+	"github.com/bjornslib/tmux-nav/plugin"
+	"github.com/bjornslib/tmux-nav/trace"
+)
+
+// cfg is the user configuration, loaded once at startup.
+var cfg config.Config
+
+// loadConfig reads the config file and registers anything it defines with
+// the packages that need it.
+func loadConfig() {
+	c, err := config.Load()
+	if err != nil {
+		die("config:", err)
+	}
+	cfg = c
+
+	templates := make([]attach.Template, len(cfg.Attach.Templates))
+	for i, t := range cfg.Attach.Templates {
+		templates[i] = attach.Template{Name: t.Name, Cmd: t.Cmd, Background: t.Background}
+	}
+	attach.RegisterTemplates(templates)
+	attach.SetTerminal(cfg.Attach.Terminal)
+	attach.SetITermProfile(cfg.Attach.Profile)
+	agent.SetNamePatterns(cfg.Agents.NamePatterns)
+	if err := agent.SetNameScheme(cfg.Agents.NameScheme); err != nil {
+		die("config:", err)
+	}
+	agent.SetCapture(cfg.Agents.Capture)
+	agent.SetStuckAfter(cfg.Agents.StuckAfter)
+	agent.SetSupervise(cfg.Agents.Supervise, cfg.Agents.MaxRestarts)
+	agent.SetArchiveAfter(cfg.Agents.ArchiveAfter)
+	agent.SetArchiveOnKill(cfg.Agents.ArchiveOnKill)
+	if cfg.Debug.Trace || cfg.Debug.TraceFile != "" {
+		if err := trace.Start(cfg.Debug.TraceFile); err != nil {
+			die("trace:", err)
+		}
+	}
+
+	projects := make([]agent.Project, len(cfg.Agents.Projects))
+	for i, p := range cfg.Agents.Projects {
+		l := p.Limits
+		projects[i] = agent.Project{
+			Name:    p.Name,
+			Dir:     p.Dir,
+			Command: p.Command,
+			Env:     p.Env,
+			Limits:  agent.Limits{Nice: l.Nice, IOClass: l.IO, CPU: l.CPU, Memory: l.Memory, Cgroup: l.Cgroup},
+		}
+	}
+	agent.RegisterProjects(projects)
+}
+
+// overrideFlags applies the command-line flags that override settings,
+// given as flag → setting key, on top of the loaded configuration.
+func overrideFlags(args cliArgs, flags map[string]string) {
+	for flag, key := range flags {
+		if v, ok := args.flags[flag]; ok {
+			if err := cfg.Set(key, v, config.FromFlag); err != nil {
+				die(fmt.Sprintf("--%s:", flag), err)
+			}
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		die(err.Error(), nil)
+	}
+}
+
+// attachFlags applies the flags of commands that attach, --strategy and
+// --profile.
+func attachFlags(args cliArgs) {
+	overrideFlags(args, map[string]string{"strategy": "attach.strategy", "profile": "attach.profile"})
+	attach.SetITermProfile(cfg.Attach.Profile)
+}
+
+// newNotifier builds the notifier configured under [notify], or nil when no
+// sink is enabled.
+func newNotifier() *notify.Notifier {
+	sinks := notifySinks()
+	if len(sinks) == 0 {
+		return nil
+	}
+	return notify.New(notifyRules(), append(sinks, logSink{})...)
+}
+
+// notifySinks returns the sinks enabled under [notify].
+func notifySinks() []notify.Sink {
+	var sinks []notify.Sink
+	if cfg.Notify.Desktop {
+		sinks = append(sinks, notify.Desktop{})
+	}
+	for _, w := range cfg.Notify.Webhooks {
+		sinks = append(sinks, notify.Webhook{URL: w.URL, Format: w.Format, Headers: w.Headers})
+	}
+	return sinks
+}
+
+// notifyRules returns the rules configured under [notify], or nil for the
+// defaults.
+func notifyRules() []notify.Rule {
+	var rules []notify.Rule
+	for _, r := range cfg.Notify.Rules {
+		rules = append(rules, notify.Rule{Kind: r.Event, After: r.After, Match: r.Match})
+	}
+	return rules
+}
+
+// macros converts the configured reply macros, or returns nil to keep the
+// TUI's defaults.
+func macros() []navui.Macro {
+	if len(cfg.Macros) == 0 {
+		return nil
+	}
+	out := make([]navui.Macro, len(cfg.Macros))
+	for i, mac := range cfg.Macros {
+		out[i] = navui.Macro{Name: mac.Name, Key: mac.Key, Keys: mac.Keys, Text: mac.Text}
+	}
+	return out
+}
+
+// keymap applies the configured [keys] to the default bindings, checking
+// that the configured macros stay reachable under them.
+func keymap() navui.Keymap {
+	km, err := navui.NewKeymap(cfg.Keys)
+	if err != nil {
+		die("config:", err)
+	}
+	if err := km.CheckMacros(macros()); err != nil {
+		die("config:", err)
+	}
+	return km
+}
+
+// theme is the configured [tui] theme with its colour overrides.
+func theme() navui.Theme {
+	t, err := navui.NewTheme(cfg.TUI.Theme, cfg.TUI.Colors)
+	if err != nil {
+		die("config:", err)
+	}
+	return t
+}
+
+// plugins describes the configured plugins. One that fails to answer is
+// reported and left out rather than keeping the navigator from starting.
+func plugins() []*plugin.Plugin {
+	var out []*plugin.Plugin
+	for _, pc := range cfg.Plugins {
+		p, err := plugin.Load(pc.Name, pc.Command)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// logSink records sent notifications in the event log, where the log view
+// and serve's metrics count them.
+type logSink struct{}
+
+func (logSink) Send(e notify.Event) error {
+	return eventlog.Append(e.Session, "notify", "%s", e.Text())
+}
+
+// pickStrategy returns the configured attach strategy, or the detected one
+// when none is configured.
+func pickStrategy() attach.Strategy {
+	if cfg.Attach.Strategy == "" {
+		return attach.DetectStrategy()
+	}
+	s, err := attach.ParseStrategy(cfg.Attach.Strategy)
+	if err != nil {
+		die("config:", err)
+	}
+	return s
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reopenTTY makes the controlling terminal our stdin again, for commands
+// whose stdin was a pipe but which go on to run something interactive.
+func reopenTTY() error {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+	return unix.Dup2(int(tty.Fd()), int(os.Stdin.Fd()))
+}
@@ -0,0 +1,6 @@
+//go:build windows
+
+package main
+
+// reopenTTY is a no-op on Windows, where tmux runs under WSL instead.
+func reopenTTY() error { return nil }
@@ -0,0 +1,499 @@
+// Package config loads the user's tmux-nav configuration from
+// $XDG_CONFIG_HOME/tmux-nav/config.toml (default ~/.config/tmux-nav),
+// overlaid with the declarative harness file, harness.yaml, beside it.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bjornslib/tmux-nav/schedule"
+)
+
+// Config is the parsed configuration file. The zero value is a valid
+// configuration using built-in defaults everywhere; Load fills them in.
+type Config struct {
+	Attach Attach `toml:"attach"`
+	Agents Agents `toml:"agents"`
+	Notify Notify `toml:"notify"`
+	// Schedules are agent runs `tmux-nav serve` launches on cron schedules.
+	Schedules []Schedule `toml:"schedules"`
+	// Macros are canned replies bound to keys in the needs-attention view;
+	// none configured keeps the built-in continue / yes to all / stop and
+	// summarize.
+	Macros []Macro `toml:"macros"`
+	TUI    TUI     `toml:"tui"`
+	// Keys rebinds navigator actions, e.g. down = ["n", "down"]; `tmux-nav
+	// keys` lists the actions and their effective keys.
+	Keys map[string][]string `toml:"keys"`
+	// Plugins are executables adding list columns, preview tabs and
+	// actions to the navigator; see package plugin.
+	Plugins []Plugin `toml:"plugins"`
+	Serve   Serve    `toml:"serve"`
+	Prune   Prune    `toml:"prune"`
+	Debug   Debug    `toml:"debug"`
+
+	origin map[string]Source // layer of each overridden setting, by key
+}
+
+// Debug configures diagnostics.
+type Debug struct {
+	// Trace records the duration of every tmux call and navigator update,
+	// logging slow ones and a summary to the event log.
+	Trace bool `toml:"trace"`
+	// TraceFile, if set, also receives every span as a JSON line; setting
+	// it turns tracing on.
+	TraceFile string `toml:"trace_file"`
+}
+
+// Serve configures the `tmux-nav serve` daemon.
+type Serve struct {
+	// Metrics is the address /metrics is served on; "off" disables it.
+	// It has no authentication and names every session, so keep it on
+	// localhost unless the network is trusted.
+	Metrics string `toml:"metrics"`
+	// Addr is the address the JSON API (/api/...) is served on; "off"
+	// disables it.
+	Addr string `toml:"addr"`
+	// Token is the bearer token API requests must carry. The API doesn't
+	// start without one. It grants a shell: holders can create sessions
+	// running any command and type into existing ones, so treat it like
+	// an SSH key and don't serve the API beyond localhost without TLS in
+	// front.
+	Token string `toml:"token" env:"TMUX_NAV_API_TOKEN"`
+}
+
+// Prune configures `tmux-nav prune` and the navigator's prune action,
+// which kill detached sessions left idle.
+type Prune struct {
+	// After is how long a detached session must have been idle to be
+	// pruned. Zero uses the built-in default, a day.
+	After time.Duration `toml:"after"`
+	// Exclude lists session-name globs never pruned, e.g. "dotfiles".
+	Exclude []string `toml:"exclude"`
+}
+
+// Plugin declares a navigator plugin, e.g.
+//
+//	[[plugins]]
+//	name    = "jira"
+//	command = "~/bin/tmux-nav-jira"
+type Plugin struct {
+	Name    string `toml:"name"`
+	Command string `toml:"command"`
+}
+
+// TUI configures the interactive navigator.
+type TUI struct {
+	// PreviewMaxBytes caps each cached preview; longer captures keep their
+	// tail. Zero uses the built-in limit.
+	PreviewMaxBytes int `toml:"preview_max_bytes"`
+	// Split is the session list's share of the screen in percent, 20 to
+	// 80; the preview gets the rest. < and > adjust it in the navigator.
+	Split int `toml:"split"`
+	// StackBelow is the terminal width, in columns, under which the preview
+	// moves below the session list; zero keeps them side by side.
+	StackBelow int `toml:"stack_below"`
+	// PreviewLines is how many lines of a pane the preview captures.
+	PreviewLines int `toml:"preview_lines"`
+	// Refresh is how often the session list is polled while things change;
+	// it backs off from there while nothing does.
+	Refresh time.Duration `toml:"refresh"`
+	// Ignore lists session-name globs the navigator hides, e.g. "_*".
+	Ignore []string `toml:"ignore"`
+	// Theme is the navigator's palette: dark (the default), light or
+	// high-contrast.
+	Theme string `toml:"theme"`
+	// Colors override single colours of the theme, e.g. selected = "#d75f00".
+	Colors map[string]string `toml:"colors"`
+}
+
+// Macro is a canned reply to an agent, e.g.
+//
+//	[[macros]]
+//	name = "stop and summarize"
+//	key  = "s"
+//	keys = ["Escape"]            # tmux keys sent first (optional)
+//	text = "Stop and summarize your progress."
+type Macro struct {
+	Name string   `toml:"name" yaml:"name"`
+	Key  string   `toml:"key" yaml:"key"`
+	Keys []string `toml:"keys" yaml:"keys"`
+	Text string   `toml:"text" yaml:"text"`
+}
+
+// Schedule launches an agent from a project on a cron schedule, e.g.
+//
+//	[[schedules]]
+//	name    = "nightly-deps"
+//	cron    = "0 2 * * *"      # or @hourly, @daily, @nightly, @weekly
+//	project = "api"
+//	task    = "Update dependencies and open a PR"
+//	worktree = true
+type Schedule struct {
+	Name     string `toml:"name" yaml:"name"`
+	Cron     string `toml:"cron" yaml:"cron"`
+	Project  string `toml:"project" yaml:"project"`
+	Task     string `toml:"task" yaml:"task"`
+	Worktree bool   `toml:"worktree" yaml:"worktree"`
+}
+
+// Notify configures notifications about agent events.
+type Notify struct {
+	// Desktop enables native desktop notifications.
+	Desktop bool `toml:"desktop" yaml:"desktop"`
+	// Rules select which events notify; empty uses the defaults (finished,
+	// error, and waiting for over a minute).
+	Rules []Rule `toml:"rules" yaml:"rules"`
+	// Webhooks receive every notified event as an HTTP POST.
+	Webhooks []Webhook `toml:"webhooks" yaml:"webhooks"`
+}
+
+// Webhook is an HTTP notification target, e.g.
+//
+//	[[notify.webhooks]]
+//	url    = "https://hooks.slack.com/services/..."
+//	format = "slack"    # or "json" (default)
+type Webhook struct {
+	URL     string            `toml:"url" yaml:"url"`
+	Format  string            `toml:"format" yaml:"format"`
+	Headers map[string]string `toml:"headers" yaml:"headers"`
+}
+
+// Rule selects agent events to notify about, e.g.
+//
+//	[[notify.rules]]
+//	event = "waiting"   # finished | error | waiting
+//	after = "2m"        # waiting only: how long before notifying
+//	match = "agent-*"   # optional session-name glob
+type Rule struct {
+	Event string        `toml:"event" yaml:"event"`
+	After time.Duration `toml:"after" yaml:"after"`
+	Match string        `toml:"match" yaml:"match"`
+}
+
+// Agents configures how Claude Code agent sessions are recognised.
+type Agents struct {
+	// NamePatterns are session-name globs marking agent sessions. Nil keeps
+	// the built-in harness patterns.
+	NamePatterns []string `toml:"name_patterns"`
+	// NameScheme is the harness's naming convention for agent sessions,
+	// e.g. "agent/{project}/{task}". Spawned agents follow it, explicit
+	// names must conform, and `agent fix-names` renames the rest.
+	NameScheme string `toml:"name_scheme"`
+	// Projects are the templates `agent new <project>` spawns from.
+	Projects []Project `toml:"projects"`
+	// Templates are shared agent settings projects can inherit.
+	Templates []AgentTemplate `toml:"templates"`
+	// Capture continuously logs every agent session's pane output to
+	// ~/.local/share/tmux-nav/logs/<session>/ via pipe-pane.
+	Capture bool `toml:"capture"`
+	// StuckAfter flags working agents whose output hasn't changed for this
+	// long (default 10m).
+	StuckAfter time.Duration `toml:"stuck_after"`
+	// Supervise respawns agent sessions whose command crashed, up to
+	// MaxRestarts times (default 3), logging each restart.
+	Supervise   bool `toml:"supervise"`
+	MaxRestarts int  `toml:"max_restarts"`
+	// MaxAgents caps how many agent sessions `dispatch` lets exist before
+	// queued tasks wait for an idle agent (default 4).
+	MaxAgents int `toml:"max_agents"`
+	// PRStatus shows the pull request, CI and review state of agent
+	// worktree branches in the TUI, queried through the gh CLI.
+	PRStatus bool `toml:"pr_status"`
+	// ArchiveAfter archives (scrollback, transcript, metadata) and kills
+	// detached agents that have been idle this long after finishing work.
+	// Zero disables auto-archiving.
+	ArchiveAfter time.Duration `toml:"archive_after"`
+	// ArchiveOnKill saves the whole scrollback of every pane of a session
+	// under ~/.local/share/tmux-nav/transcripts/ before tmux-nav kills it.
+	ArchiveOnKill bool `toml:"archive_on_kill"`
+}
+
+// Project is a harness template for spawning agents, e.g.
+//
+//	[[agents.projects]]
+//	name    = "api"
+//	dir     = "~/code/api"
+//	command = "claude --model opus"
+//	env     = { LOG_LEVEL = "debug" }
+//	limits  = { nice = 10, io = "idle", cpu = 200, memory = "4G", cgroup = true }
+//
+// A project naming a template inherits its command, env and limits where
+// it doesn't set them itself.
+type Project struct {
+	Name     string            `toml:"name" yaml:"name"`
+	Dir      string            `toml:"dir" yaml:"dir"`
+	Command  string            `toml:"command" yaml:"command"`
+	Env      map[string]string `toml:"env" yaml:"env"`
+	Limits   Limits            `toml:"limits" yaml:"limits"`
+	Template string            `toml:"template" yaml:"template"`
+}
+
+// AgentTemplate holds agent settings shared by several projects, e.g.
+//
+//	[[agents.templates]]
+//	name    = "opus"
+//	command = "claude --model opus"
+type AgentTemplate struct {
+	Name    string            `toml:"name" yaml:"name"`
+	Command string            `toml:"command" yaml:"command"`
+	Env     map[string]string `toml:"env" yaml:"env"`
+	Limits  Limits            `toml:"limits" yaml:"limits"`
+}
+
+// Limits caps an agent's resources: niceness, ionice class, CPU percent
+// (via cpulimit, or a cgroup quota) and, with cgroup, a memory maximum.
+type Limits struct {
+	Nice   int    `toml:"nice" yaml:"nice"`
+	IO     string `toml:"io" yaml:"io"`
+	CPU    int    `toml:"cpu" yaml:"cpu"`
+	Memory string `toml:"memory" yaml:"memory"`
+	Cgroup bool   `toml:"cgroup" yaml:"cgroup"`
+}
+
+// Attach configures how sessions are attached to.
+type Attach struct {
+	// Strategy names the default attach strategy (built-in or a template
+	// name). Empty means auto-detect.
+	Strategy string `toml:"strategy" env:"TMUX_NAV_STRATEGY"`
+	// Terminal is the command template the new-terminal strategy opens,
+	// e.g. `foot -e {{.Cmd}}`.
+	Terminal string `toml:"terminal" env:"TMUX_NAV_TERMINAL"`
+	// Profile is the iTerm2 profile the new-tab and new-window strategies
+	// open with; empty means the default profile.
+	Profile string `toml:"profile"`
+	// Templates defines custom strategies as shell command templates.
+	Templates []Template `toml:"templates"`
+}
+
+// Template is a user-defined attach strategy, e.g.
+//
+//	[[attach.templates]]
+//	name = "ssh-jump"
+//	cmd  = "ssh -t jump {{q .Cmd}}"
+type Template struct {
+	Name string `toml:"name"`
+	Cmd  string `toml:"cmd"`
+	// Background starts the command without handing over the terminal,
+	// for templates that open a new GUI window or tab.
+	Background bool `toml:"background"`
+}
+
+// Dir returns the tmux-nav configuration directory.
+func Dir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "tmux-nav")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "tmux-nav")
+}
+
+// Path returns the location of the configuration file.
+func Path() string {
+	return filepath.Join(Dir(), "config.toml")
+}
+
+// Load layers the configuration: built-in defaults, then the configuration
+// file overlaid with the harness file, then the environment (see
+// Settings). Missing files are not an error and yield the defaults.
+// Command-line flags go on top with Set, followed by Validate.
+func Load() (Config, error) {
+	cfg := Defaults()
+	if err := decodeFile(Path(), &cfg); err != nil {
+		return Config{}, err
+	}
+	h, err := LoadHarness(HarnessPath())
+	if err != nil {
+		return Config{}, err
+	}
+	if h != nil {
+		before := cfg.values()
+		h.apply(&cfg)
+		cfg.markChanged(before, FromFile)
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return Config{}, err
+	}
+	return cfg.finish()
+}
+
+// LoadFile reads the configuration from path over the defaults.
+func LoadFile(path string) (Config, error) {
+	cfg := Defaults()
+	if err := decodeFile(path, &cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg.finish()
+}
+
+func decodeFile(path string, cfg *Config) error {
+	md, err := toml.DecodeFile(path, cfg)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("config %s: %w", path, err)
+	}
+	cfg.markFile(md)
+	return nil
+}
+
+// finish resolves project templates and validates the result.
+func (c Config) finish() (Config, error) {
+	if err := c.validate(); err != nil {
+		return Config{}, err
+	}
+	templates := make(map[string]AgentTemplate, len(c.Agents.Templates))
+	for _, t := range c.Agents.Templates {
+		templates[t.Name] = t
+	}
+	projects := make([]Project, len(c.Agents.Projects))
+	for i, p := range c.Agents.Projects {
+		if t, ok := templates[p.Template]; ok {
+			if p.Command == "" {
+				p.Command = t.Command
+			}
+			if p.Limits == (Limits{}) {
+				p.Limits = t.Limits
+			}
+			env := maps.Clone(t.Env)
+			if env == nil {
+				env = map[string]string{}
+			}
+			maps.Copy(env, p.Env)
+			p.Env = env
+		}
+		projects[i] = p
+	}
+	c.Agents.Projects = projects
+	return c, nil
+}
+
+func (c Config) validate() error {
+	seen := map[string]bool{}
+	for i, t := range c.Attach.Templates {
+		if t.Name == "" || t.Cmd == "" {
+			return fmt.Errorf("config: attach.templates[%d] needs both name and cmd", i)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("config: duplicate attach template %q", t.Name)
+		}
+		seen[t.Name] = true
+	}
+	for i, r := range c.Notify.Rules {
+		switch r.Event {
+		case "finished", "error", "waiting":
+		default:
+			return fmt.Errorf("config: notify.rules[%d]: unknown event %q", i, r.Event)
+		}
+	}
+	for i, w := range c.Notify.Webhooks {
+		if w.URL == "" {
+			return fmt.Errorf("config: notify.webhooks[%d] needs a url", i)
+		}
+		if w.Format != "" && w.Format != "json" && w.Format != "slack" {
+			return fmt.Errorf("config: notify.webhooks[%d]: unknown format %q", i, w.Format)
+		}
+	}
+	templates := map[string]bool{}
+	for i, t := range c.Agents.Templates {
+		if t.Name == "" {
+			return fmt.Errorf("config: agents.templates[%d] needs a name", i)
+		}
+		templates[t.Name] = true
+	}
+	for _, n := range []struct {
+		key string
+		v   int
+	}{
+		{"agents.max_restarts", c.Agents.MaxRestarts},
+		{"agents.max_agents", c.Agents.MaxAgents},
+		{"tui.preview_max_bytes", c.TUI.PreviewMaxBytes},
+		{"tui.preview_lines", c.TUI.PreviewLines},
+		{"tui.stack_below", c.TUI.StackBelow},
+	} {
+		if n.v < 0 {
+			return fmt.Errorf("config: %s must not be negative", n.key)
+		}
+	}
+	if s := c.TUI.Split; s != 0 && (s < 20 || s > 80) {
+		return fmt.Errorf("config: tui.split must be between 20 and 80")
+	}
+	if c.TUI.Refresh < 0 {
+		return fmt.Errorf("config: tui.refresh must not be negative")
+	}
+	for i, p := range c.TUI.Ignore {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("config: tui.ignore[%d]: bad pattern %q", i, p)
+		}
+	}
+	if c.Prune.After < 0 {
+		return fmt.Errorf("config: prune.after must not be negative")
+	}
+	for i, p := range c.Prune.Exclude {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("config: prune.exclude[%d]: bad pattern %q", i, p)
+		}
+	}
+	plugins := map[string]bool{}
+	for i, p := range c.Plugins {
+		if p.Name == "" || p.Command == "" {
+			return fmt.Errorf("config: plugins[%d] needs both name and command", i)
+		}
+		if plugins[p.Name] {
+			return fmt.Errorf("config: duplicate plugin %q", p.Name)
+		}
+		plugins[p.Name] = true
+	}
+	keys := map[string]bool{}
+	for i, mac := range c.Macros {
+		if mac.Name == "" || mac.Key == "" || (mac.Text == "" && len(mac.Keys) == 0) {
+			return fmt.Errorf("config: macros[%d] needs name, key and text or keys", i)
+		}
+		if keys[mac.Key] {
+			return fmt.Errorf("config: duplicate macro key %q", mac.Key)
+		}
+		keys[mac.Key] = true
+	}
+	projects := map[string]bool{}
+	for i, p := range c.Agents.Projects {
+		if p.Template != "" && !templates[p.Template] {
+			return fmt.Errorf("config: agents.projects[%d]: unknown template %q", i, p.Template)
+		}
+		if p.Name == "" || p.Dir == "" {
+			return fmt.Errorf("config: agents.projects[%d] needs both name and dir", i)
+		}
+		if projects[p.Name] {
+			return fmt.Errorf("config: duplicate agent project %q", p.Name)
+		}
+		projects[p.Name] = true
+		if io := p.Limits.IO; io != "" && io != "idle" && io != "best-effort" {
+			return fmt.Errorf("config: agents.projects[%d]: unknown io class %q", i, io)
+		}
+		if p.Limits.Memory != "" && !p.Limits.Cgroup {
+			return fmt.Errorf("config: agents.projects[%d]: a memory limit needs cgroup = true", i)
+		}
+	}
+	names := map[string]bool{}
+	for i, sc := range c.Schedules {
+		if sc.Name == "" || sc.Cron == "" || sc.Project == "" {
+			return fmt.Errorf("config: schedules[%d] needs name, cron and project", i)
+		}
+		if names[sc.Name] {
+			return fmt.Errorf("config: duplicate schedule %q", sc.Name)
+		}
+		names[sc.Name] = true
+		if _, err := schedule.Parse(sc.Cron); err != nil {
+			return fmt.Errorf("config: schedules[%d]: %w", i, err)
+		}
+		if !projects[sc.Project] {
+			return fmt.Errorf("config: schedules[%d]: unknown project %q", i, sc.Project)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Harness is the declarative harness file, harness.yaml, read by the TUI,
+// the serve daemon and the orchestrator alike, e.g.
+//
+//	concurrency: 4
+//	templates:
+//	  - name: opus
+//	    command: claude --model opus
+//	    limits: {nice: 10, io: idle}
+//	projects:
+//	  - {name: api, dir: ~/code/api, template: opus}
+//	notify:
+//	  desktop: true
+//	  rules:
+//	    - {event: waiting, after: 2m}
+//	archive:
+//	  after: 2h
+//	schedules:
+//	  - {name: nightly-deps, cron: "@nightly", project: api, task: Update dependencies}
+//	tasks:
+//	  - {project: api, prompt: Refactor the auth middleware, worktree: true}
+//
+// Whatever it sets takes precedence over config.toml, and its lists replace
+// rather than extend those in config.toml. Its concurrency and tasks make
+// it a fleet file for `tmux-nav orchestrate` too.
+type Harness struct {
+	// Concurrency caps how many agents run at once.
+	Concurrency int             `yaml:"concurrency"`
+	Templates   []AgentTemplate `yaml:"templates"`
+	Projects    []Project       `yaml:"projects"`
+	Notify      *Notify         `yaml:"notify"`
+	Archive     ArchivePolicy   `yaml:"archive"`
+	Schedules   []Schedule      `yaml:"schedules"`
+	Macros      []Macro         `yaml:"macros"`
+	// A tasks list, as in fleet files, is read by the orchestrator alone.
+}
+
+// ArchivePolicy says when finished agents are archived.
+type ArchivePolicy struct {
+	// After is how long a detached agent idles after finishing work before
+	// it is archived.
+	After time.Duration `yaml:"after"`
+}
+
+// HarnessPath returns the location of the harness file: $TMUX_NAV_HARNESS,
+// or harness.yaml in the configuration directory.
+func HarnessPath() string {
+	if path := os.Getenv("TMUX_NAV_HARNESS"); path != "" {
+		return path
+	}
+	return filepath.Join(Dir(), "harness.yaml")
+}
+
+// LoadHarness reads the harness file at path. A missing file yields nil.
+func LoadHarness(path string) (*Harness, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var h Harness
+	if err := yaml.Unmarshal(b, &h); err != nil {
+		return nil, fmt.Errorf("harness %s: %w", path, err)
+	}
+	return &h, nil
+}
+
+// apply overlays the harness onto c.
+func (h *Harness) apply(c *Config) {
+	if h.Concurrency > 0 {
+		c.Agents.MaxAgents = h.Concurrency
+	}
+	if h.Templates != nil {
+		c.Agents.Templates = h.Templates
+	}
+	if h.Projects != nil {
+		c.Agents.Projects = h.Projects
+	}
+	if h.Notify != nil {
+		c.Notify = *h.Notify
+	}
+	if h.Archive.After > 0 {
+		c.Agents.ArchiveAfter = h.Archive.After
+	}
+	if h.Schedules != nil {
+		c.Schedules = h.Schedules
+	}
+	if h.Macros != nil {
+		c.Macros = h.Macros
+	}
+}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/dispatch"
+)
+
+// Source names the layer a setting's value came from. Later layers win:
+// defaults < config and harness files < environment < command-line flags.
+type Source string
+
+const (
+	FromDefault Source = "default"
+	FromFile    Source = "file"
+	FromEnv     Source = "env"
+	FromFlag    Source = "flag"
+)
+
+// Setting is a single-valued setting, addressed by its dotted key as in
+// the config file (e.g. "agents.max_agents").
+type Setting struct {
+	Key string
+	Env string // environment variable overriding it
+}
+
+// Defaults returns the built-in configuration.
+func Defaults() Config {
+	return Config{
+		Agents: Agents{
+			StuckAfter:  agent.DefaultStuckAfter,
+			MaxRestarts: agent.DefaultMaxRestarts,
+			MaxAgents:   dispatch.DefaultMaxAgents,
+		},
+		TUI: TUI{
+			PreviewMaxBytes: 256 << 10,
+			PreviewLines:    40,
+			Split:           50,
+			StackBelow:      100,
+			Refresh:         2 * time.Second,
+		},
+		Serve: Serve{Metrics: "localhost:9464", Addr: "off"},
+		Prune: Prune{After: agent.DefaultPruneAfter},
+	}
+}
+
+// Settings lists every single-valued setting in file order. Each can be
+// overridden by the environment variable TMUX_NAV_<SECTION>_<KEY>, e.g.
+// TMUX_NAV_AGENTS_MAX_AGENTS, unless it names another with an env tag.
+func Settings() []Setting {
+	var out []Setting
+	walk(reflect.ValueOf(&Config{}).Elem(), func(key string, f reflect.StructField, _ reflect.Value) {
+		env := f.Tag.Get("env")
+		if env == "" {
+			env = "TMUX_NAV_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		}
+		out = append(out, Setting{Key: key, Env: env})
+	})
+	return out
+}
+
+// walk calls fn for every scalar field in the sections of the config
+// struct v, with its dotted key.
+func walk(v reflect.Value, fn func(key string, f reflect.StructField, v reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sect := t.Field(i)
+		name := tomlName(sect)
+		if name == "" || sect.Type.Kind() != reflect.Struct {
+			continue
+		}
+		for j := 0; j < sect.Type.NumField(); j++ {
+			f := sect.Type.Field(j)
+			if key := tomlName(f); key != "" && scalar(f.Type) {
+				fn(name+"."+key, f, v.Field(i).Field(j))
+			}
+		}
+	}
+}
+
+func tomlName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+	return name
+}
+
+func scalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// field returns the field behind key.
+func (c *Config) field(key string) (reflect.Value, error) {
+	var found reflect.Value
+	walk(reflect.ValueOf(c).Elem(), func(k string, _ reflect.StructField, v reflect.Value) {
+		if k == key {
+			found = v
+		}
+	})
+	if !found.IsValid() {
+		return found, fmt.Errorf("unknown setting %q", key)
+	}
+	return found, nil
+}
+
+// Set parses value into the setting key, recording src as its origin.
+// Durations are written like "10m", booleans like "true".
+func (c *Config) Set(key, value string, src Source) error {
+	v, err := c.field(key)
+	if err != nil {
+		return err
+	}
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not true or false", key, value)
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not a number", key, value)
+		}
+		v.SetInt(int64(n))
+	default:
+		v.SetString(value)
+	}
+	c.setOrigin(key, src)
+	return nil
+}
+
+// Get returns the setting key formatted as in the config file.
+func (c Config) Get(key string) (string, error) {
+	v, err := c.field(key)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		return strconv.Quote(time.Duration(v.Int()).String()), nil
+	case v.Kind() == reflect.String:
+		return strconv.Quote(v.String()), nil
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// Origin returns the layer the setting key came from.
+func (c Config) Origin(key string) Source {
+	if src, ok := c.origin[key]; ok {
+		return src
+	}
+	return FromDefault
+}
+
+func (c *Config) setOrigin(key string, src Source) {
+	if c.origin == nil {
+		c.origin = map[string]Source{}
+	}
+	c.origin[key] = src
+}
+
+// values snapshots every setting, formatted, by key.
+func (c Config) values() map[string]string {
+	out := map[string]string{}
+	for _, s := range Settings() {
+		out[s.Key], _ = c.Get(s.Key)
+	}
+	return out
+}
+
+// markFile records the settings a config file defined as coming from it.
+func (c *Config) markFile(md toml.MetaData) {
+	for _, s := range Settings() {
+		sect, key, _ := strings.Cut(s.Key, ".")
+		if md.IsDefined(sect, key) {
+			c.setOrigin(s.Key, FromFile)
+		}
+	}
+}
+
+// markChanged records the settings that differ from before as coming from
+// src.
+func (c *Config) markChanged(before map[string]string, src Source) {
+	for key, v := range c.values() {
+		if before[key] != v {
+			c.setOrigin(key, src)
+		}
+	}
+}
+
+// applyEnv overrides settings from their environment variables. Empty
+// variables are ignored.
+func (c *Config) applyEnv() error {
+	for _, s := range Settings() {
+		if v := os.Getenv(s.Env); v != "" {
+			if err := c.Set(s.Key, v, FromEnv); err != nil {
+				return fmt.Errorf("%s: %w", s.Env, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks the configuration after overrides were applied on top of
+// a loaded one.
+func (c Config) Validate() error {
+	return c.validate()
+}
@@ -0,0 +1,76 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/config"
+)
+
+func TestLayers(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("TMUX_NAV_HARNESS", filepath.Join(dir, "harness.yaml"))
+	write := func(name, body string) {
+		if err := os.MkdirAll(config.Dir(), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(config.Dir(), name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("config.toml", "[agents]\nmax_restarts = 5\nstuck_after = \"20m\"\nmax_agents = 2\n")
+	if err := os.WriteFile(filepath.Join(dir, "harness.yaml"), []byte("concurrency: 6\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TMUX_NAV_AGENTS_STUCK_AFTER", "1h")
+	t.Setenv("TMUX_NAV_TERMINAL", "foot -e {{.Cmd}}")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("agents.max_agents", "9", config.FromFlag); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		key  string
+		want string
+		src  config.Source
+	}{
+		{"tui.preview_max_bytes", "262144", config.FromDefault},
+		{"tui.refresh", `"2s"`, config.FromDefault},
+		{"agents.max_restarts", "5", config.FromFile},
+		{"agents.stuck_after", `"1h0m0s"`, config.FromEnv},
+		{"attach.terminal", `"foot -e {{.Cmd}}"`, config.FromEnv},
+		{"agents.max_agents", "9", config.FromFlag},
+	} {
+		got, err := cfg.Get(tc.key)
+		if err != nil || got != tc.want || cfg.Origin(tc.key) != tc.src {
+			t.Errorf("%s = %s (%s), %v; want %s (%s)", tc.key, got, cfg.Origin(tc.key), err, tc.want, tc.src)
+		}
+	}
+	if cfg.Agents.StuckAfter != time.Hour {
+		t.Errorf("StuckAfter = %v, want 1h", cfg.Agents.StuckAfter)
+	}
+}
+
+func TestSetRejectsBadValues(t *testing.T) {
+	cfg := config.Defaults()
+	for key, value := range map[string]string{
+		"agents.max_agents":  "four",
+		"agents.stuck_after": "soon",
+		"agents.capture":     "maybe",
+		"agents.projects":    "api",
+	} {
+		if err := cfg.Set(key, value, config.FromFlag); err == nil {
+			t.Errorf("Set(%s, %q) succeeded", key, value)
+		}
+	}
+	if err := cfg.Set("agents.max_agents", "-1", config.FromFlag); err != nil || cfg.Validate() == nil {
+		t.Errorf("a negative max_agents passed validation")
+	}
+}
@@ -0,0 +1,112 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// DefaultMaxAgents is the agent limit when none is configured.
+const DefaultMaxAgents = 4
+
+// settle is how long a freshly assigned agent is left alone: it may still
+// show its idle prompt before it starts working on the task.
+const settle = 30 * time.Second
+
+// prompt and spawnAgent start a task on an existing or a new agent; tests
+// replace them to watch placement without typing into real panes.
+var (
+	prompt     = agent.SendPrompt
+	spawnAgent = spawn
+)
+
+// Drain assigns queued tasks, oldest first: each goes to an idle agent
+// session (of the task's project, if it names one), else to a newly
+// spawned agent while fewer than maxAgents agents exist. Tasks that can't
+// be placed stay queued, as do tasks that failed to start, whose errors are
+// joined into the returned error. It returns the tasks assigned by this
+// call.
+func Drain(maxAgents int) ([]Task, error) {
+	sessions, err := tmuxclient.ListSessions(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	var agents []tmuxclient.Session
+	for _, s := range sessions {
+		if agent.IsAgent(s) {
+			agents = append(agents, s)
+		}
+	}
+	states := agent.DetectAll(agents)
+
+	var done []Task
+	var errs []error
+	err = update(func(q *Queue) error {
+		busy := map[string]bool{}
+		for _, t := range q.Tasks {
+			if t.Status == Assigned && time.Since(t.Assigned) < settle {
+				busy[t.Session] = true
+			}
+		}
+		count := len(agents)
+		for i := range q.Tasks {
+			t := &q.Tasks[i]
+			if t.Status != Queued {
+				continue
+			}
+			session, err := assign(*t, agents, states, busy)
+			if err == nil && session == "" && t.Project != "" && count < maxAgents {
+				if session, err = spawnAgent(*t); err == nil {
+					count++
+				}
+			}
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if session == "" {
+				continue
+			}
+			busy[session] = true
+			t.Status, t.Session, t.Assigned = Assigned, session, time.Now()
+			done = append(done, *t)
+		}
+		return nil
+	})
+	if err != nil {
+		return done, err
+	}
+	return done, errors.Join(errs...)
+}
+
+// assign prompts the first idle, unclaimed agent eligible for t and returns
+// its name, or "" when none is free.
+func assign(t Task, agents []tmuxclient.Session, states map[string]agent.State, busy map[string]bool) (string, error) {
+	for _, s := range agents {
+		if busy[s.Name] || states[s.Name] != agent.StateIdle {
+			continue
+		}
+		if t.Project != "" && agent.ProjectOf(s) != t.Project {
+			continue
+		}
+		if err := prompt(s.Name, t.Text); err != nil {
+			return "", fmt.Errorf("task %d → %s: %w", t.ID, s.Name, err)
+		}
+		_ = tmuxclient.SetOption(context.Background(), s.Name, "@task", t.Text)
+		return s.Name, nil
+	}
+	return "", nil
+}
+
+// spawn starts a new agent for t from its project.
+func spawn(t Task) (string, error) {
+	p, err := agent.LookupProject(t.Project)
+	if err != nil {
+		return "", fmt.Errorf("task %d: %w", t.ID, err)
+	}
+	return agent.Spawn(agent.Spec{Project: p, Task: t.Text})
+}
@@ -0,0 +1,127 @@
+package dispatch
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+)
+
+// agentLine is a list-sessions line for an agent of project whose hooks
+// last reported state; an empty project makes a plain shell session.
+func agentLine(name, project, state string) string {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	cmd := "claude"
+	if project == "" {
+		cmd = "zsh"
+	}
+	return strings.Join([]string{name, "1", "", now, "0.0", cmd, "/src/" + name,
+		project, "", "", "", "0", state, now, "0", "", "0", now, "", "50", "0"}, "|^|")
+}
+
+// fakeAgents serves sessions to Drain and records what it starts: prompts
+// as "session: text", spawns as "spawn project: text".
+func fakeAgents(t *testing.T, lines ...string) *[]string {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	f := tmuxtest.New().
+		On("list-sessions", strings.Join(lines, "\n")+"\n", nil).
+		On("set-option", "", nil)
+	tmuxtest.Install(t, f)
+	tmuxclient.SetCacheTTL(0)
+	t.Cleanup(func() { tmuxclient.SetCacheTTL(tmuxclient.DefaultCacheTTL) })
+
+	var started []string
+	savedPrompt, savedSpawn := prompt, spawnAgent
+	t.Cleanup(func() { prompt, spawnAgent = savedPrompt, savedSpawn })
+	prompt = func(session, text string) error {
+		started = append(started, session+": "+text)
+		return nil
+	}
+	spawnAgent = func(task Task) (string, error) {
+		started = append(started, "spawn "+task.Project+": "+task.Text)
+		return task.Project + "-new", nil
+	}
+	return &started
+}
+
+func enqueue(t *testing.T, tasks ...[2]string) {
+	t.Helper()
+	for _, task := range tasks {
+		if _, err := Enqueue(task[0], task[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDrainPlacement(t *testing.T) {
+	started := fakeAgents(t,
+		agentLine("api-1", "api", "idle"),
+		agentLine("api-2", "api", "working"),
+		agentLine("web-1", "web", "idle"),
+		agentLine("notes", "", ""))
+	enqueue(t,
+		[2]string{"fix the docs", "web"},
+		[2]string{"add an endpoint", "api"},
+		[2]string{"add tests", "api"},
+		[2]string{"tidy up", ""})
+
+	done, err := Drain(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"web-1: fix the docs", "api-1: add an endpoint", "spawn api: add tests"}
+	if strings.Join(*started, "\n") != strings.Join(want, "\n") {
+		t.Errorf("started %q, want %q", *started, want)
+	}
+	if len(done) != 3 || done[2].Session != "api-new" {
+		t.Errorf("Drain returned %+v, want the three placed tasks", done)
+	}
+	q, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := q.Tasks[3]; last.Status != Queued || last.Session != "" {
+		t.Errorf("task without a project = %+v, want it left queued: every idle agent is taken and it can't spawn one", last)
+	}
+
+	// The agents still look idle, but were just given work.
+	*started = nil
+	if done, err := Drain(4); err != nil || len(done) != 0 || len(*started) != 0 {
+		t.Errorf("second Drain placed %+v (started %q), %v; want nothing while the agents settle", done, *started, err)
+	}
+}
+
+func TestDrainAgentLimit(t *testing.T) {
+	started := fakeAgents(t, agentLine("api-1", "api", "working"), agentLine("api-2", "api", "waiting"))
+	enqueue(t, [2]string{"add tests", "api"})
+
+	if done, err := Drain(2); err != nil || len(done) != 0 || len(*started) != 0 {
+		t.Errorf("Drain(2) with two busy agents placed %+v (started %q), %v; want nothing", done, *started, err)
+	}
+	if done, err := Drain(3); err != nil || len(done) != 1 || strings.Join(*started, "") != "spawn api: add tests" {
+		t.Errorf("Drain(3) placed %+v (started %q), %v; want a spawned agent", done, *started, err)
+	}
+}
+
+func TestDrainKeepsFailedTasks(t *testing.T) {
+	fakeAgents(t, agentLine("web-1", "web", "idle"))
+	prompt = func(string, string) error { return errors.New("pane is gone") }
+	enqueue(t, [2]string{"fix the docs", "web"})
+
+	done, err := Drain(1)
+	if err == nil || !strings.Contains(err.Error(), "pane is gone") || len(done) != 0 {
+		t.Errorf("Drain = %+v, %v; want the prompt error and nothing placed", done, err)
+	}
+	q, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Tasks[0].Status != Queued {
+		t.Errorf("failed task = %+v, want it still queued", q.Tasks[0])
+	}
+}
@@ -0,0 +1,108 @@
+// Package dispatch keeps a file-backed queue of tasks and hands them to
+// idle agent sessions, spawning new agents while under a concurrency limit.
+package dispatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/archive"
+)
+
+// Status is where a task is in the queue.
+type Status string
+
+const (
+	Queued   Status = "queued"
+	Assigned Status = "assigned"
+)
+
+// Task is one queued unit of work.
+type Task struct {
+	ID       int       `json:"id"`
+	Text     string    `json:"text"`
+	Project  string    `json:"project,omitempty"` // restricts which agents may take it
+	Status   Status    `json:"status"`
+	Session  string    `json:"session,omitempty"` // agent it was assigned to
+	Created  time.Time `json:"created"`
+	Assigned time.Time `json:"assigned,omitempty"`
+}
+
+// Queue is the persisted task list, oldest first.
+type Queue struct {
+	NextID int    `json:"next_id"`
+	Tasks  []Task `json:"tasks"`
+}
+
+// Path returns the location of the queue file.
+func Path() string {
+	return filepath.Join(archive.DataDir(), "queue.json")
+}
+
+// Load reads the queue; a missing file is an empty queue.
+func Load() (Queue, error) {
+	var q Queue
+	b, err := os.ReadFile(Path())
+	if errors.Is(err, fs.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return q, err
+	}
+	if err := json.Unmarshal(b, &q); err != nil {
+		return q, fmt.Errorf("%s: %w", Path(), err)
+	}
+	return q, nil
+}
+
+// update applies fn to the queue under an exclusive lock, so concurrent
+// dispatchers (CLI, supervisor) don't lose each other's changes, and saves
+// the result unless fn fails.
+func update(fn func(*Queue) error) error {
+	if err := os.MkdirAll(filepath.Dir(Path()), 0o755); err != nil {
+		return err
+	}
+	lock, err := os.OpenFile(Path()+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("lock queue: %w", err)
+	}
+	defer unlockFile(lock)
+
+	q, err := Load()
+	if err != nil {
+		return err
+	}
+	if err := fn(&q); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := Path() + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, Path())
+}
+
+// Enqueue adds a task to the end of the queue.
+func Enqueue(text, project string) (Task, error) {
+	var t Task
+	err := update(func(q *Queue) error {
+		q.NextID++
+		t = Task{ID: q.NextID, Text: text, Project: project, Status: Queued, Created: time.Now()}
+		q.Tasks = append(q.Tasks, t)
+		return nil
+	})
+	return t, err
+}
@@ -0,0 +1,63 @@
+package dispatch
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestEnqueue(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	if q, err := Load(); err != nil || len(q.Tasks) != 0 {
+		t.Fatalf("Load() without a queue file = %+v, %v; want an empty queue", q, err)
+	}
+	first, err := Enqueue("fix the docs", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Enqueue("tidy up", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID != 1 || second.ID != 2 || first.Status != Queued || first.Created.IsZero() {
+		t.Errorf("Enqueue returned %+v and %+v, want queued tasks 1 and 2", first, second)
+	}
+	q, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.NextID != 2 || len(q.Tasks) != 2 || q.Tasks[0].Text != "fix the docs" || q.Tasks[0].Project != "web" || q.Tasks[1].Text != "tidy up" {
+		t.Errorf("saved queue = %+v, want both tasks oldest first", q)
+	}
+}
+
+func TestEnqueueConcurrently(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	const n = 20
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Enqueue(fmt.Sprintf("task %d", i), ""); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	q, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Tasks) != n || q.NextID != n {
+		t.Fatalf("queue holds %d tasks, next ID %d; want %d of each (an update was lost)", len(q.Tasks), q.NextID, n)
+	}
+	seen := map[int]bool{}
+	for _, task := range q.Tasks {
+		if seen[task.ID] {
+			t.Errorf("ID %d assigned twice", task.ID)
+		}
+		seen[task.ID] = true
+	}
+}
@@ -0,0 +1,79 @@
+// Package eventlog records notable things tmux-nav did or observed (agent
+// restarts, failures) in an append-only JSON-lines file, shown by the TUI's
+// log view.
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/archive"
+)
+
+// Entry is one logged event.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Session string    `json:"session,omitempty"`
+	Kind    string    `json:"kind"`
+	Message string    `json:"message"`
+}
+
+var mu sync.Mutex
+
+// Path returns the location of the event log.
+func Path() string {
+	return filepath.Join(archive.DataDir(), "events.log")
+}
+
+// Append records an event, stamped with the current time.
+func Append(session, kind, format string, args ...any) error {
+	e := Entry{Time: time.Now(), Session: session, Kind: kind, Message: fmt.Sprintf(format, args...)}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(Path()), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(Path(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Tail returns the last n events, oldest first. A missing log is empty.
+func Tail(n int) ([]Entry, error) {
+	f, err := os.Open(Path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		entries = append(entries, e)
+		if len(entries) > n {
+			entries = entries[1:]
+		}
+	}
+	return entries, sc.Err()
+}
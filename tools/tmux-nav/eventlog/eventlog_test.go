@@ -0,0 +1,60 @@
+package eventlog_test
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/eventlog"
+)
+
+func TestAppendAndTail(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	if entries, err := eventlog.Tail(10); err != nil || len(entries) != 0 {
+		t.Fatalf("Tail without a log = %v, %v; want nothing", entries, err)
+	}
+	for i := range 5 {
+		if err := eventlog.Append("api", "restart", "restart %d after exit %d", i+1, 137); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A torn or foreign line is skipped.
+	f, err := os.OpenFile(eventlog.Path(), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{\"time\":\n")
+	f.Close()
+	if err := eventlog.Append("", "supervisor", "stopped"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := eventlog.Tail(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].Message != "restart 4 after exit 137" || entries[2].Kind != "supervisor" || entries[2].Session != "" {
+		t.Errorf("Tail(3) = %+v, want restarts 4 and 5 then the supervisor entry", entries)
+	}
+	if entries[0].Time.IsZero() || entries[2].Time.Before(entries[0].Time) {
+		t.Errorf("entries aren't stamped in order: %v, %v", entries[0].Time, entries[2].Time)
+	}
+}
+
+func TestAppendConcurrently(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := eventlog.Append("api", "restart", "restart %d", i); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if entries, err := eventlog.Tail(100); err != nil || len(entries) != 50 {
+		t.Errorf("Tail = %d entries, %v; want all 50 intact", len(entries), err)
+	}
+}
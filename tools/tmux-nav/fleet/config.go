@@ -0,0 +1,115 @@
+// Package fleet launches and supervises a fleet of agent sessions from a
+// declarative YAML file.
+package fleet
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the fleet file, e.g.
+//
+//	concurrency: 3
+//	poll: 10s
+//	backoff: 5m
+//	resume: true
+//	projects:
+//	  - name: api
+//	    dir: ~/code/api
+//	tasks:
+//	  - project: api
+//	    prompt: "Refactor the auth middleware"
+//	    worktree: true
+type Config struct {
+	// Concurrency caps how many agents run at once (default 2).
+	Concurrency int `yaml:"concurrency"`
+	// Poll is how often agent states are checked (default 10s).
+	Poll time.Duration `yaml:"poll"`
+	// Backoff is how long launches pause after an agent hits a rate limit
+	// (default 5m); every new rate-limited sighting extends the pause.
+	Backoff time.Duration `yaml:"backoff"`
+	// Resume tells rate-limited agents to continue once the pause ends.
+	Resume bool `yaml:"resume"`
+	// Projects are added to those defined in config.toml.
+	Projects []Project `yaml:"projects"`
+	Tasks    []Task    `yaml:"tasks"`
+}
+
+// Project mirrors agent.Project for fleet files.
+type Project struct {
+	Name    string            `yaml:"name"`
+	Dir     string            `yaml:"dir"`
+	Command string            `yaml:"command"`
+	Env     map[string]string `yaml:"env"`
+	Limits  Limits            `yaml:"limits"`
+}
+
+// Limits mirrors agent.Limits for fleet files, e.g.
+//
+//	limits: {nice: 10, io: idle, cpu: 200}
+type Limits struct {
+	Nice   int    `yaml:"nice"`
+	IO     string `yaml:"io"`
+	CPU    int    `yaml:"cpu"`
+	Memory string `yaml:"memory"`
+	Cgroup bool   `yaml:"cgroup"`
+}
+
+// Task is one unit of work handed to its own agent session.
+type Task struct {
+	Name     string `yaml:"name"` // optional session name
+	Project  string `yaml:"project"`
+	Prompt   string `yaml:"prompt"`
+	Worktree bool   `yaml:"worktree"`
+}
+
+// Load reads and validates a fleet file, applying defaults.
+func Load(path string) (Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var c Config
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = 2
+	}
+	if c.Poll <= 0 {
+		c.Poll = 10 * time.Second
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = 5 * time.Minute
+	}
+	if len(c.Tasks) == 0 {
+		return Config{}, fmt.Errorf("%s: no tasks", path)
+	}
+	for i, t := range c.Tasks {
+		if t.Project == "" || t.Prompt == "" {
+			return Config{}, fmt.Errorf("%s: tasks[%d] needs project and prompt", path, i)
+		}
+	}
+	return c, nil
+}
+
+// project resolves a task's project from the fleet file, then config.toml.
+func (c Config) project(name string) (agent.Project, error) {
+	for _, p := range c.Projects {
+		if p.Name == name {
+			l := p.Limits
+			return agent.Project{
+				Name:    p.Name,
+				Dir:     p.Dir,
+				Command: p.Command,
+				Env:     p.Env,
+				Limits:  agent.Limits{Nice: l.Nice, IOClass: l.IO, CPU: l.CPU, Memory: l.Memory, Cgroup: l.Cgroup},
+			}, nil
+		}
+	}
+	return agent.LookupProject(name)
+}
@@ -0,0 +1,62 @@
+package fleet
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFleet(t *testing.T, yaml string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fleet.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	c, err := Load(writeFleet(t, `
+concurrency: 3
+projects:
+  - name: api
+    dir: /src/api
+    command: claude --model opus
+    limits: {nice: 10, io: idle, cpu: 200}
+tasks:
+  - project: api
+    prompt: Refactor the auth middleware
+    worktree: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Concurrency != 3 || c.Poll != 10*time.Second || c.Backoff != 5*time.Minute {
+		t.Errorf("concurrency %d, poll %s, backoff %s; want 3 and the defaults", c.Concurrency, c.Poll, c.Backoff)
+	}
+	if len(c.Tasks) != 1 || !c.Tasks[0].Worktree || c.Tasks[0].Prompt != "Refactor the auth middleware" {
+		t.Errorf("tasks = %+v", c.Tasks)
+	}
+	p, err := c.project("api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Dir != "/src/api" || p.Command != "claude --model opus" || p.Limits.Nice != 10 || p.Limits.IOClass != "idle" || p.Limits.CPU != 200 {
+		t.Errorf("project = %+v, want the fleet file's", p)
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	for _, tt := range []struct{ yaml, want string }{
+		{"concurrency: [", "yaml"},
+		{"concurrency: 2", "no tasks"},
+		{"tasks:\n  - project: api", "tasks[0] needs project and prompt"},
+		{"tasks:\n  - prompt: fix it", "tasks[0] needs project and prompt"},
+	} {
+		if _, err := Load(writeFleet(t, tt.yaml)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Load(%q) = %v, want an error mentioning %q", tt.yaml, err, tt.want)
+		}
+	}
+}
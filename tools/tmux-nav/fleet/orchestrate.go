@@ -0,0 +1,249 @@
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// idleGrace is how long a freshly launched agent may sit idle before it is
+// considered finished; Claude Code shows an empty prompt briefly on start.
+const idleGrace = 30 * time.Second
+
+// Status is the lifecycle of a fleet task.
+type Status string
+
+const (
+	Pending Status = "pending"
+	Running Status = "running"
+	Done    Status = "done"
+	Failed  Status = "failed"
+)
+
+// resumePrompt is sent to rate-limited agents when the backoff ends.
+const resumePrompt = "continue"
+
+// spawnAgent launches a task's agent; tests replace it.
+var spawnAgent = agent.Spawn
+
+// run tracks one task through its lifecycle.
+type run struct {
+	task        Task
+	status      Status
+	session     string
+	state       agent.State
+	started     time.Time
+	seenWorking bool
+	resumed     time.Time // when resumePrompt was last sent
+}
+
+// backoff pauses launches while the API is rate limiting the fleet.
+type backoff struct {
+	until     time.Time
+	announced bool
+}
+
+// extend pushes the end of the pause to at least d from now.
+func (b *backoff) extend(d time.Duration) {
+	if until := time.Now().Add(d); until.After(b.until) {
+		b.until = until
+		b.announced = false
+	}
+}
+
+func (b *backoff) active() bool {
+	return time.Now().Before(b.until)
+}
+
+// Orchestrate launches the fleet's tasks as agent sessions, never exceeding
+// the concurrency limit, and supervises them until every task is done or
+// failed (or ctx is cancelled). A task is done when its agent returns to
+// the idle prompt, and failed when it errors or its session disappears.
+// While any agent is rate limited, new launches pause for the backoff
+// window; with Resume set, limited agents are told to continue after it.
+// Progress is written to out.
+func Orchestrate(ctx context.Context, c Config, out io.Writer) error {
+	runs := make([]*run, len(c.Tasks))
+	for i, t := range c.Tasks {
+		runs[i] = &run{task: t, status: Pending}
+	}
+
+	var pause backoff
+	ticker := time.NewTicker(c.Poll)
+	defer ticker.Stop()
+	for {
+		if err := step(c, runs, &pause, out); err != nil {
+			return err
+		}
+		if finished(runs) {
+			summarize(runs, out)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(out, "orchestrator stopped; running agents were left alone")
+			summarize(runs, out)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// step refreshes running tasks and launches pending ones into free slots.
+func step(c Config, runs []*run, pause *backoff, out io.Writer) error {
+	sessions, err := tmuxclient.ListSessions(context.Background())
+	if err != nil {
+		return err
+	}
+	live := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		live[s.Name] = true
+	}
+
+	active := 0
+	for _, r := range runs {
+		if r.status != Running {
+			continue
+		}
+		if !live[r.session] {
+			r.finish(Failed, out, "session exited")
+			continue
+		}
+		st, err := agent.Detect(r.session)
+		if err != nil {
+			continue
+		}
+		if st != r.state {
+			fmt.Fprintf(out, "%s  %-32s %s\n", clock(), r.session, st)
+			r.state = st
+		}
+		switch st {
+		case agent.StateWorking:
+			r.seenWorking = true
+		case agent.StateIdle:
+			if r.seenWorking || time.Since(r.started) > idleGrace {
+				r.finish(Done, out, "")
+				continue
+			}
+		case agent.StateErrored:
+			r.finish(Failed, out, "agent error")
+			continue
+		case agent.StateRateLimited:
+			pause.extend(c.Backoff)
+		}
+		active++
+	}
+
+	if pause.active() {
+		if !pause.announced {
+			fmt.Fprintf(out, "%s  rate limited: launches paused until %s\n", clock(), pause.until.Format("15:04:05"))
+			pause.announced = true
+		}
+	} else if c.Resume {
+		resume(runs, c.Backoff, out)
+	}
+
+	for _, r := range runs {
+		if pause.active() {
+			break
+		}
+		if active >= c.Concurrency {
+			break
+		}
+		if r.status != Pending {
+			continue
+		}
+		p, err := c.project(r.task.Project)
+		if err != nil {
+			r.finish(Failed, out, err.Error())
+			continue
+		}
+		name, err := spawnAgent(agent.Spec{
+			Project:  p,
+			Task:     r.task.Prompt,
+			Name:     r.task.Name,
+			Worktree: r.task.Worktree,
+		})
+		if err != nil {
+			r.finish(Failed, out, err.Error())
+			continue
+		}
+		r.status, r.session, r.started = Running, name, time.Now()
+		fmt.Fprintf(out, "%s  %-32s launched (%s)\n", clock(), name, r.task.Project)
+		active++
+	}
+
+	fmt.Fprintf(out, "%s  progress: %s\n", clock(), progress(runs))
+	return nil
+}
+
+// resume tells rate-limited agents to continue, at most once per backoff
+// window each.
+func resume(runs []*run, every time.Duration, out io.Writer) {
+	for _, r := range runs {
+		if r.status != Running || r.state != agent.StateRateLimited || time.Since(r.resumed) < every {
+			continue
+		}
+		if err := agent.SendPrompt(r.session, resumePrompt); err != nil {
+			fmt.Fprintf(out, "%s  %-32s resume failed: %v\n", clock(), r.session, err)
+			continue
+		}
+		r.resumed = time.Now()
+		fmt.Fprintf(out, "%s  %-32s resumed after rate limit\n", clock(), r.session)
+	}
+}
+
+func (r *run) finish(s Status, out io.Writer, reason string) {
+	r.status = s
+	name := r.session
+	if name == "" {
+		name = r.task.Project
+	}
+	line := fmt.Sprintf("%s  %-32s %s", clock(), name, s)
+	if !r.started.IsZero() {
+		line += " after " + time.Since(r.started).Round(time.Second).String()
+	}
+	if reason != "" {
+		line += ": " + reason
+	}
+	fmt.Fprintln(out, line)
+}
+
+func finished(runs []*run) bool {
+	for _, r := range runs {
+		if r.status == Pending || r.status == Running {
+			return false
+		}
+	}
+	return true
+}
+
+// progress formats counts per status, e.g. "2 running, 1 pending, 3 done".
+func progress(runs []*run) string {
+	counts := map[Status]int{}
+	for _, r := range runs {
+		counts[r.status]++
+	}
+	return fmt.Sprintf("%d running, %d pending, %d done, %d failed",
+		counts[Running], counts[Pending], counts[Done], counts[Failed])
+}
+
+func summarize(runs []*run, out io.Writer) {
+	fmt.Fprintln(out, "\n--- Summary ---")
+	for _, r := range runs {
+		name := r.session
+		if name == "" {
+			name = "(not launched)"
+		}
+		fmt.Fprintf(out, "  %-8s %-32s %s\n", r.status, name, r.task.Project)
+	}
+	fmt.Fprintf(out, "  %s\n", progress(runs))
+}
+
+func clock() string {
+	return time.Now().Format("15:04:05")
+}
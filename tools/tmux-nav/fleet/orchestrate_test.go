@@ -0,0 +1,116 @@
+package fleet
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+)
+
+// Pane captures that classify as each agent state.
+const (
+	working     = "✻ Refactoring… (esc to interrupt)\n"
+	idle        = "? for shortcuts\n"
+	errored     = "API Error: 500 internal server error\n"
+	rateLimited = "API Error: 429 rate_limit_error\n"
+)
+
+// sessionsLine is list-sessions output naming the live sessions.
+func sessionsLine(names ...string) string {
+	var b strings.Builder
+	for _, n := range names {
+		fmt.Fprintf(&b, "%s|^|1|^||^|1700000000|^|0.0|^|claude|^|/src|^|api|^||^||^||^|0|^||^||^|0|^||^|0|^|1690000000|^||^|50|^|0\n", n)
+	}
+	return b.String()
+}
+
+// fleet returns a config of n tasks and installs a fake spawner naming
+// agents agent-1, agent-2, ... in launch order.
+func fleet(t *testing.T, f *tmuxtest.Fake, concurrency, n int) (Config, []*run) {
+	t.Helper()
+	tmuxtest.Install(t, f)
+	tmuxclient.SetCacheTTL(0)
+	t.Cleanup(func() { tmuxclient.SetCacheTTL(tmuxclient.DefaultCacheTTL) })
+	launched := 0
+	saved := spawnAgent
+	t.Cleanup(func() { spawnAgent = saved })
+	spawnAgent = func(spec agent.Spec) (string, error) {
+		launched++
+		return fmt.Sprintf("agent-%d", launched), nil
+	}
+
+	c := Config{Concurrency: concurrency, Backoff: time.Minute, Projects: []Project{{Name: "api", Dir: "/src/api"}}}
+	runs := make([]*run, n)
+	for i := range runs {
+		c.Tasks = append(c.Tasks, Task{Project: "api", Prompt: fmt.Sprintf("task %d", i+1)})
+		runs[i] = &run{task: c.Tasks[i], status: Pending}
+	}
+	return c, runs
+}
+
+func statuses(runs []*run) string {
+	var s []string
+	for _, r := range runs {
+		s = append(s, r.session+"="+string(r.status))
+	}
+	return strings.Join(s, " ")
+}
+
+func TestStep(t *testing.T) {
+	f := tmuxtest.New().
+		On("list-sessions", "", nil).
+		On("list-sessions", sessionsLine("agent-1", "agent-2"), nil).
+		On("list-sessions", sessionsLine("agent-1"), nil).
+		On("capture-pane", working, nil). // agent-1
+		On("capture-pane", errored, nil). // agent-2
+		On("capture-pane", idle, nil)     // agent-1
+	c, runs := fleet(t, f, 2, 3)
+	var out strings.Builder
+	var pause backoff
+
+	steps := []string{
+		"agent-1=running agent-2=running =pending",
+		"agent-1=running agent-2=failed agent-3=running",
+		"agent-1=done agent-2=failed agent-3=failed",
+	}
+	for i, want := range steps {
+		if err := step(c, runs, &pause, &out); err != nil {
+			t.Fatal(err)
+		}
+		if got := statuses(runs); got != want {
+			t.Errorf("after step %d: %s, want %s", i+1, got, want)
+		}
+	}
+	if !finished(runs) {
+		t.Error("fleet not finished with every task done or failed")
+	}
+	for _, want := range []string{": agent error", ": session exited", "0 running, 0 pending, 1 done, 2 failed"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestStepPausesWhenRateLimited(t *testing.T) {
+	f := tmuxtest.New().
+		On("list-sessions", sessionsLine("agent-1"), nil).
+		On("capture-pane", rateLimited, nil)
+	c, runs := fleet(t, f, 2, 2)
+	runs[0].status, runs[0].session, runs[0].started = Running, "agent-1", time.Now()
+	var out strings.Builder
+	var pause backoff
+
+	if err := step(c, runs, &pause, &out); err != nil {
+		t.Fatal(err)
+	}
+	if runs[1].status != Pending || !pause.active() {
+		t.Errorf("task 2 is %s with pause active %v, want launches paused", runs[1].status, pause.active())
+	}
+	if !strings.Contains(out.String(), "rate limited: launches paused until") {
+		t.Errorf("output doesn't announce the pause:\n%s", out.String())
+	}
+}
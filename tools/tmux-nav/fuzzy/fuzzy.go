@@ -0,0 +1,98 @@
+// Package fuzzy matches what is typed against names, the way file pickers
+// do: the pattern's characters must appear in order, not necessarily
+// adjacent, ignoring case. "apw" matches "agent/api/fix-web".
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Score reports whether pattern matches s and how well: higher scores are
+// better matches. Runs of adjacent characters and characters starting a
+// word (after / - _ . : or a space) score higher, as does a match nearer
+// the start. An empty pattern matches everything with score 0.
+func Score(pattern, s string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+	p := []rune(strings.ToLower(pattern))
+	r := []rune(s)
+	best, found := 0, false
+	// Try the match starting at each occurrence of the first character,
+	// so "api" in "agent/api" scores the word, not the leading "a".
+	for start := range r {
+		if unicode.ToLower(r[start]) != p[0] {
+			continue
+		}
+		if score, ok := scoreFrom(p, r, start); ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
+
+// scoreFrom matches p against r greedily from start, where p's first
+// character is.
+func scoreFrom(p, r []rune, start int) (int, bool) {
+	score, pi, prev := 0, 0, -2
+	for i := start; i < len(r) && pi < len(p); i++ {
+		if unicode.ToLower(r[i]) != p[pi] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 4
+		}
+		if i == 0 || strings.ContainsRune("/-_.: ", r[i-1]) {
+			score += 3
+		}
+		prev = i
+		pi++
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+	return score*16 - min(start, 15), true
+}
+
+// Match reports whether pattern matches s.
+func Match(pattern, s string) bool {
+	_, ok := Score(pattern, s)
+	return ok
+}
+
+// Resolve picks the names a partial name can mean, the way tmux resolves
+// -t: an exact match alone, else the names it is a prefix of, else its
+// fuzzy matches, best first. Several results mean it is ambiguous.
+func Resolve(partial string, names []string) []string {
+	if slices.Contains(names, partial) {
+		return []string{partial}
+	}
+	var prefixed []string
+	for _, n := range names {
+		if strings.HasPrefix(n, partial) {
+			prefixed = append(prefixed, n)
+		}
+	}
+	if len(prefixed) > 0 || partial == "" {
+		return prefixed
+	}
+	type match struct {
+		name  string
+		score int
+	}
+	var matches []match
+	for _, n := range names {
+		if score, ok := Score(partial, n); ok {
+			matches = append(matches, match{n, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int { return b.score - a.score })
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = m.name
+	}
+	return out
+}
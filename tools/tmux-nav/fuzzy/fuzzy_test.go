@@ -0,0 +1,61 @@
+package fuzzy_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/fuzzy"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"", "anything", true},
+		{"apw", "agent/api/fix-web", true},
+		{"API", "agent/api/fix-web", true},
+		{"wpa", "agent/api/fix-web", false},
+		{"docs", "dotfiles", false},
+	}
+	for _, tt := range tests {
+		if got := fuzzy.Match(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestScoreRanksTighterMatchesHigher(t *testing.T) {
+	better := [][2]string{
+		{"api", "api"},       // over a scattered match
+		{"api", "agent/api"}, // a word start over mid-word
+		{"web", "web-perf"},  // earlier over later
+	}
+	worse := []string{"a-p-i", "agent/rapid", "agent/web"}
+	for i, b := range better {
+		hi, _ := fuzzy.Score(b[0], b[1])
+		lo, _ := fuzzy.Score(b[0], worse[i])
+		if hi <= lo {
+			t.Errorf("Score(%q, %q) = %d, not above %q's %d", b[0], b[1], hi, worse[i], lo)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	names := []string{"agent/api/fix-login", "agent/api/fix-web", "agent/web/docs", "api"}
+	tests := []struct {
+		partial string
+		want    []string
+	}{
+		{"api", []string{"api"}},                                            // exact beats prefix
+		{"agent/api/fix-l", []string{"agent/api/fix-login"}},                // unique prefix
+		{"agent/api", []string{"agent/api/fix-login", "agent/api/fix-web"}}, // ambiguous prefix
+		{"wdocs", []string{"agent/web/docs"}},                               // fuzzy
+		{"zzz", nil},
+	}
+	for _, tt := range tests {
+		if got := fuzzy.Resolve(tt.partial, names); !slices.Equal(got, tt.want) {
+			t.Errorf("Resolve(%q) = %q, want %q", tt.partial, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Package gh queries GitHub pull request status through the gh CLI.
+package gh
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// PR is the state of the pull request for a branch.
+type PR struct {
+	Number int    `json:"number"`
+	State  string `json:"state"` // OPEN, CLOSED or MERGED
+	Draft  bool   `json:"isDraft"`
+	// Review is the review decision: APPROVED, CHANGES_REQUESTED,
+	// REVIEW_REQUIRED or "".
+	Review string `json:"reviewDecision"`
+	URL    string `json:"url"`
+	// Checks summarises CI: "pass", "fail", "pending" or "" without checks.
+	Checks string `json:"-"`
+}
+
+// check is one entry of gh's statusCheckRollup: check runs report status
+// and conclusion, commit statuses report state.
+type check struct {
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	State      string `json:"state"`
+}
+
+// Lookup returns the pull request for branch in the repository at dir.
+// ok is false when the branch has no pull request.
+func Lookup(dir, branch string) (pr PR, ok bool, err error) {
+	cmd := exec.Command("gh", "pr", "view", branch,
+		"--json", "number,state,isDraft,reviewDecision,url,statusCheckRollup")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, isExit := err.(*exec.ExitError); isExit {
+			if strings.Contains(string(ee.Stderr), "no pull requests found") {
+				return PR{}, false, nil
+			}
+			return PR{}, false, fmt.Errorf("gh pr view: %s", strings.TrimSpace(string(ee.Stderr)))
+		}
+		return PR{}, false, fmt.Errorf("gh pr view: %w", err)
+	}
+	var raw struct {
+		PR
+		Rollup []check `json:"statusCheckRollup"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return PR{}, false, fmt.Errorf("gh pr view: %w", err)
+	}
+	pr = raw.PR
+	pr.Checks = summarize(raw.Rollup)
+	return pr, true, nil
+}
+
+// summarize folds check results into one word: any failure fails, else any
+// unfinished check is pending.
+func summarize(checks []check) string {
+	if len(checks) == 0 {
+		return ""
+	}
+	result := "pass"
+	for _, c := range checks {
+		switch strings.ToUpper(c.Conclusion + c.State) {
+		case "FAILURE", "ERROR", "CANCELLED", "TIMED_OUT", "ACTION_REQUIRED", "STARTUP_FAILURE":
+			return "fail"
+		case "PENDING", "EXPECTED", "":
+			result = "pending"
+		}
+		if c.Status != "" && c.Status != "COMPLETED" {
+			result = "pending"
+		}
+	}
+	return result
+}
+
+// Short renders the PR compactly for a list column, e.g. "#42 ✓ approved"
+// or "#7 draft ✗".
+func (p PR) Short() string {
+	parts := []string{fmt.Sprintf("#%d", p.Number)}
+	switch {
+	case p.State == "MERGED":
+		return parts[0] + " merged"
+	case p.State == "CLOSED":
+		return parts[0] + " closed"
+	case p.Draft:
+		parts = append(parts, "draft")
+	}
+	switch p.Checks {
+	case "pass":
+		parts = append(parts, "✓")
+	case "fail":
+		parts = append(parts, "✗")
+	case "pending":
+		parts = append(parts, "…")
+	}
+	switch p.Review {
+	case "APPROVED":
+		parts = append(parts, "approved")
+	case "CHANGES_REQUESTED":
+		parts = append(parts, "changes")
+	}
+	return strings.Join(parts, " ")
+}
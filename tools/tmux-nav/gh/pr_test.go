@@ -0,0 +1,89 @@
+package gh
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []check
+		want   string
+	}{
+		{"no checks", nil, ""},
+		{"all passed", []check{{Status: "COMPLETED", Conclusion: "SUCCESS"}, {State: "SUCCESS"}}, "pass"},
+		{"skipped and neutral pass", []check{{Status: "COMPLETED", Conclusion: "SKIPPED"}, {Status: "COMPLETED", Conclusion: "NEUTRAL"}}, "pass"},
+		{"a run in progress", []check{{Status: "COMPLETED", Conclusion: "SUCCESS"}, {Status: "IN_PROGRESS"}}, "pending"},
+		{"a pending status", []check{{State: "PENDING"}}, "pending"},
+		{"a failure beats pending", []check{{Status: "QUEUED"}, {Status: "COMPLETED", Conclusion: "FAILURE"}}, "fail"},
+		{"an errored status", []check{{State: "ERROR"}}, "fail"},
+		{"a timeout", []check{{Status: "COMPLETED", Conclusion: "TIMED_OUT"}}, "fail"},
+	}
+	for _, tt := range tests {
+		if got := summarize(tt.checks); got != tt.want {
+			t.Errorf("%s: summarize = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestShort(t *testing.T) {
+	tests := []struct {
+		pr   PR
+		want string
+	}{
+		{PR{Number: 42, State: "OPEN", Checks: "pass", Review: "APPROVED"}, "#42 ✓ approved"},
+		{PR{Number: 7, State: "OPEN", Draft: true, Checks: "fail"}, "#7 draft ✗"},
+		{PR{Number: 9, State: "OPEN", Checks: "pending", Review: "CHANGES_REQUESTED"}, "#9 … changes"},
+		{PR{Number: 3, State: "OPEN", Review: "REVIEW_REQUIRED"}, "#3"},
+		{PR{Number: 5, State: "MERGED", Checks: "fail"}, "#5 merged"},
+		{PR{Number: 6, State: "CLOSED", Draft: true}, "#6 closed"},
+	}
+	for _, tt := range tests {
+		if got := tt.pr.Short(); got != tt.want {
+			t.Errorf("%+v.Short() = %q, want %q", tt.pr, got, tt.want)
+		}
+	}
+}
+
+// fakeGH puts a gh on $PATH that prints stdout, prints stderr to its
+// standard error and exits with code.
+func fakeGH(t *testing.T, stdout, stderr string, code int) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake gh is a shell script")
+	}
+	dir := t.TempDir()
+	script := fmt.Sprintf("#!/bin/sh\nprintf '%%s' '%s'\nprintf '%%s' '%s' >&2\nexit %d\n", stdout, stderr, code)
+	if err := os.WriteFile(filepath.Join(dir, "gh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestLookup(t *testing.T) {
+	fakeGH(t, `{"number":42,"state":"OPEN","isDraft":false,"reviewDecision":"APPROVED","url":"https://github.com/o/r/pull/42",`+
+		`"statusCheckRollup":[{"status":"COMPLETED","conclusion":"SUCCESS"},{"state":"PENDING"}]}`, "", 0)
+	pr, ok, err := Lookup(t.TempDir(), "feature")
+	if err != nil || !ok {
+		t.Fatalf("Lookup = %v, %v", ok, err)
+	}
+	if pr.Number != 42 || pr.Review != "APPROVED" || pr.URL != "https://github.com/o/r/pull/42" || pr.Checks != "pending" {
+		t.Errorf("Lookup = %+v", pr)
+	}
+}
+
+func TestLookupWithoutPR(t *testing.T) {
+	fakeGH(t, "", `no pull requests found for branch "feature"`, 1)
+	if _, ok, err := Lookup(t.TempDir(), "feature"); ok || err != nil {
+		t.Errorf("Lookup = %v, %v; want no PR and no error", ok, err)
+	}
+
+	fakeGH(t, "", "HTTP 401: Bad credentials", 1)
+	if _, _, err := Lookup(t.TempDir(), "feature"); err == nil || err.Error() != "gh pr view: HTTP 401: Bad credentials" {
+		t.Errorf("Lookup = %v, want gh's error", err)
+	}
+}
@@ -0,0 +1,107 @@
+// Package git wraps the handful of git commands tmux-nav needs for
+// worktree-based agent sessions.
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// run executes git in dir and returns stdout without trailing whitespace
+// (leading indentation is significant in status output), folding stderr into
+// the error.
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimRight(string(out), " \n"), nil
+}
+
+// Toplevel returns the root of the working tree containing dir.
+func Toplevel(dir string) (string, error) {
+	return run(dir, "rev-parse", "--show-toplevel")
+}
+
+// Branch returns the checked-out branch in dir ("" when detached).
+func Branch(dir string) (string, error) {
+	b, err := run(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if b == "HEAD" {
+		b = ""
+	}
+	return b, err
+}
+
+// AddWorktree creates a worktree at path on branch, which is checked out
+// if it exists and otherwise created from base (HEAD when empty).
+func AddWorktree(repo, path, branch, base string) error {
+	if HasBranch(repo, branch) {
+		_, err := run(repo, "worktree", "add", path, branch)
+		return err
+	}
+	if base == "" {
+		base = "HEAD"
+	}
+	_, err := run(repo, "worktree", "add", "-b", branch, path, base)
+	return err
+}
+
+// HasBranch reports whether the repository has a local branch of this name.
+func HasBranch(repo, branch string) bool {
+	_, err := run(repo, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// RemoveWorktree removes the worktree at path. Without force, git refuses
+// to remove worktrees with uncommitted changes.
+func RemoveWorktree(repo, path string, force bool) error {
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	_, err := run(repo, append(args, path)...)
+	return err
+}
+
+// DeleteBranch deletes a local branch. Without force, git refuses to delete
+// unmerged branches.
+func DeleteBranch(repo, branch string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	_, err := run(repo, "branch", flag, branch)
+	return err
+}
+
+// MainRoot returns the root of the main working tree of the repository
+// containing dir, so that linked worktrees resolve to the same root.
+func MainRoot(dir string) (string, error) {
+	common, err := run(dir, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return "", err
+	}
+	if filepath.Base(common) == ".git" {
+		return filepath.Dir(common), nil
+	}
+	// Bare repository: the common dir is the repository itself.
+	return common, nil
+}
+
+// Status returns the short status of the working tree in dir, headed by
+// the branch and its upstream tracking line.
+func Status(dir string) (string, error) {
+	return run(dir, "status", "--short", "--branch")
+}
+
+// DiffStat returns the diffstat of uncommitted changes in dir, staged and
+// unstaged, against HEAD.
+func DiffStat(dir string) (string, error) {
+	return run(dir, "diff", "HEAD", "--stat")
+}
@@ -0,0 +1,122 @@
+package git_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/git"
+)
+
+// repo creates a repository with one commit on main and returns its root.
+func repo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	for k, v := range map[string]string{
+		"GIT_CONFIG_GLOBAL": os.DevNull, "GIT_CONFIG_NOSYSTEM": "1",
+		"GIT_AUTHOR_NAME": "test", "GIT_AUTHOR_EMAIL": "test@example.com",
+		"GIT_COMMITTER_NAME": "test", "GIT_COMMITTER_EMAIL": "test@example.com",
+	} {
+		t.Setenv(k, v)
+	}
+	// Resolve symlinks (macOS's /var → /private/var) to compare with git's paths.
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, dir, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitIn(t, dir, "add", "README")
+	gitIn(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func gitIn(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v: %s", args[0], err, out)
+	}
+}
+
+func TestWorktreeLifecycle(t *testing.T) {
+	root := repo(t)
+	wt := filepath.Join(filepath.Dir(root), "wt-feature")
+
+	if git.HasBranch(root, "feature") {
+		t.Fatal("feature branch exists before it was created")
+	}
+	if err := git.AddWorktree(root, wt, "feature", ""); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := git.Branch(wt); err != nil || b != "feature" {
+		t.Errorf("Branch(worktree) = %q, %v; want feature", b, err)
+	}
+	if top, err := git.Toplevel(wt); err != nil || top != wt {
+		t.Errorf("Toplevel(worktree) = %q, %v; want %s", top, err, wt)
+	}
+	if main, err := git.MainRoot(wt); err != nil || main != root {
+		t.Errorf("MainRoot(worktree) = %q, %v; want %s", main, err, root)
+	}
+
+	// Uncommitted changes keep the worktree unless forced.
+	if err := os.WriteFile(filepath.Join(wt, "README"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if st, err := git.Status(wt); err != nil || !strings.HasPrefix(st, "## feature") || !strings.Contains(st, " M README") {
+		t.Errorf("Status = %q, %v; want the branch line and the modified README", st, err)
+	}
+	if ds, err := git.DiffStat(wt); err != nil || !strings.Contains(ds, "1 file changed") {
+		t.Errorf("DiffStat = %q, %v", ds, err)
+	}
+	if err := git.RemoveWorktree(root, wt, false); err == nil || !strings.HasPrefix(err.Error(), "git worktree: ") {
+		t.Errorf("RemoveWorktree of a dirty worktree = %v, want git's refusal", err)
+	}
+	if err := git.RemoveWorktree(root, wt, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// The branch survives its worktree and is checked out again on re-add.
+	if !git.HasBranch(root, "feature") {
+		t.Fatal("feature branch went with its worktree")
+	}
+	if err := git.AddWorktree(root, wt, "feature", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := git.RemoveWorktree(root, wt, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := git.DeleteBranch(root, "feature", false); err != nil {
+		t.Fatal(err)
+	}
+	if git.HasBranch(root, "feature") {
+		t.Error("feature branch still there after DeleteBranch")
+	}
+}
+
+func TestDeleteUnmergedBranch(t *testing.T) {
+	root := repo(t)
+	gitIn(t, root, "checkout", "-q", "-b", "spike")
+	gitIn(t, root, "commit", "-q", "--allow-empty", "-m", "spike")
+	gitIn(t, root, "checkout", "-q", "main")
+
+	if err := git.DeleteBranch(root, "spike", false); err == nil {
+		t.Error("DeleteBranch deleted an unmerged branch without force")
+	}
+	if err := git.DeleteBranch(root, "spike", true); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDetachedBranch(t *testing.T) {
+	root := repo(t)
+	gitIn(t, root, "checkout", "-q", "--detach")
+	if b, err := git.Branch(root); err != nil || b != "" {
+		t.Errorf("Branch(detached) = %q, %v; want empty", b, err)
+	}
+}
@@ -0,0 +1,158 @@
+// Package mcp serves tools over the Model Context Protocol, so that an
+// assistant such as a supervising Claude Code instance can call them. It
+// speaks the stdio transport: JSON-RPC 2.0 messages, one per line.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ProtocolVersion is the protocol revision offered to clients that don't
+// ask for one.
+const ProtocolVersion = "2024-11-05"
+
+// Tool is a function the server exposes.
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON Schema of the tool's arguments.
+	InputSchema map[string]any
+	// Call runs the tool with its arguments (a JSON object) and returns
+	// text for the model. An error is reported to the model as a failed
+	// call, not as a protocol error.
+	Call func(ctx context.Context, args json.RawMessage) (string, error)
+}
+
+// Server answers MCP requests with its tools.
+type Server struct {
+	Name    string
+	Version string
+	Tools   []Tool
+}
+
+// JSON-RPC error codes.
+const (
+	codeParse          = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+type request struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from r and writes responses to w until r ends or
+// ctx is done. Tool calls run concurrently, so a slow one doesn't hold up
+// the rest.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	reply := func(resp response) {
+		resp.JSONRPC = "2.0"
+		if resp.ID == nil {
+			resp.ID = json.RawMessage("null")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_ = enc.Encode(resp)
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 16<<20)
+	for sc.Scan() && ctx.Err() == nil {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var req request
+		if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+			reply(response{Error: &rpcError{codeParse, err.Error()}})
+			continue
+		}
+		if req.ID == nil {
+			continue // a notification, e.g. notifications/initialized
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := s.handle(ctx, req)
+			reply(response{ID: req.ID, Result: result, Error: err})
+		}()
+	}
+	return sc.Err()
+}
+
+func (s *Server) handle(ctx context.Context, req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &p)
+		if p.ProtocolVersion == "" {
+			p.ProtocolVersion = ProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": p.ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": s.Name, "version": s.Version},
+		}, nil
+
+	case "ping":
+		return map[string]any{}, nil
+
+	case "tools/list":
+		tools := make([]map[string]any, len(s.Tools))
+		for i, t := range s.Tools {
+			tools[i] = map[string]any{"name": t.Name, "description": t.Description, "inputSchema": t.InputSchema}
+		}
+		return map[string]any{"tools": tools}, nil
+
+	case "tools/call":
+		var p struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return nil, &rpcError{codeInvalidParams, err.Error()}
+		}
+		for _, t := range s.Tools {
+			if t.Name == p.Name {
+				return callResult(t.Call(ctx, p.Arguments)), nil
+			}
+		}
+		return nil, &rpcError{codeInvalidParams, fmt.Sprintf("unknown tool %q", p.Name)}
+	}
+	return nil, &rpcError{codeMethodNotFound, fmt.Sprintf("method %q not found", req.Method)}
+}
+
+// callResult wraps a tool's output, or its failure, as text content.
+func callResult(text string, err error) map[string]any {
+	if err != nil {
+		text = err.Error()
+	}
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": text}},
+		"isError": err != nil,
+	}
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	s := &Server{Name: "tmux-nav", Version: "test", Tools: []Tool{
+		{
+			Name:        "echo",
+			InputSchema: map[string]any{"type": "object"},
+			Call: func(_ context.Context, args json.RawMessage) (string, error) {
+				var a struct{ Text string }
+				if err := json.Unmarshal(args, &a); err != nil {
+					return "", err
+				}
+				if a.Text == "" {
+					return "", errors.New("nothing to echo")
+				}
+				return a.Text, nil
+			},
+		},
+	}}
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"resources/list"}`,
+		`not json`,
+	}, "\n")
+	var out bytes.Buffer
+	if err := s.Serve(t.Context(), strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	// Calls are answered concurrently, so in any order.
+	replies := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(replies)
+	want := []string{
+		`{"jsonrpc":"2.0","id":1,"result":{"capabilities":{"tools":{}},"protocolVersion":"2025-03-26","serverInfo":{"name":"tmux-nav","version":"test"}}}`,
+		`{"jsonrpc":"2.0","id":2,"result":{"tools":[{"description":"","inputSchema":{"type":"object"},"name":"echo"}]}}`,
+		`{"jsonrpc":"2.0","id":3,"result":{"content":[{"text":"hi","type":"text"}],"isError":false}}`,
+		`{"jsonrpc":"2.0","id":4,"result":{"content":[{"text":"nothing to echo","type":"text"}],"isError":true}}`,
+		`{"jsonrpc":"2.0","id":5,"error":{"code":-32601,"message":"method \"resources/list\" not found"}}`,
+		`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"invalid character 'o' in literal null (expecting 'u')"}}`,
+	}
+	sort.Strings(want)
+	if strings.Join(replies, "\n") != strings.Join(want, "\n") {
+		t.Errorf("replies:\n%s\nwant:\n%s", strings.Join(replies, "\n"), strings.Join(want, "\n"))
+	}
+}
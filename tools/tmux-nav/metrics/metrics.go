@@ -0,0 +1,134 @@
+// Package metrics exposes session and agent metrics in the Prometheus text
+// exposition format, for `tmux-nav serve`'s /metrics endpoint.
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// Collector holds the latest session snapshot and counts event-log entries
+// by kind. Gauges come from Observe; counters from the event log, which
+// every tmux-nav process (TUI, CLI, daemon) appends to, so attaches and
+// notifications are counted wherever they happen.
+type Collector struct {
+	mu       sync.Mutex
+	sessions map[bool]int        // by attached
+	agents   map[agent.State]int // by state
+	stuck    int                 // working agents with frozen output
+	events   map[string]int64    // event-log entries by kind
+	offset   int64               // event-log bytes already counted
+}
+
+// New creates a collector. Events logged before it was created are
+// counted too, so counters survive daemon restarts.
+func New() *Collector {
+	return &Collector{
+		sessions: map[bool]int{},
+		agents:   map[agent.State]int{},
+		events:   map[string]int64{},
+	}
+}
+
+// Observe records the current sessions with their agent states and the
+// set of stuck agents.
+func (c *Collector) Observe(sessions []tmuxclient.Session, states map[string]agent.State, stuck map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessions = map[bool]int{}
+	for _, s := range sessions {
+		c.sessions[s.Attached]++
+	}
+	c.agents = map[agent.State]int{}
+	for _, st := range states {
+		c.agents[st]++
+	}
+	c.stuck = len(stuck)
+}
+
+// ServeHTTP writes the metrics.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.countEvents(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.write(w)
+}
+
+func (c *Collector) write(w io.Writer) {
+	header(w, "tmux_nav_sessions", "gauge", "tmux sessions by attach state.")
+	fmt.Fprintf(w, "tmux_nav_sessions{state=\"attached\"} %d\n", c.sessions[true])
+	fmt.Fprintf(w, "tmux_nav_sessions{state=\"detached\"} %d\n", c.sessions[false])
+
+	header(w, "tmux_nav_agents", "gauge", "Agent sessions by detected state.")
+	for st := agent.StateUnknown; st <= agent.StateRateLimited; st++ {
+		fmt.Fprintf(w, "tmux_nav_agents{state=%q} %d\n", st.String(), c.agents[st])
+	}
+	header(w, "tmux_nav_agents_stuck", "gauge", "Working agents whose output has stopped changing.")
+	fmt.Fprintf(w, "tmux_nav_agents_stuck %d\n", c.stuck)
+
+	header(w, "tmux_nav_attaches_total", "counter", "Session attaches made through tmux-nav.")
+	fmt.Fprintf(w, "tmux_nav_attaches_total %d\n", c.events["attach"])
+	header(w, "tmux_nav_notifications_total", "counter", "Agent notifications sent.")
+	fmt.Fprintf(w, "tmux_nav_notifications_total %d\n", c.events["notify"])
+
+	header(w, "tmux_nav_events_total", "counter", "Event-log entries by kind.")
+	kinds := make([]string, 0, len(c.events))
+	for k := range c.events {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	for _, k := range kinds {
+		fmt.Fprintf(w, "tmux_nav_events_total{kind=%q} %d\n", k, c.events[k])
+	}
+}
+
+func header(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// countEvents reads event-log entries appended since the last call.
+func (c *Collector) countEvents() error {
+	f, err := os.Open(eventlog.Path())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if fi, err := f.Stat(); err == nil && fi.Size() < c.offset {
+		// The log was truncated or replaced; start over.
+		c.offset = 0
+		c.events = map[string]int64{}
+	}
+	if _, err := f.Seek(c.offset, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// Leave a partial trailing line for the next scrape.
+			return nil
+		}
+		c.offset += int64(len(line))
+		var e eventlog.Entry
+		if json.Unmarshal(line, &e) == nil {
+			c.events[e.Kind]++
+		}
+	}
+}
@@ -0,0 +1,1090 @@
+package navui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/attach"
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/gh"
+	"github.com/bjornslib/tmux-nav/notify"
+	"github.com/bjornslib/tmux-nav/platform"
+	"github.com/bjornslib/tmux-nav/plugin"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/trace"
+	"github.com/bjornslib/tmux-nav/transcript"
+	"github.com/bjornslib/tmux-nav/workpool"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ── Messages ───────────────────────────────────────────────────────────────
+
+type sessionsLoadedMsg struct {
+	sessions  []tmuxclient.Session
+	states    map[string]agent.State
+	details   map[string]transcript.Info
+	stuck     map[string]bool
+	groups    map[string]string // repository root by agent session name
+	checkouts map[string]checkout
+
+	// Upkeep the poll found, left to the dispatcher so it is ordered
+	// with the user's operations on the same sessions.
+	capture   []tmuxclient.Session
+	supervise []tmuxclient.Session
+	archive   []tmuxclient.Session
+}
+type errMsg struct{ err error }
+
+// serverGoneMsg reports that the tmux server isn't running.
+type serverGoneMsg struct{}
+
+// actionDoneMsg reports the outcome of a background action on a session.
+type actionDoneMsg struct {
+	status     string
+	err        error
+	selectName string // session to highlight after the reload, if any
+}
+type tickMsg time.Time
+
+// hookEventMsg arrives when a Claude Code hook signalled a state change.
+type hookEventMsg struct{ err error }
+
+// ── Model ──────────────────────────────────────────────────────────────────
+
+type uiMode int
+
+const (
+	modeList uiMode = iota
+	modeConfirmKill
+	modeAttachFailed
+	modeAttention
+	modeInput
+	modeLog
+	modeGrid
+	modeWindows
+	modePanes
+)
+
+// inputKind says what a submitted lineInput is for.
+type inputKind int
+
+const (
+	inputPrompt    inputKind = iota // send a prompt to the selected agent
+	inputAgent                      // spawn an agent: "<project> [task]"
+	inputSession                    // create a session: "<name> [dir] [-- command]"
+	inputRename                     // rename the selected session
+	inputSend                       // type a line into the selected session
+	inputBroadcast                  // send a prompt to the marked sessions
+)
+
+// Model is the Bubble Tea model.
+// After p.Run() returns, inspect AttachSession: if non-empty, caller should attach.
+type Model struct {
+	sessions      []tmuxclient.Session
+	states        map[string]agent.State     // agent state by session name
+	details       map[string]transcript.Info // agent transcript info by session name
+	stuck         map[string]bool            // working agents with frozen output
+	cursor        int
+	previews      map[string]string // cached previews by previewKey
+	previewTab    previewTab
+	previewScroll int // lines scrolled back from the bottom of the pane
+	err           error
+	mode          uiMode
+	width         int
+	height        int
+	Strategy      attach.Strategy
+	statusMsg     string
+	AttachSession string // set when user picks a session to attach to
+	AttachWindow  string // optional window to select after attaching
+	AttachPane    string // optional pane to select after attaching
+	DetachOthers  bool   // detach other clients when attaching (A key)
+	archiveKill   bool   // the kill confirmation archives the session first
+
+	// Notifier, when set, is fed agent states on every refresh.
+	Notifier *notify.Notifier
+	// PRStatus shows the pull request status of worktree branches (via gh).
+	PRStatus bool
+	// Macros are the canned replies of the needs-attention view; nil uses
+	// DefaultMacros.
+	Macros []Macro
+	// PreviewMaxBytes caps each cached preview; zero uses the default.
+	PreviewMaxBytes int
+	// Split is the list's share of the screen in percent, 20 to 80: of the
+	// width side by side, of the height stacked. Zero splits evenly.
+	Split int
+	// StackBelow is the terminal width under which the preview moves below
+	// the list; zero keeps them side by side.
+	StackBelow int
+	// PreviewLines is how many lines previews capture; zero uses the
+	// default.
+	PreviewLines int
+	// Refresh is the fastest the session list is polled; zero uses the
+	// default.
+	Refresh time.Duration
+	// Ignore lists session-name globs hidden from the list.
+	Ignore []string
+	// PruneAfter and PruneExclude pick the sessions the prune action
+	// offers to kill; see agent.Prunable.
+	PruneAfter   time.Duration
+	PruneExclude []string
+	// Plugins contribute list columns, preview tabs and key-bound actions.
+	Plugins []*plugin.Plugin
+	// Keys binds keys to actions; the zero value uses the defaults.
+	Keys Keymap
+
+	selectName string         // session to reselect once sessions load
+	failure    *attachFailure // set while the attach recovery menu is open
+
+	blockedSince map[string]time.Time // when agents started needing attention
+	attnCursor   int                  // cursor within the attention queue
+
+	input     lineInput // footer text prompt, active in modeInput
+	inputFor  inputKind
+	inputBack uiMode // mode to return to when the input closes
+
+	showHelp   bool // the help overlay is open
+	helpScroll int  // help lines scrolled past
+
+	log    []eventlog.Entry // event log tail, shown in modeLog
+	logErr error
+
+	gridLines map[string]string // last output line per agent, for modeGrid
+
+	winSession string              // session drilled into, in modeWindows
+	windows    []tmuxclient.Window // its windows; nil while loading
+	winCursor  int
+	paneWindow int               // window drilled into, in modePanes
+	panes      []tmuxclient.Pane // its panes; nil while loading
+	paneCursor int
+
+	grouped   bool              // list agents under per-repository headers
+	groups    map[string]string // repository root by agent session name
+	collapsed map[string]bool   // collapsed groups by root
+
+	checkouts map[string]checkout // git checkout by session name
+
+	prs       map[string]gh.PR // pull request by session name
+	prFetched time.Time
+
+	pluginCells    map[string][]string // plugin column cells by session name
+	pluginsFetched time.Time
+
+	events        <-chan tmuxclient.Notification // control-mode notifications; nil when polling only
+	stopWatch     context.CancelFunc
+	reloadPending bool          // a debounced reload is scheduled
+	pollEvery     time.Duration // current adaptive poll interval
+	snapshot      string        // fingerprint of the last refresh
+	noServer      bool          // the tmux server is gone; waiting for it
+	hadServer     bool          // sessions have loaded since the navigator started
+	noTmux        bool          // tmux isn't installed; waiting for it to be
+
+	marked map[string]bool // sessions marked for a bulk kill or broadcast
+	sort   sortOrder
+
+	filter    string    // fuzzy filter narrowing the list; "" shows all
+	attnOnly  bool      // show only sessions needing attention
+	filtering bool      // the filter is being typed
+	filterIn  lineInput // the filter as typed
+
+	follow     io.ReadCloser // output of the followed pane, while following
+	followName string
+	followBuf  string // what it wrote since, capped
+
+	pool *workpool.Pool // runs background captures and lookups
+	ops  *dispatcher    // runs the changes the user asks for
+}
+
+// New creates an initialised Model.
+func New() Model {
+	return Model{
+		Strategy:   attach.DetectStrategy(),
+		StackBelow: defaultStackBelow,
+		pool:       workpool.New(maxWorkers),
+		ops:        newDispatcher(),
+	}
+}
+
+// Init kicks off the initial session load.
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.refresh(), tickCmd(m.pollInterval()), waitHookEvent, watchTmux)
+}
+
+// ── Update ─────────────────────────────────────────────────────────────────
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer m.catch(msg)
+	if trace.Enabled() {
+		defer trace.Record("update", msgName(msg), time.Now(), nil)
+	}
+	switch msg := msg.(type) {
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case sessionsLoadedMsg:
+		var rewatch tea.Cmd
+		m.noTmux = false
+		if m.noServer {
+			m.noServer = false
+			if m.hadServer {
+				m.statusMsg = "tmux server is back"
+			}
+			if m.events == nil {
+				rewatch = watchTmux
+			}
+		}
+		m.hadServer = true
+		snapshot := fingerprint(msg)
+		m.adaptPoll(snapshot != m.snapshot)
+		m.snapshot = snapshot
+		// Anchor the cursor to the highlighted session, not its row.
+		anchor := ""
+		if m.cursor < len(m.sessions) {
+			anchor = m.sessions[m.cursor].Name
+		}
+		if m.selectName != "" {
+			anchor, m.selectName = m.selectName, ""
+		}
+		sessions, same := mergeSessions(m.sessions, m.unignored(msg.sessions))
+		regroup := m.grouped && !(same && maps.Equal(m.groups, msg.groups))
+		m.sessions = sessions
+		m.states = msg.states
+		m.details = msg.details
+		m.stuck = msg.stuck
+		m.groups = msg.groups
+		m.checkouts = msg.checkouts
+		m.err = nil
+		if i := indexOf(m.sessions, anchor); i >= 0 {
+			m.cursor = i
+		} else if anchor != "" && m.mode == modeConfirmKill && (len(m.marked) == 0 || m.archiveKill) {
+			// Never let the confirmation slide onto another session.
+			m.mode = modeList
+			m.statusMsg = fmt.Sprintf("%q is gone; kill cancelled", anchor)
+		}
+		m.cursor = min(m.cursor, safeMax(0, len(m.sessions)-1))
+		if m.sort != sortTmux {
+			m.sortSessions()
+			regroup = m.grouped
+		}
+		if regroup {
+			m.sortByGroup()
+		}
+		m.keepCursorVisible()
+		m.pruneMarks()
+		m.trackBlocked()
+		m.Notifier.Observe(m.states, time.Now())
+		if m.mode == modeAttention {
+			m.syncAttentionCursor()
+		}
+		var drill tea.Cmd
+		switch m.viewMode() {
+		case modeWindows:
+			drill = m.loadWindows(m.winSession)
+		case modePanes:
+			drill = m.loadPanes(m.winSession, m.paneWindow)
+		}
+		return m, tea.Batch(m.loadPreview(), m.refreshPRs(), m.refreshPluginCells(), rewatch, drill, m.upkeep(msg))
+
+	case prStatusMsg:
+		m.prs = msg.prs
+		return m, nil
+
+	case pluginCellsMsg:
+		m.pluginCells = msg.cells
+		return m, nil
+
+	case previewLoadedMsg:
+		m.storePreview(msg)
+		return m, nil
+
+	case serverGoneMsg:
+		return m.serverGone(), nil
+
+	case errMsg:
+		if m.noServer {
+			// Expected until the server is back; don't flash errors.
+			return m, nil
+		}
+		if errors.Is(msg.err, tmuxclient.ErrTmuxNotInstalled) {
+			m.noTmux = true
+			return m, nil
+		}
+		m.err = msg.err
+		return m, nil
+
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.statusMsg = msg.err.Error()
+		} else {
+			m.statusMsg = msg.status
+		}
+		if msg.selectName != "" {
+			m.selectName = msg.selectName
+		}
+		return m, m.refresh()
+
+	case windowsLoadedMsg:
+		return m.storeWindows(msg)
+
+	case followStartedMsg:
+		return m.startedFollow(msg)
+
+	case followOutputMsg:
+		return m.followed(msg)
+
+	case panesLoadedMsg:
+		return m.storePanes(msg)
+
+	case gridLinesMsg:
+		m.gridLines = msg.lines
+		return m, nil
+
+	case logLoadedMsg:
+		m.log, m.logErr = msg.entries, msg.err
+		return m, nil
+
+	case tickMsg:
+		next := tickCmd(m.pollInterval())
+		if m.mode == modeLog {
+			return m, tea.Batch(m.refresh(), loadLog, next)
+		}
+		if m.viewMode() == modeGrid {
+			return m, tea.Batch(m.refresh(), m.loadGridLines(), next)
+		}
+		return m, tea.Batch(m.refresh(), next)
+
+	case watchMsg:
+		if msg.err != nil {
+			return m, rewatchLater()
+		}
+		if m.events != nil {
+			// Reconnected meanwhile; one connection is enough.
+			msg.cancel()
+			return m, nil
+		}
+		m.events, m.stopWatch = msg.ch, msg.cancel
+		return m, waitTmuxEvent(m.events)
+
+	case tmuxEventMsg:
+		return m.handleTmuxEvent(msg)
+
+	case reloadMsg:
+		m.reloadPending = false
+		return m, m.refresh()
+
+	case hookEventMsg:
+		if errors.Is(msg.err, context.DeadlineExceeded) {
+			return m, waitHookEvent
+		}
+		if msg.err != nil {
+			// No server (yet); try again later rather than spinning.
+			return m, tea.Tick(5*time.Second, func(time.Time) tea.Msg { return waitHookEvent() })
+		}
+		// Hooks set options from another process, behind the cache.
+		return m, tea.Batch(m.reload(), waitHookEvent)
+
+	case tea.KeyMsg:
+		// Someone is looking: refresh promptly again.
+		m.pollEvery = m.fastestPoll()
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.mode == modeAttachFailed {
+		return m.handleRecoveryKey(msg)
+	}
+	if m.mode == modeInput {
+		return m.handleInputKey(msg)
+	}
+	if m.showHelp {
+		return m.handleHelpKey(msg)
+	}
+	if m.action(msg) == ActHelp && m.mode != modeConfirmKill && !m.filtering {
+		return m.openHelp()
+	}
+	if m.mode == modeAttention {
+		return m.handleAttentionKey(msg)
+	}
+	if m.mode == modeLog {
+		return m.handleLogKey(msg)
+	}
+	if m.mode == modeGrid {
+		return m.handleGridKey(msg)
+	}
+	if m.mode == modeWindows {
+		return m.handleWindowsKey(msg)
+	}
+	if m.mode == modePanes {
+		return m.handlePanesKey(msg)
+	}
+	if m.mode == modeConfirmKill {
+		switch msg.String() {
+		case "y", "Y":
+			m.mode = modeList
+			if len(m.sessions) == 0 {
+				return m, nil
+			}
+			if len(m.marked) > 0 && !m.archiveKill {
+				return m.killMarked()
+			}
+			return m.kill(m.sessions[m.cursor], m.archiveKill)
+		default:
+			m.mode = modeList
+			m.statusMsg = "kill cancelled"
+			return m, nil
+		}
+	}
+
+	// modeList key handling
+	if m.filtering {
+		return m.handleFilterKey(msg)
+	}
+	if m.noMatches() {
+		// Nothing is selected; only leave, refilter or reload.
+		switch m.action(msg) {
+		case ActQuit, ActBack, ActFilter, ActAttentionFilter, ActRefresh:
+		default:
+			return m, nil
+		}
+	}
+	switch m.action(msg) {
+	case ActBack:
+		if len(m.marked) > 0 {
+			m.marked = nil
+			return m, nil
+		}
+		if m.filter != "" {
+			return m.setFilter("")
+		}
+		if m.attnOnly {
+			return m.toggleAttentionOnly()
+		}
+		return m, tea.Quit
+
+	case ActQuit:
+		return m, tea.Quit
+
+	case ActFilter:
+		return m.openFilter(), nil
+
+	case ActAttentionFilter:
+		return m.toggleAttentionOnly()
+
+	case ActUp:
+		if m.moveCursor(-1) {
+			return m, m.loadPreview()
+		}
+
+	case ActDown:
+		if m.moveCursor(1) {
+			return m, m.loadPreview()
+		}
+
+	case ActAttach:
+		if m.grouped && len(m.sessions) > 0 && m.collapsed[m.groups[m.sessions[m.cursor].Name]] {
+			m.toggleGroup()
+			return m, nil
+		}
+		// Record the chosen session; main.go will attach after TUI exits.
+		if len(m.sessions) > 0 {
+			m.AttachSession = m.sessions[m.cursor].Name
+			return m, tea.Quit
+		}
+
+	case ActAttachOnly:
+		// Attach as the only client, detaching everyone else.
+		if len(m.sessions) > 0 {
+			m.AttachSession = m.sessions[m.cursor].Name
+			m.DetachOthers = true
+			return m, tea.Quit
+		}
+
+	case ActDrillIn:
+		return m.openWindows()
+
+	case ActPreview:
+		return m, m.loadPreview()
+
+	case ActFollow:
+		return m.toggleFollow()
+
+	case ActPreviewTab:
+		// Cycle the preview through pane output, worktree changes and
+		// plugin tabs.
+		m.previewTab = m.nextTab()
+		m.previewScroll = 0
+		return m, m.loadPreview()
+
+	case ActScrollUp:
+		return m.scrollPreview(1)
+
+	case ActScrollDown:
+		return m.scrollPreview(-1)
+
+	case ActKill:
+		if len(m.sessions) > 0 {
+			m.mode = modeConfirmKill
+			m.archiveKill = false
+			m.statusMsg = ""
+		}
+
+	case ActArchive:
+		// Archive scrollback, transcript and metadata, then kill.
+		if len(m.sessions) > 0 && agent.IsAgent(m.sessions[m.cursor]) {
+			m.mode = modeConfirmKill
+			m.archiveKill = true
+			m.statusMsg = ""
+		}
+
+	case ActOpenDir:
+		if len(m.sessions) > 0 {
+			return m, openDir(m.sessions[m.cursor])
+		}
+
+	case ActRefresh:
+		m.statusMsg = "refreshing…"
+		return m, m.reload()
+
+	case ActApprove:
+		return m, m.respond(true)
+
+	case ActDeny:
+		return m, m.respond(false)
+
+	case ActNewSession:
+		return m.openInput(inputSession, "New session (name [dir] [-- command]):"), nil
+
+	case ActInterrupt:
+		return m, m.interrupt()
+
+	case ActPrompt:
+		if len(m.sessions) > 0 {
+			return m.openInput(inputPrompt, "Prompt "+m.sessions[m.cursor].Name+":"), nil
+		}
+
+	case ActBroadcast:
+		if len(m.marked) == 0 {
+			m.statusMsg = "mark the agents to broadcast to first"
+			return m, nil
+		}
+		return m.openInput(inputBroadcast, fmt.Sprintf("Broadcast to %d sessions:", len(m.marked))), nil
+
+	case ActSend:
+		if len(m.sessions) > 0 {
+			return m.openInput(inputSend, "Send to "+m.sessions[m.cursor].Name+":"), nil
+		}
+
+	case ActRename:
+		if len(m.sessions) > 0 {
+			name := m.sessions[m.cursor].Name
+			m = m.openInput(inputRename, "Rename "+name+" to:")
+			m.input.value = []rune(name)
+			return m, nil
+		}
+
+	case ActNewAgent:
+		return m.openInput(inputAgent, "New agent (project [task]):"), nil
+
+	case ActSort:
+		return m.cycleSort()
+
+	case ActGroup:
+		return m.toggleGrouping()
+
+	case ActStrategy:
+		m.Strategy = nextStrategy(m.Strategy)
+		m.statusMsg = "attach: " + attach.StrategyLabel(m.Strategy)
+		return m, nil
+
+	case ActMark:
+		return m.toggleMark()
+
+	case ActPrune:
+		return m.markStale()
+
+	case ActFold:
+		if m.grouped {
+			m.toggleGroup()
+		}
+
+	case ActShrinkList, ActGrowList:
+		dir := 1
+		if m.action(msg) == ActShrinkList {
+			dir = -1
+		}
+		m = m.resizeSplit(dir)
+		return m, m.loadPreview()
+
+	case ActLog:
+		m.mode = modeLog
+		return m, loadLog
+
+	case ActGrid:
+		// Switch to the agent dashboard.
+		m.mode = modeGrid
+		if cards := m.gridAgents(); len(cards) > 0 {
+			m.cursor = cards[m.gridPos(cards)]
+		}
+		return m, m.loadGridLines()
+
+	case ActAttention:
+		// Switch to the queue of agents waiting on a human.
+		m.mode = modeAttention
+		m.attnCursor = 0
+		m.syncAttentionCursor()
+		return m, m.loadPreview()
+
+	default:
+		if cmd := m.pluginAction(msg.String()); cmd != nil {
+			return m, cmd
+		}
+	}
+
+	return m, nil
+}
+
+// kill kills s, archiving it first if asked, once the operations already
+// dispatched on it end; those still waiting are dropped.
+func (m Model) kill(s tmuxclient.Session, archive bool) (tea.Model, tea.Cmd) {
+	m.ops.cancel(s.Name)
+	if archive {
+		m.statusMsg = fmt.Sprintf("archiving %q…", s.Name)
+		return m, m.ops.run(s.Name, func() tea.Msg {
+			dir, err := agent.Archive(s)
+			if err != nil {
+				return actionDoneMsg{err: err}
+			}
+			return actionDoneMsg{status: fmt.Sprintf("archived %q to %s", s.Name, dir)}
+		})
+	}
+	m.statusMsg = fmt.Sprintf("killing %q…", s.Name)
+	return m, m.ops.run(s.Name, func() tea.Msg {
+		if err := agent.Remove(s, false, false); err != nil {
+			return actionDoneMsg{err: err}
+		}
+		if s.Worktree != "" {
+			return actionDoneMsg{status: fmt.Sprintf("killed %q and removed %s", s.Name, s.Worktree)}
+		}
+		return actionDoneMsg{status: fmt.Sprintf("killed %q", s.Name)}
+	})
+}
+
+// ── View ───────────────────────────────────────────────────────────────────
+
+func (m Model) View() string {
+	defer m.catch(nil)
+	defer trace.Record("view", "View", time.Now(), nil)
+	if m.width == 0 {
+		return "Loading…\n"
+	}
+	return m.render(m.footerKeys())
+}
+
+// render lays out the view with keys as the footer's key help.
+func (m Model) render(keys string) string {
+	listW, previewW := m.splitWidths()
+
+	listContent := m.renderList(listW)
+	if m.viewMode() == modeAttention {
+		listContent = m.renderAttention(listW)
+	}
+	if m.viewMode() == modeWindows {
+		listContent = m.renderWindows(listW)
+	}
+	if m.viewMode() == modePanes {
+		listContent = m.renderPanes(listW)
+	}
+	previewContent := m.renderPreview(previewW)
+
+	body := m.layoutBody(listContent, previewContent)
+
+	header := titleStyle.Render(fmt.Sprintf("tmux-nav  %d session(s)  [%s]%s",
+		len(m.sessions), attach.StrategyLabel(m.Strategy), m.sortStatus()+m.filterStatus()+m.markStatus()))
+
+	if m.viewMode() == modeGrid {
+		body = m.renderGrid()
+	}
+	if m.mode == modeLog {
+		body = listBorderStyle.Width(m.width - 2).Render(m.renderLog(m.width - 4))
+	}
+
+	if m.mode == modeAttachFailed {
+		modal := lipgloss.Place(m.width, lipgloss.Height(body), lipgloss.Center, lipgloss.Center, m.renderRecovery())
+		return lipgloss.JoinVertical(lipgloss.Left, header, modal)
+	}
+	if m.showHelp {
+		return lipgloss.JoinVertical(lipgloss.Left, header,
+			lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Center, m.renderHelp()))
+	}
+
+	footer := m.renderFooter(keys)
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, footer)
+}
+
+func (m Model) renderList(w int) string {
+	if m.noTmux {
+		return confirmStyle.Render("tmux isn't installed") + "\n\n" +
+			helpStyle.Render("install it with "+platform.Current().InstallTmux()+";\nthe list appears once tmux is on $PATH")
+	}
+	if m.noServer && !m.hadServer {
+		return normalStyle.Render("no tmux server is running yet") + "\n\n" +
+			helpStyle.Render(m.Keys.help(entry("create a first session", ActNewSession), entry("start an agent", ActNewAgent)))
+	}
+	if m.noServer {
+		return confirmStyle.Render("tmux server gone — waiting for it to come back…")
+	}
+	if len(m.sessions) == 0 {
+		return normalStyle.Render("(no sessions)")
+	}
+
+	if m.noMatches() && m.filter == "" {
+		return normalStyle.Render("(no sessions need attention)")
+	}
+	if m.noMatches() {
+		return normalStyle.Render(fmt.Sprintf("(no sessions match %q)", m.filter))
+	}
+
+	if m.grouped {
+		return m.renderGroupedList(w)
+	}
+
+	var rows []string
+	cur := 0
+	for i := range m.sessions {
+		if m.matches(i) {
+			if i == m.cursor {
+				cur = len(rows)
+			}
+			rows = append(rows, m.renderRow(i))
+		}
+	}
+	return m.pageList(rows, cur, w)
+}
+
+// renderRow renders the list line for m.sessions[i].
+func (m Model) renderRow(i int) string {
+	s := m.sessions[i]
+	badge := detachedBadge.String()
+	if s.Attached {
+		badge = attachedBadge.String()
+	}
+	age := formatAge(s.LastUsed)
+	kind := " "
+	if agent.IsAgent(s) {
+		kind = m.stateBadge(s.Name)
+	}
+	state := m.stateLabel(s.Name)
+	label := fmt.Sprintf("%s%s %-28s  %dw  %-4s %s", badge, kind, s.Name, s.Windows, age, state)
+	if d, ok := m.details[s.Name]; ok {
+		label += fmt.Sprintf("  $%.2f", d.Usage.CostUSD)
+	}
+	if where := m.checkoutLabel(s); where != "" {
+		label += "  ⎇ " + where
+	}
+	if pr, ok := m.prs[s.Name]; ok {
+		label += "  " + pr.Short()
+	}
+	for _, cell := range m.pluginCells[s.Name] {
+		if cell != "" {
+			label += "  " + cell
+		}
+	}
+
+	mark := " "
+	if m.marked[s.Name] {
+		mark = "✓"
+	}
+	if i == m.cursor {
+		return selectedStyle.Render("▶" + mark + label)
+	}
+	return normalStyle.Render(" " + mark + label)
+}
+
+func (m Model) renderPreview(w int) string {
+	title := "(no session selected)"
+	if len(m.sessions) > 0 {
+		title = "Preview: " + m.sessions[m.cursor].Name
+		if m.following() {
+			title = "Following: " + m.followName
+		} else if p, ok := m.selectedPane(); ok && m.viewMode() == modePanes {
+			title = fmt.Sprintf("Preview: %s:%d.%d %s", m.winSession, m.paneWindow, p.Index, p.Command)
+		} else if w, ok := m.selectedWindow(); ok && m.viewMode() == modeWindows {
+			title = fmt.Sprintf("Preview: %s:%d %s", m.winSession, w.Index, w.Name)
+		} else if m.previewTab == tabChanges {
+			title = "Changes: " + m.sessions[m.cursor].Name
+		} else if m.previewTab >= tabPlugins {
+			title = m.pluginTabs()[m.previewTab-tabPlugins].name + ": " + m.sessions[m.cursor].Name
+		} else if m.previewScroll > 0 {
+			title += fmt.Sprintf("  ↑ %d lines", m.previewScroll)
+		}
+	}
+
+	parts := []string{titleStyle.Render(title)}
+	details := m.renderDetails(w)
+	if details != "" {
+		parts = append(parts, details)
+	}
+
+	var content string
+	preview := m.currentPreview()
+	if m.err != nil {
+		content = errorStyle.Render("Error: " + m.err.Error())
+	} else if preview == "" {
+		content = normalStyle.Render("(empty pane)")
+	} else {
+		// The agent details take their lines from the output's, all of
+		// them on a short terminal.
+		lines := strings.Split(preview, "\n")
+		maxLines := m.previewRows()
+		if details != "" {
+			maxLines -= lipgloss.Height(details)
+		}
+		lines = lines[len(lines)-max(0, min(len(lines), maxLines)):]
+		content = strings.Join(lines, "\n")
+	}
+	if content != "" {
+		parts = append(parts, content)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// renderDetails summarises the selected agent's transcript: current task,
+// last tool call and turn count. Empty for sessions without a transcript.
+func (m Model) renderDetails(w int) string {
+	if len(m.sessions) == 0 {
+		return ""
+	}
+	d, ok := m.details[m.sessions[m.cursor].Name]
+	if !ok {
+		return ""
+	}
+	lines := []string{
+		"Task:  " + truncate(strings.Join(strings.Fields(d.Task), " "), w-8),
+		"Tool:  " + truncate(d.LastTool, w-8),
+		fmt.Sprintf("Turns: %d   Tokens: %d   Cost: $%.2f", d.Turns, d.Usage.Tokens(), d.Usage.CostUSD),
+	}
+	lines = append(lines, renderTodos(d.Todos, w)...)
+	return helpStyle.Render(strings.Join(lines, "\n")) + "\n"
+}
+
+// maxTodoLines caps how much of an agent's plan the detail panel shows.
+const maxTodoLines = 8
+
+// renderTodos shows the agent's plan with done/pending markers under a
+// progress count. Long plans are windowed around the first unfinished item.
+func renderTodos(todos []transcript.Todo, w int) []string {
+	if len(todos) == 0 {
+		return nil
+	}
+	done, next := 0, len(todos)
+	for i, t := range todos {
+		if t.Done() {
+			done++
+		} else if next == len(todos) {
+			next = i
+		}
+	}
+	lines := []string{fmt.Sprintf("Plan:  %d/%d done", done, len(todos))}
+	start := max(0, min(next-1, len(todos)-maxTodoLines))
+	end := min(len(todos), start+maxTodoLines)
+	for _, t := range todos[start:end] {
+		mark := "○"
+		switch t.Status {
+		case "completed":
+			mark = "✓"
+		case "in_progress":
+			mark = "▶"
+		}
+		lines = append(lines, "  "+mark+" "+truncate(t.Content, w-6))
+	}
+	if rest := len(todos) - end; rest > 0 {
+		lines = append(lines, fmt.Sprintf("  … %d more", rest))
+	}
+	return lines
+}
+
+// footerKeys is the footer's key help for the current view. The full list
+// of keys is in the help overlay; the footer has room for the everyday
+// ones.
+func (m Model) footerKeys() string {
+	km := m.Keys
+	nav := entry("navigate", ActUp, ActDown)
+	helpKey, quit := entry("help", ActHelp), entry("quit", ActQuit)
+	keys := km.help(nav, entry("attach", ActAttach), entry("filter", ActFilter), entry("kill", ActKill), helpKey, quit)
+	answer := entry("approve/deny", ActApprove, ActDeny)
+	if m.viewMode() == modeAttention {
+		keys = km.help(nav, entry("jump in", ActAttach), answer, entry("prompt", ActPrompt),
+			entry("interrupt", ActInterrupt), entry("reload", ActRefresh), entry("back to list", ActBack, ActAttention), helpKey)
+		if macros := m.macroHelp(); macros != "" {
+			keys += "  " + macros
+		}
+	}
+	if m.selectedNeedsAttention() && m.mode != modeAttention {
+		keys = km.help(answer) + "  " + keys
+	}
+	if m.mode == modeLog {
+		keys = km.help(entry("reload", ActRefresh), entry("back to list", ActBack, ActLog), helpKey, quit)
+	}
+	if m.viewMode() == modeWindows {
+		keys = km.help(nav, entry("attach window", ActAttach), entry("panes", ActDrillIn), entry("reload", ActRefresh),
+			entry("back to sessions", ActBack, ActDrillOut), helpKey, quit)
+	}
+	if m.viewMode() == modePanes {
+		keys = km.help(nav, entry("attach pane", ActAttach), entry("reload", ActRefresh),
+			entry("back to windows", ActBack, ActDrillOut), helpKey, quit)
+	}
+	if m.viewMode() == modeGrid {
+		keys = km.help(entry("navigate", ActDrillOut, ActDown, ActUp, ActDrillIn), entry("attach", ActAttach), answer,
+			entry("prompt", ActPrompt), entry("interrupt", ActInterrupt), entry("reload", ActRefresh),
+			entry("back to list", ActBack, ActGrid), helpKey)
+	}
+	return keys
+}
+
+// renderFooter renders the footer: the input, filter or confirmation
+// being shown, else keys and the status message.
+func (m Model) renderFooter(keys string) string {
+	if m.mode == modeInput {
+		help := "[enter] ok  [esc] cancel"
+		switch m.inputFor {
+		case inputPrompt, inputBroadcast:
+			help = "[enter] send  [alt+enter] newline  [esc] cancel"
+		case inputSend:
+			help = "[enter] send  [esc] cancel"
+		}
+		return m.input.view() + "\n" + helpStyle.Render(help)
+	}
+	if m.filtering && m.mode == modeList {
+		return m.filterIn.view() + "\n" + helpStyle.Render("[↑↓] navigate  [enter] keep filter  [esc] clear")
+	}
+	if m.mode == modeConfirmKill && len(m.marked) > 0 && !m.archiveKill {
+		return m.confirmKillMarked()
+	}
+	if m.mode == modeConfirmKill && len(m.sessions) > 0 {
+		s := m.sessions[m.cursor]
+		if m.archiveKill {
+			return confirmStyle.Render(fmt.Sprintf("Archive and kill %q? [y/N]", s.Name))
+		}
+		if s.Worktree != "" {
+			return confirmStyle.Render(fmt.Sprintf("Kill %q and remove worktree %s? [y/N]", s.Name, s.Worktree))
+		}
+		return confirmStyle.Render(fmt.Sprintf("Kill %q? [y/N]", s.Name))
+	}
+	help := helpStyle.Render(keys)
+	if m.statusMsg != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, help, normalStyle.Render("  "+m.statusMsg))
+	}
+	return help
+}
+
+// ── Commands ───────────────────────────────────────────────────────────────
+
+func loadSessions() tea.Msg {
+	sessions, err := tmuxclient.ListSessions(context.Background())
+	if err != nil {
+		return errMsg{err}
+	}
+	if len(sessions) == 0 && errors.Is(tmuxclient.Ping(context.Background()), tmuxclient.ErrNoServer) {
+		return serverGoneMsg{}
+	}
+	states := agent.DetectAll(sessions)
+	details := agent.TranscriptAll(sessions)
+	return sessionsLoadedMsg{
+		sessions:  sessions,
+		states:    states,
+		details:   details,
+		stuck:     agent.StuckSessions(states, time.Now()),
+		groups:    repoRoots(sessions),
+		checkouts: checkouts(sessions),
+		capture:   agent.Uncaptured(sessions),
+		supervise: agent.NeedsSupervision(sessions),
+		archive:   agent.DueForArchive(sessions, states, details),
+	}
+}
+
+// upkeep dispatches the capture, supervision and auto-archiving a poll
+// found due. A session's upkeep already under way isn't dispatched again.
+func (m Model) upkeep(msg sessionsLoadedMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, s := range msg.capture {
+		cmds = append(cmds, m.ops.runOnce("capture "+s.Name, s.Name, func() tea.Msg {
+			agent.EnsureCapture([]tmuxclient.Session{s})
+			return nil
+		}))
+	}
+	for _, s := range msg.supervise {
+		cmds = append(cmds, m.ops.runOnce("supervise "+s.Name, s.Name, func() tea.Msg {
+			agent.SuperviseSession(s)
+			return nil
+		}))
+	}
+	for _, s := range msg.archive {
+		cmds = append(cmds, m.ops.runOnce("archive "+s.Name, s.Name, func() tea.Msg {
+			dir, err := agent.ArchiveFinished(s)
+			if err != nil {
+				return actionDoneMsg{err: err}
+			}
+			return actionDoneMsg{status: fmt.Sprintf("archived %q to %s", s.Name, dir)}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// waitHookEvent blocks until `tmux-nav hook-event` signals a change.
+func waitHookEvent() tea.Msg {
+	return hookEventMsg{tmuxclient.WaitFor(context.Background(), agent.EventChannel, time.Minute)}
+}
+
+func tickCmd(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// ── Helpers ────────────────────────────────────────────────────────────────
+
+func formatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if n <= 0 || len(r) <= n {
+		return s
+	}
+	if n == 1 {
+		return "…"
+	}
+	return string(r[:n-1]) + "…"
+}
+
+func safeMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
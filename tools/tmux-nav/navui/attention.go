@@ -0,0 +1,213 @@
+package navui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// trackBlocked records when each agent session started needing attention
+// (waiting on a prompt, or possibly stuck), so the attention queue can be
+// ordered by how long agents have waited. The session's last activity is
+// used as the start time when first seen, since a blocked agent produces
+// no output.
+func (m *Model) trackBlocked() {
+	if m.blockedSince == nil {
+		m.blockedSince = make(map[string]time.Time)
+	}
+	seen := make(map[string]bool)
+	for _, s := range m.sessions {
+		if m.needsAttention(s.Name) {
+			seen[s.Name] = true
+			if _, ok := m.blockedSince[s.Name]; !ok {
+				m.blockedSince[s.Name] = s.LastUsed
+			}
+		}
+	}
+	for name := range m.blockedSince {
+		if !seen[name] {
+			delete(m.blockedSince, name)
+		}
+	}
+}
+
+// attentionQueue returns indices into m.sessions of sessions needing
+// attention, longest-blocked first.
+func (m Model) attentionQueue() []int {
+	var idx []int
+	for i, s := range m.sessions {
+		if _, ok := m.blockedSince[s.Name]; ok {
+			idx = append(idx, i)
+		}
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return m.blockedSince[m.sessions[idx[a]].Name].Before(m.blockedSince[m.sessions[idx[b]].Name])
+	})
+	return idx
+}
+
+// syncAttentionCursor points the main cursor at the highlighted queue entry
+// so the preview follows the queue selection.
+func (m *Model) syncAttentionCursor() {
+	q := m.attentionQueue()
+	if len(q) == 0 {
+		m.attnCursor = 0
+		return
+	}
+	if m.attnCursor >= len(q) {
+		m.attnCursor = len(q) - 1
+	}
+	m.cursor = q[m.attnCursor]
+}
+
+func (m Model) handleAttentionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	q := m.attentionQueue()
+	switch m.action(msg) {
+	case ActQuit:
+		return m, tea.Quit
+
+	case ActBack, ActAttention:
+		m.mode = modeList
+		return m, nil
+
+	case ActUp:
+		if m.attnCursor > 0 {
+			m.attnCursor--
+			m.syncAttentionCursor()
+			return m, m.loadPreview()
+		}
+
+	case ActDown:
+		if m.attnCursor < len(q)-1 {
+			m.attnCursor++
+			m.syncAttentionCursor()
+			return m, m.loadPreview()
+		}
+
+	case ActAttach:
+		if len(q) > 0 {
+			m.AttachSession = m.sessions[q[m.attnCursor]].Name
+			return m, tea.Quit
+		}
+
+	case ActRefresh:
+		return m, m.reload()
+
+	case ActApprove, ActDeny:
+		return m, m.respond(m.action(msg) == ActApprove)
+
+	case ActInterrupt:
+		return m, m.interrupt()
+
+	case ActPrompt:
+		if len(q) > 0 {
+			return m.openInput(inputPrompt, "Prompt "+m.sessions[m.cursor].Name+":"), nil
+		}
+
+	default:
+		if mac, ok := m.macroFor(msg.String()); ok && len(q) > 0 {
+			return m, m.sendMacro(mac)
+		}
+	}
+	return m, nil
+}
+
+// needsAttention reports whether the named agent waits on a human or looks
+// stuck.
+func (m Model) needsAttention(name string) bool {
+	st, ok := m.states[name]
+	return (ok && st.NeedsAttention()) || m.stuck[name]
+}
+
+// stateLabel is the state column text for a session ("" for non-agents).
+func (m Model) stateLabel(name string) string {
+	if m.stuck[name] {
+		return "stuck?"
+	}
+	if st, ok := m.states[name]; ok {
+		return st.String()
+	}
+	return ""
+}
+
+// stateBadge is the agent badge of the named session, coloured by its
+// state; stuck agents show orange.
+func (m Model) stateBadge(name string) string {
+	if m.stuck[name] {
+		return agentBadge.Foreground(stuckColor).String()
+	}
+	if c, ok := stateColors[m.states[name]]; ok {
+		return agentBadge.Foreground(c).String()
+	}
+	return agentBadge.String()
+}
+
+// interrupt stops the highlighted agent's current turn in the background.
+func (m Model) interrupt() tea.Cmd {
+	if len(m.sessions) == 0 {
+		return nil
+	}
+	session := m.sessions[m.cursor].Name
+	if _, ok := m.states[session]; !ok {
+		return nil
+	}
+	return m.ops.run(session, func() tea.Msg {
+		if err := agent.Interrupt(session); err != nil {
+			return actionDoneMsg{err: err}
+		}
+		return actionDoneMsg{status: fmt.Sprintf("interrupted %q", session)}
+	})
+}
+
+// selectedNeedsAttention reports whether the highlighted session is an agent
+// blocked on a prompt.
+func (m Model) selectedNeedsAttention() bool {
+	if len(m.sessions) == 0 {
+		return false
+	}
+	st, ok := m.states[m.sessions[m.cursor].Name]
+	return ok && st.NeedsAttention()
+}
+
+// respond approves or denies the highlighted agent's prompt in the
+// background. It returns nil when the session isn't waiting on a prompt.
+func (m Model) respond(approve bool) tea.Cmd {
+	if !m.selectedNeedsAttention() {
+		return nil
+	}
+	session := m.sessions[m.cursor].Name
+	return m.ops.run(session, func() tea.Msg {
+		verb := "denied"
+		if approve {
+			verb = "approved"
+		}
+		if err := agent.Respond(session, approve); err != nil {
+			return actionDoneMsg{err: err}
+		}
+		return actionDoneMsg{status: fmt.Sprintf("%s prompt in %q", verb, session)}
+	})
+}
+
+func (m Model) renderAttention(w int) string {
+	q := m.attentionQueue()
+	if len(q) == 0 {
+		return normalStyle.Render("(no agents need attention)")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render(fmt.Sprintf("Needs attention (%d)", len(q))) + "\n")
+	for i, idx := range q {
+		s := m.sessions[idx]
+		label := fmt.Sprintf("%-28s  %-10s  blocked %s", s.Name, m.stateLabel(s.Name), formatAge(m.blockedSince[s.Name]))
+		if i == m.attnCursor {
+			sb.WriteString(selectedStyle.Render("▶ "+label) + "\n")
+		} else {
+			sb.WriteString(normalStyle.Render("  "+label) + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,92 @@
+package navui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/attach"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// benchSizes are the session counts the TUI benchmarks run at.
+var benchSizes = []int{10, 100, 500}
+
+// benchSessions returns n sessions, every other one an agent.
+func benchSessions(n int) []tmuxclient.Session {
+	now := time.Now()
+	sessions := make([]tmuxclient.Session, n)
+	for i := range sessions {
+		s := tmuxclient.Session{Name: fmt.Sprintf("scratch-%03d", i), Windows: 1,
+			LastUsed: now.Add(-time.Duration(i) * time.Minute), Created: now.Add(-time.Hour)}
+		if i%2 == 0 {
+			s.Name = fmt.Sprintf("agent-api-task-%03d", i)
+			s.AgentTag, s.Task, s.Branch = "api", "Fix the login bug", "agent/task-"+strconv.Itoa(i)
+		}
+		sessions[i] = s
+	}
+	return sessions
+}
+
+// listOutput renders sessions as tmux list-sessions output.
+func listOutput(sessions []tmuxclient.Session) string {
+	var sb strings.Builder
+	for _, s := range sessions {
+		sb.WriteString(strings.Join([]string{s.Name, "1", "", strconv.FormatInt(s.LastUsed.Unix(), 10),
+			"0.0", "claude", "/src/api", s.AgentTag, "", s.Branch, "", "0", "", "", "0", "", "0",
+			strconv.FormatInt(s.Created.Unix(), 10), s.Task, "50", "0"}, "|^|"))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func BenchmarkView(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			m := New()
+			m.Strategy = attach.PlainAttach
+			m = send(m, tea.WindowSizeMsg{Width: 160, Height: 50})
+			m = send(m, sessionsLoadedMsg{sessions: benchSessions(n), states: map[string]agent.State{}})
+			m = send(m, previewLoadedMsg{previewKey(tabPane, m.sessions[0].Name), strings.Repeat("output line\n", 40)})
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = m.View()
+			}
+		})
+	}
+}
+
+// BenchmarkSnapshot measures one full refresh against a fake tmux: listing
+// sessions, classifying the agents' panes and merging the result into the
+// model.
+func BenchmarkSnapshot(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.Setenv("HOME", b.TempDir())
+			b.Setenv("XDG_DATA_HOME", b.TempDir())
+			sessions := benchSessions(n)
+			tmuxtest.Install(b, tmuxtest.New().
+				On("list-sessions", listOutput(sessions), nil).
+				On("capture-pane", "● Working…\n\n✻ Pondering… (esc to interrupt)\n", nil))
+			tmuxclient.SetCacheTTL(0)
+			b.Cleanup(func() { tmuxclient.SetCacheTTL(tmuxclient.DefaultCacheTTL) })
+
+			m := send(New(), tea.WindowSizeMsg{Width: 160, Height: 50})
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				msg, ok := loadSessions().(sessionsLoadedMsg)
+				if !ok || len(msg.sessions) != n {
+					b.Fatalf("loadSessions() = %#v", msg)
+				}
+				m = send(m, msg)
+			}
+		})
+	}
+}
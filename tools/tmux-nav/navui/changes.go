@@ -0,0 +1,47 @@
+package navui
+
+import (
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/git"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// previewTab selects what the preview pane shows.
+type previewTab int
+
+const (
+	tabPane    previewTab = iota // the session's pane output
+	tabChanges                   // git status and diffstat of its worktree
+)
+
+// changesDir is the directory whose changes the Changes tab shows: the
+// agent's worktree, else the active pane's working directory.
+func changesDir(s tmuxclient.Session) string {
+	if s.Worktree != "" {
+		return s.Worktree
+	}
+	return s.Path
+}
+
+// loadChanges reports, as the preview under key, what the session changed
+// in its working tree: the short status followed by the diffstat against
+// HEAD.
+func loadChanges(key string, s tmuxclient.Session) tea.Cmd {
+	dir := changesDir(s)
+	return func() tea.Msg {
+		status, err := git.Status(dir)
+		if err != nil {
+			return previewLoadedMsg{key, "(no git changes: " + err.Error() + ")"}
+		}
+		parts := []string{status}
+		if stat, err := git.DiffStat(dir); err == nil && stat != "" {
+			parts = append(parts, stat)
+		}
+		if !strings.Contains(status, "\n") {
+			parts = append(parts, "(working tree clean)")
+		}
+		return previewLoadedMsg{key, strings.Join(parts, "\n\n")}
+	}
+}
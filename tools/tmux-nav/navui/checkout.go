@@ -0,0 +1,72 @@
+package navui
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/git"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// checkout is the repository and branch a session's working directory is
+// in, as git reports them.
+type checkout struct{ repo, branch string }
+
+// checkoutTTL is how long a directory's checkout is trusted: its branch
+// changes whenever someone checks out another.
+const checkoutTTL = 10 * time.Second
+
+// checkoutCache holds the checkout of each working directory looked up,
+// with when, so a refresh doesn't run git for every session.
+var checkoutCache sync.Map // path → cachedCheckout
+
+type cachedCheckout struct {
+	checkout
+	at time.Time
+}
+
+// checkouts looks up the git checkout of each session's working
+// directory. Sessions outside git are left out.
+func checkouts(sessions []tmuxclient.Session) map[string]checkout {
+	out := make(map[string]checkout)
+	for _, s := range sessions {
+		if s.Path == "" {
+			continue
+		}
+		if c, ok := checkoutCache.Load(s.Path); ok && time.Since(c.(cachedCheckout).at) < checkoutTTL {
+			if c := c.(cachedCheckout).checkout; c.repo != "" {
+				out[s.Name] = c
+			}
+			continue
+		}
+		var c checkout
+		if root, err := git.MainRoot(s.Path); err == nil {
+			c.repo = filepath.Base(root)
+			c.branch, _ = git.Branch(s.Path)
+			out[s.Name] = c
+		}
+		checkoutCache.Store(s.Path, cachedCheckout{c, time.Now()})
+	}
+	return out
+}
+
+// checkoutLabel shows where a session works as "repo@branch", preferring
+// the repository and branch it was spawned on to what git says of its
+// directory; "" outside git.
+func (m Model) checkoutLabel(s tmuxclient.Session) string {
+	c := m.checkouts[s.Name]
+	if s.Repo != "" {
+		c.repo = filepath.Base(s.Repo)
+	}
+	if s.Branch != "" {
+		c.branch = s.Branch
+	}
+	switch {
+	case c.branch == "":
+		return c.repo
+	case c.repo == "":
+		return c.branch
+	}
+	return c.repo + "@" + c.branch
+}
@@ -0,0 +1,34 @@
+package navui
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+func TestCheckoutLabel(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{{"init", "-q", "-b", "main", dir}, {"-C", dir, "commit", "-q", "--allow-empty", "-m", "init"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Env = append(cmd.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	sessions := []tmuxclient.Session{
+		{Name: "shell", Path: dir},
+		{Name: "agent", Path: dir, Repo: "/src/api", Branch: "agent/fix"},
+		{Name: "elsewhere", Path: t.TempDir()},
+	}
+	m := Model{checkouts: checkouts(sessions)}
+	for i, want := range []string{filepath.Base(dir) + "@main", "api@agent/fix", ""} {
+		if got := m.checkoutLabel(sessions[i]); got != want {
+			t.Errorf("checkoutLabel(%s) = %q, want %q", sessions[i].Name, got, want)
+		}
+	}
+}
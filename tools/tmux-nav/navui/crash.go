@@ -0,0 +1,90 @@
+package navui
+
+import (
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Crash describes a panic in the navigator. Bubble Tea recovers it and
+// restores the terminal; Crash keeps what it prints too little of to
+// diagnose the panic.
+type Crash struct {
+	Value any
+	Stack []byte
+	// During names what was running: the message being handled, "View",
+	// or a background job.
+	During string
+	// State is a text snapshot of the model when it panicked.
+	State string
+}
+
+var lastCrash atomic.Pointer[Crash]
+
+// LastCrash returns the panic that ended the program, or nil. Programs
+// running the Model check it when Run returns tea.ErrProgramPanic.
+func LastCrash() *Crash {
+	return lastCrash.Load()
+}
+
+// catch records a panic during handling of msg (nil for View) and panics
+// again for Bubble Tea to recover. It must be deferred directly.
+func (m Model) catch(msg tea.Msg) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	during := "View"
+	if msg != nil {
+		during = "Update " + msgName(msg)
+	}
+	lastCrash.CompareAndSwap(nil, &Crash{Value: r, Stack: debug.Stack(), During: during, State: m.dump()})
+	panic(r)
+}
+
+// catchJob is catch for background jobs, which have no model to dump.
+func catchJob() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	lastCrash.CompareAndSwap(nil, &Crash{Value: r, Stack: debug.Stack(), During: "background job"})
+	panic(r)
+}
+
+// msgName names a message by its type.
+func msgName(msg tea.Msg) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", msg), "navui.")
+}
+
+// dump describes the model's state for a crash report. It leaves out
+// previews and inputs, which may hold secrets typed into a pane.
+func (m Model) dump() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "size %dx%d, mode %d, cursor %d of %d, tab %d, scroll %d\n",
+		m.width, m.height, m.mode, m.cursor, len(m.sessions), m.previewTab, m.previewScroll)
+	fmt.Fprintf(&sb, "status %q, err %v, no server %v, grouped %v, events %v, poll %v\n",
+		m.statusMsg, m.err, m.noServer, m.grouped, m.events != nil, m.pollEvery)
+	fmt.Fprintf(&sb, "select %q, attention cursor %d, %d plugins, %d cached previews\n",
+		m.selectName, m.attnCursor, len(m.Plugins), len(m.previews))
+	for _, s := range m.sessions {
+		state := ""
+		if st, ok := m.states[s.Name]; ok {
+			state = st.String()
+		}
+		fmt.Fprintf(&sb, "  %-32s %dw attached=%v agent=%q state=%q\n", s.Name, s.Windows, s.Attached, s.AgentTag, state)
+	}
+	if len(m.collapsed) > 0 {
+		keys := make([]string, 0, len(m.collapsed))
+		for k := range m.collapsed {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		fmt.Fprintf(&sb, "collapsed %s\n", strings.Join(keys, ", "))
+	}
+	return sb.String()
+}
@@ -0,0 +1,40 @@
+package navui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCrashIsRecorded(t *testing.T) {
+	lastCrash.Store(nil)
+	t.Cleanup(func() { lastCrash.Store(nil) })
+
+	m := New()
+	m.sessions = []tmuxclient.Session{{Name: "api"}}
+	m.cursor = 3 // out of range: opening its directory panics
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Update did not panic")
+			}
+		}()
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	}()
+
+	c := LastCrash()
+	if c == nil {
+		t.Fatal("LastCrash() = nil after a panic in Update")
+	}
+	if c.During != "Update tea.KeyMsg" {
+		t.Errorf("During = %q", c.During)
+	}
+	if !strings.Contains(c.State, "cursor 3 of 1") || !strings.Contains(c.State, "api") {
+		t.Errorf("State = %q, want the cursor and sessions", c.State)
+	}
+	if !strings.Contains(string(c.Stack), "navui.Model.Update") {
+		t.Errorf("Stack doesn't show Update:\n%s", c.Stack)
+	}
+}
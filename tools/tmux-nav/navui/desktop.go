@@ -0,0 +1,41 @@
+package navui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/bjornslib/tmux-nav/platform"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copyToClipboard puts text on the system clipboard using the platform's
+// helper, falling back to an OSC 52 escape sequence which most modern
+// terminals (and tmux with set-clipboard on) understand — the only way
+// over SSH.
+func copyToClipboard(text string) error {
+	if platform.Current().Copy(text) == nil {
+		return nil
+	}
+	_, err := fmt.Fprintf(os.Stdout, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
+
+// openDir opens the session's directory — its agent worktree, else its
+// active pane's working directory — in the desktop's file manager.
+func openDir(s tmuxclient.Session) tea.Cmd {
+	dir := s.Worktree
+	if dir == "" {
+		dir = s.Path
+	}
+	return func() tea.Msg {
+		if dir == "" {
+			return actionDoneMsg{err: fmt.Errorf("%s has no known directory", s.Name)}
+		}
+		if err := platform.Current().Open(dir); err != nil {
+			return actionDoneMsg{err: err}
+		}
+		return actionDoneMsg{status: "opened " + dir}
+	}
+}
@@ -0,0 +1,39 @@
+package navui
+
+import "github.com/bjornslib/tmux-nav/tmuxclient"
+
+// mergeSessions applies a fresh snapshot to the shown list. Sessions are
+// identified by name: when the snapshot holds the same sessions, they keep
+// their current rows (so a grouped list needn't be re-sorted and the
+// cursor stays put) and only their fields are updated; otherwise the
+// snapshot's order is taken. The result never aliases either input.
+func mergeSessions(shown, fresh []tmuxclient.Session) (merged []tmuxclient.Session, same bool) {
+	merged = make([]tmuxclient.Session, len(fresh))
+	if len(shown) != len(fresh) {
+		copy(merged, fresh)
+		return merged, false
+	}
+	byName := make(map[string]int, len(fresh))
+	for i, s := range fresh {
+		byName[s.Name] = i
+	}
+	for i, s := range shown {
+		j, ok := byName[s.Name]
+		if !ok {
+			copy(merged, fresh)
+			return merged, false
+		}
+		merged[i] = fresh[j]
+	}
+	return merged, true
+}
+
+// indexOf returns the row of the named session, or -1.
+func indexOf(sessions []tmuxclient.Session, name string) int {
+	for i, s := range sessions {
+		if s.Name == name {
+			return i
+		}
+	}
+	return -1
+}
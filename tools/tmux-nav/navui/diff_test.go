@@ -0,0 +1,86 @@
+package navui
+
+import (
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+func names(sessions []tmuxclient.Session) []string {
+	out := make([]string, len(sessions))
+	for i, s := range sessions {
+		out[i] = s.Name
+	}
+	return out
+}
+
+func TestCursorFollowsSessionAcrossInsert(t *testing.T) {
+	m := keys(fixture(80, 24), "j") // agent-web-docs
+	fresh := append([]tmuxclient.Session{{Name: "aaa-new"}}, m.sessions...)
+	m = send(m, sessionsLoadedMsg{sessions: fresh})
+	if got := m.sessions[m.cursor].Name; got != "agent-web-docs" {
+		t.Errorf("cursor on %q after a session was added above, want agent-web-docs", got)
+	}
+}
+
+func TestMergeKeepsRowsForSameSessions(t *testing.T) {
+	shown := []tmuxclient.Session{{Name: "b"}, {Name: "a"}, {Name: "c"}}
+	fresh := []tmuxclient.Session{{Name: "a", Windows: 2}, {Name: "b", Windows: 3}, {Name: "c"}}
+	merged, same := mergeSessions(shown, fresh)
+	if !same {
+		t.Fatal("same sessions reported as changed")
+	}
+	if got := names(merged); got[0] != "b" || got[1] != "a" || merged[0].Windows != 3 {
+		t.Errorf("merged = %+v, want shown order with fresh fields", merged)
+	}
+
+	fresh = append(fresh, tmuxclient.Session{Name: "d"})
+	merged, same = mergeSessions(shown, fresh)
+	if same || names(merged)[0] != "a" {
+		t.Errorf("merged = %v (same %t), want the fresh order after an addition", names(merged), same)
+	}
+}
+
+func TestIgnoredSessionsHidden(t *testing.T) {
+	m := fixture(80, 24)
+	m.Ignore = []string{"_*", "scratch"}
+	fresh := append([]tmuxclient.Session{{Name: "_popup"}, {Name: "scratch"}}, m.sessions...)
+	m = send(m, sessionsLoadedMsg{sessions: fresh})
+	for _, name := range names(m.sessions) {
+		if name == "_popup" || name == "scratch" {
+			t.Errorf("ignored session %q listed", name)
+		}
+	}
+}
+
+func TestKillConfirmationCancelledWhenSessionGoes(t *testing.T) {
+	m := keys(fixture(80, 24), "j", "d") // confirm killing agent-web-docs
+	var fresh []tmuxclient.Session
+	for _, s := range m.sessions {
+		if s.Name != "agent-web-docs" {
+			fresh = append(fresh, s)
+		}
+	}
+	m = send(m, sessionsLoadedMsg{sessions: fresh})
+	if m.mode != modeList {
+		t.Fatalf("mode = %v, want the confirmation cancelled", m.mode)
+	}
+	if m = keys(m, "y"); m.mode != modeList || len(m.sessions) != 3 {
+		t.Errorf("y after the target went away did something: mode %v", m.mode)
+	}
+}
+
+func TestSortedListSurvivesLastSessionGoing(t *testing.T) {
+	m := keys(fixture(80, 24), "s", "j", "j", "j")
+	last := m.sessions[m.cursor].Name
+	var fresh []tmuxclient.Session
+	for _, s := range m.sessions {
+		if s.Name != last {
+			fresh = append(fresh, s)
+		}
+	}
+	m = send(m, sessionsLoadedMsg{sessions: fresh})
+	if m.cursor >= len(m.sessions) {
+		t.Errorf("cursor %d past the %d sessions left", m.cursor, len(m.sessions))
+	}
+}
@@ -0,0 +1,156 @@
+package navui
+
+import (
+	"context"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dispatcher runs the changes the user asks for (killing, archiving,
+// prompting, replying, plugin actions) as commands, off the UI goroutine.
+// Operations on the same session run one at a time in the order they were
+// dispatched, so a prompt typed before a kill never lands after it, and
+// each reports back with the message it returns. A nil *dispatcher runs
+// operations without ordering them.
+type dispatcher struct {
+	ctx  context.Context
+	stop context.CancelFunc
+
+	mu      sync.Mutex
+	tail    map[string]chan struct{}              // closed when the session's last operation ends
+	waiting map[string]map[int]context.CancelFunc // operations not started yet, by session
+	nextID  int
+	pending map[string]bool // keys of runOnce operations not ended yet
+}
+
+func newDispatcher() *dispatcher {
+	ctx, stop := context.WithCancel(context.Background())
+	return &dispatcher{
+		ctx:     ctx,
+		stop:    stop,
+		tail:    map[string]chan struct{}{},
+		waiting: map[string]map[int]context.CancelFunc{},
+		pending: map[string]bool{},
+	}
+}
+
+// run returns a command running op once every operation dispatched
+// earlier on session has ended. Operations on no session in particular
+// ("") run straight away. An operation cancelled before it starts never
+// runs and reports nothing.
+func (d *dispatcher) run(session string, op func() tea.Msg) tea.Cmd {
+	if session == "" {
+		return d.runOn(nil, op)
+	}
+	return d.runOn([]string{session}, op)
+}
+
+// runOnce is run for upkeep found on every poll, such as restarting a
+// crashed agent: while an operation dispatched under key hasn't ended,
+// dispatching another returns nil rather than doing the work twice.
+func (d *dispatcher) runOnce(key, session string, op func() tea.Msg) tea.Cmd {
+	if d == nil {
+		return op
+	}
+	d.mu.Lock()
+	if d.pending[key] {
+		d.mu.Unlock()
+		return nil
+	}
+	d.pending[key] = true
+	d.mu.Unlock()
+	run := d.run(session, op)
+	return func() tea.Msg {
+		defer func() {
+			d.mu.Lock()
+			delete(d.pending, key)
+			d.mu.Unlock()
+		}()
+		return run()
+	}
+}
+
+// runOn is run for an operation on several sessions at once, such as a
+// bulk kill: it waits for the operations dispatched earlier on each of
+// them, and those dispatched later on any of them wait for it. Cancelling
+// any of its sessions cancels it.
+func (d *dispatcher) runOn(sessions []string, op func() tea.Msg) tea.Cmd {
+	if d == nil {
+		return op
+	}
+	ctx, cancel := context.WithCancel(d.ctx)
+	d.mu.Lock()
+	var prevs []chan struct{}
+	done := make(chan struct{})
+	id := d.nextID
+	d.nextID++
+	for _, session := range sessions {
+		if prev := d.tail[session]; prev != nil {
+			prevs = append(prevs, prev)
+		}
+		d.tail[session] = done
+		if d.waiting[session] == nil {
+			d.waiting[session] = map[int]context.CancelFunc{}
+		}
+		d.waiting[session][id] = cancel
+	}
+	d.mu.Unlock()
+
+	return func() tea.Msg {
+		defer cancel()
+		defer d.finish(sessions, id, done)
+		for _, prev := range prevs {
+			select {
+			case <-prev:
+			case <-ctx.Done():
+				<-prev // keep the order for the operations behind this one
+			}
+		}
+		d.mu.Lock()
+		for _, session := range sessions {
+			delete(d.waiting[session], id)
+		}
+		d.mu.Unlock()
+		if ctx.Err() != nil {
+			return nil
+		}
+		return op()
+	}
+}
+
+// finish marks the operation id on sessions as ended.
+func (d *dispatcher) finish(sessions []string, id int, done chan struct{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, session := range sessions {
+		delete(d.waiting[session], id)
+		if len(d.waiting[session]) == 0 {
+			delete(d.waiting, session)
+		}
+		if d.tail[session] == done {
+			delete(d.tail, session)
+		}
+	}
+	close(done)
+}
+
+// cancel drops the operations on session that haven't started, e.g.
+// prompts queued for a session about to be killed.
+func (d *dispatcher) cancel(session string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, cancel := range d.waiting[session] {
+		cancel()
+	}
+}
+
+// close drops every operation that hasn't started.
+func (d *dispatcher) close() {
+	if d != nil {
+		d.stop()
+	}
+}
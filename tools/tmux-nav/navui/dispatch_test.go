@@ -0,0 +1,113 @@
+package navui
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDispatcherOrdersPerSession(t *testing.T) {
+	d := newDispatcher()
+	var mu sync.Mutex
+	var order []string
+	op := func(name string, delay time.Duration) func() tea.Msg {
+		return func() tea.Msg {
+			time.Sleep(delay)
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return name
+		}
+	}
+	first := d.run("api", op("prompt", 20*time.Millisecond))
+	second := d.run("api", op("kill", 0))
+	other := d.run("web", op("other", 0))
+
+	// Bubble Tea runs commands in any order; start them backwards.
+	var wg sync.WaitGroup
+	for _, cmd := range []tea.Cmd{other, second, first} {
+		wg.Add(1)
+		go func() { defer wg.Done(); cmd() }()
+	}
+	wg.Wait()
+	if len(order) != 3 || order[0] != "other" || order[1] != "prompt" || order[2] != "kill" {
+		t.Errorf("ran %v, want other, then prompt before kill", order)
+	}
+}
+
+func TestDispatcherCancel(t *testing.T) {
+	d := newDispatcher()
+	release := make(chan struct{})
+	running := d.run("api", func() tea.Msg { <-release; return "running" })
+	queued := d.run("api", func() tea.Msg { t.Error("cancelled operation ran"); return nil })
+
+	got := make(chan tea.Msg, 2)
+	go func() { got <- running() }()
+	go func() { got <- queued() }()
+	time.Sleep(10 * time.Millisecond)
+	d.cancel("api")
+	close(release)
+	msgs := []tea.Msg{<-got, <-got}
+	if (msgs[0] != "running" || msgs[1] != nil) && (msgs[0] != nil || msgs[1] != "running") {
+		t.Errorf("messages = %v, want the running operation's only", msgs)
+	}
+	if len(d.tail) != 0 || len(d.waiting) != 0 {
+		t.Errorf("dispatcher kept state: tail %v, waiting %v", d.tail, d.waiting)
+	}
+}
+
+func TestDispatcherRunOnWaitsForEverySession(t *testing.T) {
+	d := newDispatcher()
+	var mu sync.Mutex
+	var order []string
+	op := func(name string, delay time.Duration) func() tea.Msg {
+		return func() tea.Msg {
+			time.Sleep(delay)
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return name
+		}
+	}
+	api := d.run("api", op("api", 20*time.Millisecond))
+	web := d.run("web", op("web", 10*time.Millisecond))
+	bulk := d.runOn([]string{"api", "web"}, op("bulk", 0))
+	after := d.run("web", op("after", 0))
+
+	var wg sync.WaitGroup
+	for _, cmd := range []tea.Cmd{after, bulk, web, api} {
+		wg.Add(1)
+		go func() { defer wg.Done(); cmd() }()
+	}
+	wg.Wait()
+	if len(order) != 4 || order[2] != "bulk" || order[3] != "after" {
+		t.Errorf("ran %v, want the bulk operation after api and web, before after", order)
+	}
+	if len(d.tail) != 0 || len(d.waiting) != 0 {
+		t.Errorf("dispatcher kept state: tail %v, waiting %v", d.tail, d.waiting)
+	}
+}
+
+func TestDispatcherRunOnce(t *testing.T) {
+	d := newDispatcher()
+	release := make(chan struct{})
+	kill := d.run("api", func() tea.Msg { <-release; return "kill" })
+	restart := d.runOnce("supervise api", "api", func() tea.Msg { return "restart" })
+	if again := d.runOnce("supervise api", "api", func() tea.Msg { return "again" }); again != nil {
+		t.Error("runOnce dispatched a second restart while the first was pending")
+	}
+
+	got := make(chan tea.Msg, 2)
+	go func() { got <- restart() }()
+	go func() { got <- kill() }()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	if first, second := <-got, <-got; first != "kill" || second != "restart" {
+		t.Errorf("ran %v then %v, want the restart behind the kill", first, second)
+	}
+	if d.runOnce("supervise api", "api", func() tea.Msg { return nil }) == nil {
+		t.Error("runOnce refused a restart after the last one ended")
+	}
+}
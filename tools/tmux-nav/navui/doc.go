@@ -0,0 +1,16 @@
+// Package navui is the interactive session navigator as a Bubble Tea
+// model, for embedding in other programs:
+//
+//	m := navui.New()
+//	final, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+//	if fm, ok := final.(navui.Model); ok {
+//		fm.Close()
+//		if fm.AttachSession != "" {
+//			err = attach.Attach(fm.AttachSession, fm.Strategy, attach.Options{})
+//		}
+//	}
+//
+// The exported fields of Model configure it before the program starts and
+// report the user's choice after it ends. Part of the module's stable API;
+// see package tmuxclient.
+package navui
@@ -0,0 +1,162 @@
+package navui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Refresh pacing. Session changes arrive as control-mode notifications;
+// polling only catches what tmux doesn't announce (agent output, activity
+// times), so it backs off while nothing changes: up to maxPoll without a
+// control-mode connection and maxPollWatched with one. Model.Refresh
+// overrides minPoll.
+const (
+	minPoll        = 2 * time.Second
+	maxPoll        = 10 * time.Second
+	maxPollWatched = 30 * time.Second
+
+	// reloadDelay coalesces a burst of notifications into one reload.
+	reloadDelay = 100 * time.Millisecond
+	// rewatchDelay is how long to wait before reconnecting control mode.
+	rewatchDelay = 10 * time.Second
+)
+
+// watchMsg reports the outcome of connecting the control-mode client.
+type watchMsg struct {
+	ch     <-chan tmuxclient.Notification
+	cancel context.CancelFunc
+	err    error
+}
+
+// tmuxEventMsg carries one control-mode notification; ok is false once the
+// connection has ended.
+type tmuxEventMsg struct {
+	n  tmuxclient.Notification
+	ok bool
+}
+
+// reloadMsg triggers a debounced session reload.
+type reloadMsg struct{}
+
+// watchTmux connects a control-mode client for session-change
+// notifications.
+func watchTmux() tea.Msg {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := tmuxclient.Watch(ctx)
+	if err != nil {
+		cancel()
+		return watchMsg{err: err}
+	}
+	return watchMsg{ch: ch, cancel: cancel}
+}
+
+// rewatchLater retries the control-mode connection after rewatchDelay.
+func rewatchLater() tea.Cmd {
+	return tea.Tick(rewatchDelay, func(time.Time) tea.Msg { return watchTmux() })
+}
+
+// waitTmuxEvent blocks for the next notification on ch.
+func waitTmuxEvent(ch <-chan tmuxclient.Notification) tea.Cmd {
+	return func() tea.Msg {
+		n, ok := <-ch
+		return tmuxEventMsg{n, ok}
+	}
+}
+
+// handleTmuxEvent schedules a reload for notifications that change the
+// session list, or the panes while drilled into a session, coalescing
+// bursts.
+func (m Model) handleTmuxEvent(msg tmuxEventMsg) (tea.Model, tea.Cmd) {
+	if !msg.ok {
+		// Our session was killed or the server went away: reconnect, and
+		// poll until that succeeds.
+		m.closeWatch()
+		return m, tea.Batch(m.refresh(), watchTmux)
+	}
+	wait := waitTmuxEvent(m.events)
+	drilled := m.viewMode() == modeWindows || m.viewMode() == modePanes
+	if !(msg.n.ChangesSessions() || drilled && msg.n.ChangesPanes()) || m.reloadPending {
+		return m, wait
+	}
+	m.reloadPending = true
+	return m, tea.Batch(wait, tea.Tick(reloadDelay, func(time.Time) tea.Msg { return reloadMsg{} }))
+}
+
+// serverGone switches to waiting for the tmux server. Its sessions went
+// with it, but the selection is kept for when they come back, and polling
+// stays brisk so the recovery shows promptly.
+func (m Model) serverGone() Model {
+	if !m.noServer && m.cursor < len(m.sessions) {
+		m.selectName = m.sessions[m.cursor].Name
+	}
+	m.noServer = true
+	m.sessions, m.cursor = nil, 0
+	m.err = nil
+	m.pollEvery = m.fastestPoll()
+	return m
+}
+
+// closeWatch disconnects the control-mode client, if any.
+func (m *Model) closeWatch() {
+	if m.stopWatch != nil {
+		m.stopWatch()
+	}
+	m.events, m.stopWatch = nil, nil
+}
+
+// Close releases the model's tmux connection and drops background work and
+// operations that haven't started. Call it once the program has finished
+// with the model.
+func (m Model) Close() {
+	m.closeWatch()
+	if m.follow != nil {
+		m.follow.Close()
+	}
+	m.pool.Close()
+	m.ops.close()
+}
+
+// fastestPoll is the delay between polls while things change.
+func (m Model) fastestPoll() time.Duration {
+	if m.Refresh > 0 {
+		return m.Refresh
+	}
+	return minPoll
+}
+
+// pollInterval is the delay until the next poll.
+func (m Model) pollInterval() time.Duration {
+	if m.pollEvery == 0 {
+		return m.fastestPoll()
+	}
+	return m.pollEvery
+}
+
+// adaptPoll polls faster again after a change and backs off, doubling the
+// interval, while refreshes find nothing new.
+func (m *Model) adaptPoll(changed bool) {
+	if changed {
+		m.pollEvery = m.fastestPoll()
+		return
+	}
+	limit := maxPoll
+	if m.events != nil {
+		limit = maxPollWatched
+	}
+	m.pollEvery = min(2*m.pollInterval(), max(limit, m.fastestPoll()))
+}
+
+// fingerprint summarises what a refresh shows, to tell whether anything
+// changed since the last one.
+func fingerprint(msg sessionsLoadedMsg) string {
+	var sb strings.Builder
+	for _, s := range msg.sessions {
+		fmt.Fprintf(&sb, "%s %d %t %d %s %t|", s.Name, s.Windows, s.Attached, s.LastUsed.Unix(), msg.states[s.Name], msg.stuck[s.Name])
+	}
+	return sb.String()
+}
@@ -0,0 +1,109 @@
+package navui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/attach"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestServerGoneAndBack(t *testing.T) {
+	gone := errors.New("exit status 1: no server running on /tmp/tmux-1000/default")
+	line := strings.Join([]string{"agent-web-docs", "1", "", "1700000000", "0.0", "claude", "/src/web",
+		"web", "", "", "", "0", "", "", "0", "", "0", "1690000000", "", "50", "0"}, "|^|")
+	f := tmuxtest.New().
+		On("list-sessions", "", gone). // ListSessions: no sessions
+		On("list-sessions", "", gone). // Ping: no server
+		On("list-sessions", line+"\n", nil)
+	tmuxtest.Install(t, f)
+	tmuxclient.SetCacheTTL(0)
+	t.Cleanup(func() { tmuxclient.SetCacheTTL(tmuxclient.DefaultCacheTTL) })
+
+	m := fixture(80, 24)
+	m = keys(m, "j") // agent-web-docs
+	m = send(m, loadSessions())
+	if !m.noServer || len(m.sessions) != 0 {
+		t.Fatalf("noServer = %v with %d sessions, want the waiting state", m.noServer, len(m.sessions))
+	}
+	m = send(m, errMsg{errors.New("capture-pane: no server running")})
+	if m.err != nil {
+		t.Errorf("err = %v while waiting for the server, want it suppressed", m.err)
+	}
+	if !strings.Contains(m.View(), "tmux server gone") {
+		t.Error("view doesn't say the server is gone")
+	}
+
+	m = send(m, loadSessions())
+	if m.noServer || m.statusMsg != "tmux server is back" {
+		t.Errorf("noServer = %v, status %q after the server returned", m.noServer, m.statusMsg)
+	}
+	if len(m.sessions) != 1 || m.sessions[m.cursor].Name != "agent-web-docs" {
+		t.Errorf("cursor on %v, want the session selected before the server went", m.sessions)
+	}
+}
+
+func TestPaneChangesReloadOnlyWhenDrilledIn(t *testing.T) {
+	split := tmuxEventMsg{tmuxclient.Notification{Name: "layout-change", Args: []string{"@1"}}, true}
+	m := fixture(80, 24)
+	m.events = make(chan tmuxclient.Notification)
+	if m = send(m, split); m.reloadPending {
+		t.Error("a layout change reloaded the session list")
+	}
+	m = keys(m, "l")
+	if m = send(m, split); !m.reloadPending {
+		t.Error("a layout change didn't reload the window list")
+	}
+}
+
+func TestTmuxNotInstalled(t *testing.T) {
+	m := fixture(80, 24)
+	m = send(m, errMsg{&tmuxclient.Error{Command: "list-sessions", Err: tmuxclient.ErrTmuxNotInstalled}})
+	if m.err != nil || !m.noTmux {
+		t.Fatalf("err = %v, noTmux = %v; want the install guidance instead of an error", m.err, m.noTmux)
+	}
+	if !strings.Contains(m.View(), "tmux isn't installed") {
+		t.Error("view doesn't say tmux is missing")
+	}
+	m = send(m, sessionsLoadedMsg{})
+	if m.noTmux {
+		t.Error("still asking for tmux after sessions loaded")
+	}
+}
+
+func TestStrategyCyclesThroughAll(t *testing.T) {
+	m := fixture(80, 24)
+	m.Strategy = attach.NewTabCC
+	seen := map[attach.Strategy]bool{}
+	for range attach.Strategies() {
+		m = keys(m, "S")
+		seen[m.Strategy] = true
+	}
+	if m.Strategy != attach.NewTabCC || len(seen) != len(attach.Strategies()) {
+		t.Errorf("S visited %d of %d strategies, ending on %s", len(seen), len(attach.Strategies()), attach.StrategyName(m.Strategy))
+	}
+	if !seen[attach.NewWindowCC] {
+		t.Error("S never selects the new iTerm2 window strategy")
+	}
+}
+
+func TestNewSessionKeyDeniesOnlyWhileWaiting(t *testing.T) {
+	m := fixture(80, 24) // the cursor is on an agent waiting for permission
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m = next.(Model); m.mode != modeList || cmd == nil {
+		t.Errorf("n on a waiting agent left mode %v with command %v; want it denied", m.mode, cmd != nil)
+	}
+
+	m = keys(m, "j") // a working agent
+	next, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = next.(Model)
+	if m.mode != modeInput || m.inputFor != inputSession {
+		t.Errorf("n left mode %v, input %v; want the new-session input", m.mode, m.inputFor)
+	}
+	if cmd != nil {
+		t.Error("n returned a command; it would answer the agent's prompt")
+	}
+}
@@ -0,0 +1,129 @@
+package navui
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/bjornslib/tmux-nav/fuzzy"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// matches reports whether session i passes the list filter.
+func (m Model) matches(i int) bool {
+	name := m.sessions[i].Name
+	if m.attnOnly && !m.needsAttention(name) {
+		return false
+	}
+	return fuzzy.Match(m.filter, name)
+}
+
+// unignored drops the sessions matching an Ignore pattern.
+func (m Model) unignored(sessions []tmuxclient.Session) []tmuxclient.Session {
+	if len(m.Ignore) == 0 {
+		return sessions
+	}
+	return slices.DeleteFunc(slices.Clone(sessions), func(s tmuxclient.Session) bool {
+		return slices.ContainsFunc(m.Ignore, func(p string) bool {
+			ok, _ := path.Match(p, s.Name)
+			return ok
+		})
+	})
+}
+
+// filtered reports whether the list is narrowed by the typed filter or to
+// the sessions needing attention.
+func (m Model) filtered() bool {
+	return m.filter != "" || m.attnOnly
+}
+
+// toggleAttentionOnly narrows the list to the sessions needing attention,
+// or widens it again.
+func (m Model) toggleAttentionOnly() (tea.Model, tea.Cmd) {
+	m.attnOnly = !m.attnOnly
+	if !m.keepCursorVisible() {
+		return m, nil
+	}
+	m.previewScroll = 0
+	return m, m.loadPreview()
+}
+
+// openFilter starts typing the list filter, keeping the current one.
+func (m Model) openFilter() Model {
+	m.filtering = true
+	m.filterIn = lineInput{prompt: "/", value: []rune(m.filter)}
+	return m
+}
+
+// handleFilterKey narrows the list as the filter is typed. enter keeps the
+// filter and returns to the list keys; esc clears it. The arrows still
+// move the cursor among the matches.
+func (m Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "down":
+		delta := 1
+		if msg.String() == "up" {
+			delta = -1
+		}
+		if m.moveCursor(delta) {
+			return m, m.loadPreview()
+		}
+		return m, nil
+	}
+	submitted, cancelled := m.filterIn.update(msg)
+	switch {
+	case cancelled:
+		m.filtering = false
+		return m.setFilter("")
+	case submitted:
+		m.filtering = false
+		return m, nil
+	}
+	return m.setFilter(m.filterIn.text())
+}
+
+// setFilter narrows the list to the sessions matching filter, moving the
+// cursor to the first match if it was on a session filtered out.
+func (m Model) setFilter(filter string) (tea.Model, tea.Cmd) {
+	if filter == m.filter {
+		return m, nil
+	}
+	m.filter = filter
+	if !m.keepCursorVisible() {
+		return m, nil
+	}
+	m.previewScroll = 0
+	return m, m.loadPreview()
+}
+
+// keepCursorVisible moves the cursor to the first row shown when its
+// session is hidden, reporting whether it moved.
+func (m *Model) keepCursorVisible() bool {
+	rows := m.navRows()
+	if len(rows) == 0 || slices.Contains(rows, m.cursor) {
+		return false
+	}
+	m.cursor = rows[0]
+	return true
+}
+
+// noMatches reports whether a filter hides every session.
+func (m Model) noMatches() bool {
+	return m.filtered() && len(m.navRows()) == 0
+}
+
+// filterStatus describes the active filter for the header.
+func (m Model) filterStatus() string {
+	if !m.filtered() {
+		return ""
+	}
+	label := "/" + m.filter
+	if m.attnOnly {
+		label = "needs attention"
+		if m.filter != "" {
+			label += " /" + m.filter
+		}
+	}
+	return fmt.Sprintf("  %s (%d of %d)", label, len(m.navRows()), len(m.sessions))
+}
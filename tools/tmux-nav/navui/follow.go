@@ -0,0 +1,155 @@
+package navui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// followChunk is how much followed output is read at a time.
+	followChunk = 4 << 10
+	// followMaxBytes caps the followed output kept for the preview.
+	followMaxBytes = 64 << 10
+)
+
+// followStartedMsg reports the outcome of starting to follow a session.
+type followStartedMsg struct {
+	session string
+	r       io.ReadCloser
+	err     error
+}
+
+// followOutputMsg carries output read from the followed pane; err is set
+// once following has ended.
+type followOutputMsg struct {
+	r    io.ReadCloser
+	data []byte
+	err  error
+}
+
+// toggleFollow starts streaming the selected session's output into the
+// preview as it is written, or stops.
+func (m Model) toggleFollow() (tea.Model, tea.Cmd) {
+	if m.follow != nil {
+		name := m.followName
+		m, stop := m.stopFollow()
+		m.statusMsg = fmt.Sprintf("stopped following %q", name)
+		return m, tea.Batch(stop, m.loadPreview())
+	}
+	if len(m.sessions) == 0 {
+		return m, nil
+	}
+	s := m.sessions[m.cursor]
+	if s.Piped {
+		m.statusMsg = fmt.Sprintf("can't follow %q: its pane is already piped (capture)", s.Name)
+		return m, nil
+	}
+	m.statusMsg = fmt.Sprintf("following %q…", s.Name)
+	return m, m.ops.run(s.Name, func() tea.Msg {
+		r, err := tmuxclient.Follow(context.Background(), s.Name)
+		return followStartedMsg{s.Name, r, err}
+	})
+}
+
+// stopFollow stops following, closing the pane's pipe in the background.
+func (m Model) stopFollow() (Model, tea.Cmd) {
+	r, name := m.follow, m.followName
+	m.follow, m.followName, m.followBuf = nil, "", ""
+	if r == nil {
+		return m, nil
+	}
+	return m, m.ops.run(name, func() tea.Msg {
+		r.Close()
+		return nil
+	})
+}
+
+// startedFollow begins reading a newly followed pane, unless following
+// was stopped or restarted meanwhile.
+func (m Model) startedFollow(msg followStartedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		if errors.Is(msg.err, tmuxclient.ErrPiped) {
+			m.statusMsg = fmt.Sprintf("can't follow %q: its pane is already piped (capture)", msg.session)
+		} else {
+			m.statusMsg = msg.err.Error()
+		}
+		return m, nil
+	}
+	if m.follow != nil {
+		return m, m.ops.run(msg.session, func() tea.Msg {
+			msg.r.Close()
+			return nil
+		})
+	}
+	m.follow, m.followName, m.followBuf = msg.r, msg.session, ""
+	m.statusMsg = fmt.Sprintf("following %q", msg.session)
+	return m, readFollow(msg.r)
+}
+
+// readFollow waits for the next output of the followed pane.
+func readFollow(r io.ReadCloser) tea.Cmd {
+	return func() tea.Msg {
+		buf := make([]byte, followChunk)
+		n, err := r.Read(buf)
+		return followOutputMsg{r, buf[:n], err}
+	}
+}
+
+// followed appends output of the followed pane and reads on.
+func (m Model) followed(msg followOutputMsg) (tea.Model, tea.Cmd) {
+	if msg.r != m.follow {
+		return m, nil // stopped meanwhile
+	}
+	m.followBuf = capTail(m.followBuf+string(msg.data), followMaxBytes)
+	if msg.err != nil {
+		name := m.followName
+		m, stop := m.stopFollow()
+		m.statusMsg = fmt.Sprintf("stopped following %q: %v", name, msg.err)
+		return m, tea.Batch(stop, m.loadPreview())
+	}
+	return m, readFollow(msg.r)
+}
+
+// following reports whether the preview shows the selected session's
+// followed output.
+func (m Model) following() bool {
+	return m.follow != nil && len(m.sessions) > 0 && m.sessions[m.cursor].Name == m.followName
+}
+
+// controlSeq matches terminal control sequences: CSI (colours, cursor
+// movement, erasing), OSC (titles, hyperlinks) and two-byte escapes.
+var controlSeq = regexp.MustCompile(`\x1b\[[0-9;?<=>]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[()][0-9A-Za-z]|\x1b[=>78DEHMc]`)
+
+// followText turns raw pane output into lines for the preview: colours
+// are kept, other control sequences dropped, and a carriage return
+// starts its line over.
+func followText(raw string) string {
+	raw = controlSeq.ReplaceAllStringFunc(raw, func(seq string) string {
+		if strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m") {
+			return seq
+		}
+		return ""
+	})
+	raw = strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\n' && r != '\r' && r != '\t' && r != 0x1b || r == 0x7f {
+			return -1
+		}
+		return r
+	}, strings.ReplaceAll(raw, "\r\n", "\n"))
+	lines := strings.Split(raw, "\n")
+	for i, l := range lines {
+		l = strings.TrimRight(l, "\r")
+		if j := strings.LastIndexByte(l, '\r'); j >= 0 {
+			l = l[j+1:]
+		}
+		lines[i] = l
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,18 @@
+package navui
+
+import "testing"
+
+func TestFollowText(t *testing.T) {
+	for _, tc := range []struct{ raw, want string }{
+		{"plain\r\nlines\n", "plain\nlines\n"},
+		{"\x1b[32mok\x1b[0m\n", "\x1b[32mok\x1b[0m\n"},
+		{"\x1b[2K\x1b[1Gworking…\x1b[?25l\n", "working…\n"},
+		{"10%\r50%\r100%\n", "100%\n"},
+		{"done\r\n\x1b]0;title\x07bell\a\n", "done\nbell\n"},
+		{"waiting\r", "waiting"},
+	} {
+		if got := followText(tc.raw); got != tc.want {
+			t.Errorf("followText(%q) = %q, want %q", tc.raw, got, tc.want)
+		}
+	}
+}
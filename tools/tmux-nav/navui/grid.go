@@ -0,0 +1,155 @@
+package navui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// cardWidth is the inner width of a dashboard card.
+const cardWidth = 32
+
+// gridLinesMsg carries the last output line of each agent session.
+type gridLinesMsg struct{ lines map[string]string }
+
+// loadGridLines captures every agent pane for its last line of output.
+func (m Model) loadGridLines() tea.Cmd {
+	var names []string
+	for _, i := range m.gridAgents() {
+		names = append(names, m.sessions[i].Name)
+	}
+	return m.background(m.pool.Group("grid"), func() tea.Msg {
+		lines := make(map[string]string, len(names))
+		for _, name := range names {
+			if content, err := tmuxclient.CaptureText(context.Background(), name, 30); err == nil {
+				lines[name] = lastOutputLine(content)
+			}
+		}
+		return gridLinesMsg{lines}
+	})
+}
+
+// lastOutputLine returns the last non-blank line that isn't Claude Code's
+// prompt box or status chrome.
+func lastOutputLine(content string) string {
+	lines := strings.Split(content, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		l := strings.TrimSpace(lines[i])
+		if l == "" || strings.HasPrefix(l, ">") || strings.Contains(l, "for shortcuts") ||
+			strings.IndexAny(l, "│╭╰─") == 0 {
+			continue
+		}
+		return strings.TrimLeft(l, "⏺⎿ ")
+	}
+	return ""
+}
+
+// gridAgents returns indices into m.sessions of the agent sessions shown as
+// cards.
+func (m Model) gridAgents() []int {
+	var idx []int
+	for i, s := range m.sessions {
+		if agent.IsAgent(s) {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// gridColumns is how many cards fit side by side.
+func (m Model) gridColumns() int {
+	return safeMax(1, m.width/(cardWidth+4))
+}
+
+// gridPos is the position of the selected session among the cards.
+func (m Model) gridPos(cards []int) int {
+	for p, i := range cards {
+		if i == m.cursor {
+			return p
+		}
+	}
+	return 0
+}
+
+func (m Model) handleGridKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	cards := m.gridAgents()
+	pos, cols := m.gridPos(cards), m.gridColumns()
+	move := 0
+	switch m.action(msg) {
+	case ActQuit:
+		return m, tea.Quit
+	case ActBack, ActGrid:
+		m.mode = modeList
+		return m, m.loadPreview()
+	case ActDrillOut:
+		move = -1
+	case ActDrillIn:
+		move = 1
+	case ActUp:
+		move = -cols
+	case ActDown:
+		move = cols
+	case ActAttach:
+		if len(cards) > 0 {
+			m.AttachSession = m.sessions[cards[pos]].Name
+			return m, tea.Quit
+		}
+	case ActRefresh:
+		return m, tea.Batch(m.reload(), m.loadGridLines())
+	case ActApprove, ActDeny:
+		return m, m.respond(m.action(msg) == ActApprove)
+	case ActInterrupt:
+		return m, m.interrupt()
+	case ActPrompt:
+		if len(cards) > 0 {
+			return m.openInput(inputPrompt, "Prompt "+m.sessions[m.cursor].Name+":"), nil
+		}
+	}
+	if move != 0 && len(cards) > 0 {
+		if p := pos + move; p >= 0 && p < len(cards) {
+			m.cursor = cards[p]
+		}
+	}
+	return m, nil
+}
+
+// renderGrid lays agent sessions out as cards, row by row.
+func (m Model) renderGrid() string {
+	cards := m.gridAgents()
+	if len(cards) == 0 {
+		return normalStyle.Render("(no agent sessions)")
+	}
+	cols := m.gridColumns()
+	var rows []string
+	for start := 0; start < len(cards); start += cols {
+		var row []string
+		for _, i := range cards[start:min(start+cols, len(cards))] {
+			row = append(row, m.renderCard(i))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// renderCard shows one agent: name, state, elapsed time, cost, the task it
+// was given and its last line of output.
+func (m Model) renderCard(i int) string {
+	s := m.sessions[i]
+	name := normalStyle.Bold(true).Render(truncate(s.Name, cardWidth-2))
+	stats := fmt.Sprintf("%-12s %6s", m.stateLabel(s.Name), formatAge(s.Created))
+	if d, ok := m.details[s.Name]; ok {
+		stats += fmt.Sprintf("  $%.2f", d.Usage.CostUSD)
+	}
+	task := normalStyle.Render(truncate(strings.Join(strings.Fields(s.Task), " "), cardWidth-2))
+	last := helpStyle.Render(truncate(m.gridLines[s.Name], cardWidth-2))
+	body := lipgloss.JoinVertical(lipgloss.Left, name, normalStyle.Render(stats), task, last)
+	if i == m.cursor {
+		return selectedCardStyle.Render(body)
+	}
+	return cardStyle.Render(body)
+}
@@ -0,0 +1,215 @@
+package navui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// repoRoots maps each agent session to the repository root it works in.
+func repoRoots(sessions []tmuxclient.Session) map[string]string {
+	roots := make(map[string]string)
+	for _, s := range sessions {
+		if root := agent.RepoRoot(s); root != "" {
+			roots[s.Name] = root
+		}
+	}
+	return roots
+}
+
+// sortByGroup orders sessions so each repository's agents are adjacent,
+// repositories by name and non-agent sessions last, keeping the cursor on
+// the same session.
+func (m *Model) sortByGroup() {
+	if len(m.sessions) == 0 {
+		return
+	}
+	current := m.sessions[m.cursor].Name
+	sort.SliceStable(m.sessions, func(a, b int) bool {
+		ga, gb := m.groups[m.sessions[a].Name], m.groups[m.sessions[b].Name]
+		if (ga == "") != (gb == "") {
+			return gb == ""
+		}
+		return groupLabel(ga) < groupLabel(gb)
+	})
+	m.selectSession(current)
+	if root := m.groups[current]; m.collapsed[root] {
+		m.cursor = m.firstOfGroup(root)
+	}
+}
+
+// firstOfGroup returns the index of the group's first session.
+func (m Model) firstOfGroup(root string) int {
+	for i, s := range m.sessions {
+		if m.groups[s.Name] == root {
+			return i
+		}
+	}
+	return 0
+}
+
+// selectSession moves the cursor to the named session, if present.
+func (m *Model) selectSession(name string) {
+	for i, s := range m.sessions {
+		if s.Name == name {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// toggleGrouping switches between the flat and the grouped list. Turning
+// grouping off reloads to restore tmux's order.
+func (m Model) toggleGrouping() (tea.Model, tea.Cmd) {
+	m.grouped = !m.grouped
+	if m.grouped {
+		m.sortByGroup()
+		return m, nil
+	}
+	if len(m.sessions) > 0 {
+		m.selectName = m.sessions[m.cursor].Name
+	}
+	return m, m.refresh()
+}
+
+// toggleGroup collapses or expands the selected session's group.
+func (m *Model) toggleGroup() {
+	if len(m.sessions) == 0 {
+		return
+	}
+	root := m.groups[m.sessions[m.cursor].Name]
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	m.collapsed[root] = !m.collapsed[root]
+	if m.collapsed[root] {
+		// A collapsed group is represented by its first session.
+		m.cursor = m.firstOfGroup(root)
+	}
+}
+
+// navRows returns the session indices the cursor can rest on: every
+// session, except that a collapsed group only offers its first one. While
+// the list is filtered, they are the matching sessions, groups expanded.
+func (m Model) navRows() []int {
+	var rows []int
+	for i, s := range m.sessions {
+		if m.filtered() {
+			if m.matches(i) {
+				rows = append(rows, i)
+			}
+			continue
+		}
+		root := m.groups[s.Name]
+		if m.grouped && m.collapsed[root] && i > 0 && m.groups[m.sessions[i-1].Name] == root {
+			continue
+		}
+		rows = append(rows, i)
+	}
+	return rows
+}
+
+// moveCursor steps the cursor by delta rows, reporting whether it moved.
+func (m *Model) moveCursor(delta int) bool {
+	rows := m.navRows()
+	pos := 0
+	for p, i := range rows {
+		if i == m.cursor {
+			pos = p
+		}
+	}
+	next := pos + delta
+	if next < 0 || next >= len(rows) {
+		return false
+	}
+	m.cursor = rows[next]
+	m.previewScroll = 0
+	return true
+}
+
+// renderGroupedList renders sessions under one header per repository, with
+// the group's aggregate agent state.
+func (m Model) renderGroupedList(w int) string {
+	var rows []string
+	cur := 0
+	last, shown := "", false
+	for i, s := range m.sessions {
+		if !m.matches(i) {
+			continue
+		}
+		root := m.groups[s.Name]
+		collapsed := m.collapsed[root] && !m.filtered()
+		first := !shown || last != root
+		last, shown = root, true
+		if first {
+			arrow := "▾"
+			if collapsed {
+				arrow = "▸"
+			}
+			header := fmt.Sprintf("%s %s  %s", arrow, groupLabel(root), m.groupSummary(root))
+			if collapsed && m.groups[m.sessions[m.cursor].Name] == root {
+				cur = len(rows)
+				rows = append(rows, selectedStyle.Render(header))
+			} else {
+				rows = append(rows, groupHeaderStyle.Render(header))
+			}
+		}
+		if !collapsed {
+			if i == m.cursor {
+				cur = len(rows)
+			}
+			rows = append(rows, m.renderRow(i))
+		}
+	}
+	return m.pageList(rows, cur, w)
+}
+
+// groupSummary counts the group's agents by state, e.g.
+// "3 working, 1 waiting".
+func (m Model) groupSummary(root string) string {
+	if root == "" {
+		return ""
+	}
+	counts := map[string]int{}
+	var order []string
+	for _, s := range m.sessions {
+		if m.groups[s.Name] != root {
+			continue
+		}
+		label := m.stateLabel(s.Name)
+		if counts[label] == 0 {
+			order = append(order, label)
+		}
+		counts[label]++
+	}
+	sort.Strings(order)
+	parts := make([]string, len(order))
+	for i, label := range order {
+		parts[i] = fmt.Sprintf("%d %s", counts[label], label)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// groupLabel names a group by its root, abbreviating the home directory.
+func groupLabel(root string) string {
+	if root == "" {
+		return "other sessions"
+	}
+	return tildePath(root)
+}
+
+// tildePath abbreviates the home directory in path to ~.
+func tildePath(path string) string {
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.Join("~", rel)
+		}
+	}
+	return path
+}
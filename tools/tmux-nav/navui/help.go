@@ -0,0 +1,116 @@
+package navui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpRow is one line of the help overlay: keys and what they do, or a
+// section heading when keys is empty.
+type helpRow struct{ keys, text string }
+
+// helpRows lists every bound action, then the attention view's macros and
+// the plugins' actions.
+func (m Model) helpRows() []helpRow {
+	var rows []helpRow
+	for _, b := range m.Keys.Bindings() {
+		if l := m.Keys.label(b.Action); l != "" {
+			rows = append(rows, helpRow{l, b.Help})
+		}
+	}
+	if macros := m.macros(); len(macros) > 0 {
+		rows = append(rows, helpRow{"", "Needs-attention view"})
+		for _, mac := range macros {
+			rows = append(rows, helpRow{mac.Key, mac.Name})
+		}
+	}
+	var actions []helpRow
+	for _, p := range m.Plugins {
+		for _, a := range p.Actions {
+			actions = append(actions, helpRow{a.Key, a.Name + " (" + p.Name + ")"})
+		}
+	}
+	if len(actions) > 0 {
+		rows = append(append(rows, helpRow{"", "Plugins"}), actions...)
+	}
+	return rows
+}
+
+// openHelp shows the help overlay.
+func (m Model) openHelp() (tea.Model, tea.Cmd) {
+	m.showHelp = true
+	m.helpScroll = 0
+	return m, nil
+}
+
+// handleHelpKey scrolls the help overlay; any key but those and quit
+// closes it.
+func (m Model) handleHelpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.action(msg) {
+	case ActQuit:
+		return m, tea.Quit
+	case ActUp, ActScrollUp:
+		m.helpScroll = max(0, m.helpScroll-1)
+	case ActDown, ActScrollDown:
+		table, fit := m.helpTable()
+		m.helpScroll = min(m.helpScroll+1, max(0, len(table)-fit))
+	default:
+		m.showHelp = false
+	}
+	return m, nil
+}
+
+// helpTable lays the help rows out in as many columns as fit the width,
+// and says how many of its lines fit the height.
+func (m Model) helpTable() (table []string, fit int) {
+	rows := m.helpRows()
+	keyW, lineW := 0, 0
+	for _, r := range rows {
+		keyW = max(keyW, lipgloss.Width(r.keys))
+	}
+	lines := make([]string, len(rows))
+	for i, r := range rows {
+		lines[i] = r.text
+		if r.keys != "" {
+			lines[i] = fmt.Sprintf("%-*s  %s", keyW, r.keys, r.text)
+		}
+		lineW = max(lineW, lipgloss.Width(lines[i]))
+	}
+
+	const gap = 3
+	innerW := max(20, m.width-8) // modal border and padding
+	fit = max(3, m.height-9)     // header, modal border and padding, title, hint
+	lineW = min(lineW, innerW)
+	cols := max(1, min((innerW+gap)/(lineW+gap), (len(lines)+fit-1)/fit))
+	perCol := (len(lines) + cols - 1) / cols
+
+	table = make([]string, perCol)
+	for i, line := range lines {
+		cell := truncate(line, lineW)
+		pad := lineW - lipgloss.Width(cell) + gap
+		if rows[i].keys == "" {
+			cell = groupHeaderStyle.Render(cell)
+		}
+		if i/perCol < cols-1 {
+			cell += strings.Repeat(" ", pad)
+		}
+		table[i%perCol] += cell
+	}
+	return table, fit
+}
+
+// renderHelp shows the help overlay.
+func (m Model) renderHelp() string {
+	table, fit := m.helpTable()
+	scroll := min(m.helpScroll, max(0, len(table)-fit))
+	shown := table[scroll:min(len(table), scroll+fit)]
+	hint := "[esc] close"
+	if len(table) > fit {
+		hint = fmt.Sprintf("[%s] scroll  %s", m.Keys.label(ActUp, ActDown), hint)
+	}
+	return overlayStyle.Render(groupHeaderStyle.Render("Keys") + "\n\n" +
+		normalStyle.Render(strings.Join(shown, "\n")) + "\n\n" + helpStyle.Render(hint))
+}
@@ -0,0 +1,192 @@
+package navui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lineInput is a minimal text prompt shown in the footer. alt+enter inserts
+// a newline for multi-line input.
+type lineInput struct {
+	prompt string
+	value  []rune
+}
+
+// update applies a key to the input. It reports whether the input was
+// submitted (enter) or cancelled (esc).
+func (in *lineInput) update(msg tea.KeyMsg) (submitted, cancelled bool) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		if msg.Alt {
+			in.value = append(in.value, '\n')
+			return false, false
+		}
+		return true, false
+	case tea.KeyEsc, tea.KeyCtrlC:
+		return false, true
+	case tea.KeyBackspace:
+		if len(in.value) > 0 {
+			in.value = in.value[:len(in.value)-1]
+		}
+	case tea.KeyCtrlU:
+		in.value = nil
+	case tea.KeySpace:
+		in.value = append(in.value, ' ')
+	case tea.KeyRunes:
+		in.value = append(in.value, msg.Runes...)
+	}
+	return false, false
+}
+
+// text returns the current input.
+func (in lineInput) text() string {
+	return string(in.value)
+}
+
+func (in lineInput) view() string {
+	shown := strings.ReplaceAll(in.text(), "\n", "⏎")
+	return confirmStyle.Render(in.prompt) + " " + normalStyle.Render(shown+"█")
+}
+
+// viewMode is the mode whose screen is showing; a footer input keeps the
+// screen of the mode it was opened from.
+func (m Model) viewMode() uiMode {
+	if m.mode == modeInput {
+		return m.inputBack
+	}
+	return m.mode
+}
+
+// openInput shows a footer prompt; the submitted text is handled by
+// submitInput according to kind.
+func (m Model) openInput(kind inputKind, prompt string) Model {
+	m.input = lineInput{prompt: prompt}
+	m.inputFor = kind
+	m.inputBack = m.mode
+	m.mode = modeInput
+	return m
+}
+
+func (m Model) handleInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	submitted, cancelled := m.input.update(msg)
+	switch {
+	case cancelled:
+		m.mode = m.inputBack
+		return m, nil
+	case submitted:
+		m.mode = m.inputBack
+		return m.submitInput(m.input.text())
+	}
+	return m, nil
+}
+
+// parseNewSession parses "<name> [dir] [-- command]" as typed into the new
+// session prompt. A leading ~ in dir is the home directory.
+func parseNewSession(text string) (tmuxclient.NewSessionOptions, error) {
+	var opts tmuxclient.NewSessionOptions
+	text, command, _ := strings.Cut(text, " -- ")
+	opts.Command = strings.TrimSpace(command)
+	fields := strings.Fields(text)
+	switch {
+	case len(fields) == 0:
+		return opts, fmt.Errorf("new session: no name given")
+	case len(fields) > 2:
+		return opts, fmt.Errorf("new session: expected a name and a directory, got %q", text)
+	}
+	if err := tmuxclient.CheckName(fields[0]); err != nil {
+		return opts, fmt.Errorf("new session: %w", err)
+	}
+	opts.Name = fields[0]
+	if len(fields) == 2 {
+		opts.Dir = fields[1]
+		if home, err := os.UserHomeDir(); err == nil && (opts.Dir == "~" || strings.HasPrefix(opts.Dir, "~/")) {
+			opts.Dir = filepath.Join(home, opts.Dir[1:])
+		}
+	}
+	return opts, nil
+}
+
+// submitInput acts on text entered in the footer prompt.
+func (m Model) submitInput(text string) (tea.Model, tea.Cmd) {
+	if strings.TrimSpace(text) == "" {
+		return m, nil
+	}
+	switch m.inputFor {
+	case inputAgent:
+		project, task, _ := strings.Cut(strings.TrimSpace(text), " ")
+		return m, m.ops.run("", func() tea.Msg {
+			p, err := agent.LookupProject(project)
+			if err != nil {
+				return actionDoneMsg{err: err}
+			}
+			name, err := agent.Spawn(agent.Spec{Project: p, Task: strings.TrimSpace(task)})
+			if err != nil {
+				return actionDoneMsg{err: err}
+			}
+			return actionDoneMsg{status: fmt.Sprintf("started %q", name), selectName: name}
+		})
+	case inputSession:
+		opts, err := parseNewSession(text)
+		if err != nil {
+			m.statusMsg = err.Error()
+			return m, nil
+		}
+		return m, m.ops.run(opts.Name, func() tea.Msg {
+			if tmuxclient.HasSession(context.Background(), opts.Name) {
+				return actionDoneMsg{err: fmt.Errorf("session %q already exists", opts.Name)}
+			}
+			if err := tmuxclient.NewSession(context.Background(), opts); err != nil {
+				return actionDoneMsg{err: err}
+			}
+			return actionDoneMsg{status: fmt.Sprintf("created %q", opts.Name), selectName: opts.Name}
+		})
+	}
+
+	if len(m.sessions) == 0 {
+		return m, nil
+	}
+	session := m.sessions[m.cursor].Name
+	switch m.inputFor {
+	case inputRename:
+		name := strings.TrimSpace(text)
+		if name == session {
+			return m, nil
+		}
+		if err := tmuxclient.CheckName(name); err != nil {
+			m.statusMsg = "rename: " + err.Error()
+			return m, nil
+		}
+		return m, m.ops.run(session, func() tea.Msg {
+			if err := tmuxclient.RenameSession(context.Background(), session, name); err != nil {
+				return actionDoneMsg{err: err}
+			}
+			_ = eventlog.Append(name, "rename", "renamed from %s", session)
+			return actionDoneMsg{status: fmt.Sprintf("renamed %q to %q", session, name), selectName: name}
+		})
+	case inputPrompt:
+		return m, m.ops.run(session, func() tea.Msg {
+			if err := agent.SendPrompt(session, text); err != nil {
+				return actionDoneMsg{err: err}
+			}
+			return actionDoneMsg{status: fmt.Sprintf("sent prompt to %q", session)}
+		})
+	case inputBroadcast:
+		return m.broadcastMarked(text)
+	case inputSend:
+		return m, m.ops.run(session, func() tea.Msg {
+			if err := tmuxclient.SendLine(context.Background(), tmuxclient.ActivePaneTarget(session), text); err != nil {
+				return actionDoneMsg{err: err}
+			}
+			return actionDoneMsg{status: fmt.Sprintf("sent %q to %q", text, session)}
+		})
+	}
+	return m, nil
+}
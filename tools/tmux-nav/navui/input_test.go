@@ -0,0 +1,36 @@
+package navui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+func TestParseNewSession(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	tests := []struct {
+		text    string
+		want    tmuxclient.NewSessionOptions
+		wantErr bool
+	}{
+		{text: "scratch", want: tmuxclient.NewSessionOptions{Name: "scratch"}},
+		{text: " api  ~/code/api ", want: tmuxclient.NewSessionOptions{Name: "api", Dir: filepath.Join(home, "code/api")}},
+		{text: "logs /var/log -- tail -f syslog", want: tmuxclient.NewSessionOptions{Name: "logs", Dir: "/var/log", Command: "tail -f syslog"}},
+		{text: "top -- htop", want: tmuxclient.NewSessionOptions{Name: "top", Command: "htop"}},
+		{text: "", wantErr: true},
+		{text: "v1.2", wantErr: true},
+		{text: "a b c", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseNewSession(tt.text)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseNewSession(%q) error = %v", tt.text, err)
+			continue
+		}
+		if !tt.wantErr && (got.Name != tt.want.Name || got.Dir != tt.want.Dir || got.Command != tt.want.Command) {
+			t.Errorf("parseNewSession(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package navui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Action names a command of the navigator that can be bound to keys.
+type Action string
+
+// The navigator's actions. Views share them where they mean the same thing:
+// up and down move whichever cursor is showing, back leaves the view.
+const (
+	ActUp              Action = "up"
+	ActDown            Action = "down"
+	ActDrillIn         Action = "drill-in"
+	ActDrillOut        Action = "drill-out"
+	ActAttach          Action = "attach"
+	ActAttachOnly      Action = "attach-only"
+	ActFilter          Action = "filter"
+	ActAttentionFilter Action = "attention-filter"
+	ActMark            Action = "mark"
+	ActPreview         Action = "preview"
+	ActFollow          Action = "follow"
+	ActPreviewTab      Action = "preview-tab"
+	ActScrollUp        Action = "scroll-up"
+	ActScrollDown      Action = "scroll-down"
+	ActPrompt          Action = "prompt"
+	ActSend            Action = "send"
+	ActBroadcast       Action = "broadcast"
+	ActInterrupt       Action = "interrupt"
+	ActApprove         Action = "approve"
+	ActDeny            Action = "deny"
+	ActRename          Action = "rename"
+	ActNewSession      Action = "new-session"
+	ActNewAgent        Action = "new-agent"
+	ActKill            Action = "kill"
+	ActArchive         Action = "archive"
+	ActOpenDir         Action = "open-dir"
+	ActAttention       Action = "attention"
+	ActGrid            Action = "grid"
+	ActSort            Action = "sort"
+	ActGroup           Action = "group"
+	ActFold            Action = "fold"
+	ActLog             Action = "log"
+	ActRefresh         Action = "refresh"
+	ActQuit            Action = "quit"
+	ActBack            Action = "back"
+	ActHelp            Action = "help"
+	ActShrinkList      Action = "shrink-list"
+	ActGrowList        Action = "grow-list"
+	ActPrune           Action = "prune"
+	ActStrategy        Action = "strategy"
+	ActPlainAttach     Action = "plain-attach"
+	ActCopyCommand     Action = "copy-command"
+)
+
+// Binding is an action with the keys that trigger it, named as in the
+// [keys] section of config.toml.
+type Binding struct {
+	Action Action
+	Keys   []string
+	Help   string
+}
+
+// defaultBindings are the built-in bindings, in the order `tmux-nav keys`
+// lists them.
+var defaultBindings = []Binding{
+	{ActUp, []string{"up", "k"}, "move up"},
+	{ActDown, []string{"down", "j"}, "move down"},
+	{ActDrillIn, []string{"right", "l"}, "windows, then panes; right in grid"},
+	{ActDrillOut, []string{"left", "h"}, "out of panes/windows; left in grid"},
+	{ActAttach, []string{"enter", "a"}, "attach"},
+	{ActAttachOnly, []string{"A"}, "attach, detaching other clients"},
+	{ActFilter, []string{"/"}, "filter sessions by name"},
+	{ActAttentionFilter, []string{"f"}, "show only sessions needing attention"},
+	{ActMark, []string{"space"}, "mark a session for a bulk kill or broadcast"},
+	{ActPreview, []string{"p"}, "reload the preview"},
+	{ActFollow, []string{"F"}, "follow a session's output live"},
+	{ActPreviewTab, []string{"tab"}, "cycle the preview tabs"},
+	{ActScrollUp, []string{"pgup", "ctrl+u"}, "scroll the preview back"},
+	{ActScrollDown, []string{"pgdown", "ctrl+d"}, "scroll the preview forward"},
+	{ActPrompt, []string{"i"}, "send an agent a prompt"},
+	{ActSend, []string{":"}, "type a line into a session"},
+	{ActBroadcast, []string{"B"}, "send the marked agents a prompt"},
+	{ActInterrupt, []string{"I"}, "interrupt an agent"},
+	{ActApprove, []string{"y"}, "approve an agent's request"},
+	{ActDeny, []string{"n", "N"}, "deny an agent's request (while it waits)"},
+	{ActRename, []string{"R"}, "rename a session"},
+	{ActNewSession, []string{"n"}, "create a session"},
+	{ActNewAgent, []string{"N"}, "spawn an agent"},
+	{ActKill, []string{"d", "x"}, "kill a session (or the marked ones)"},
+	{ActArchive, []string{"X"}, "archive and kill an agent"},
+	{ActPrune, []string{"P"}, "kill detached sessions left idle"},
+	{ActOpenDir, []string{"o"}, "open a session's directory"},
+	{ActAttention, []string{"!"}, "queue of agents needing attention"},
+	{ActGrid, []string{"g"}, "agent dashboard"},
+	{ActSort, []string{"s"}, "cycle the sort order"},
+	{ActGroup, []string{"G"}, "group sessions by repository"},
+	{ActStrategy, []string{"S"}, "cycle the attach strategy"},
+	{ActPlainAttach, []string{"p"}, "retry a failed attach as plain attach"},
+	{ActCopyCommand, []string{"c"}, "copy a failed attach's command"},
+	{ActFold, []string{"z"}, "fold or unfold a group"},
+	{ActLog, []string{"L"}, "event log"},
+	{ActShrinkList, []string{"<"}, "give the preview more room"},
+	{ActGrowList, []string{">"}, "give the list more room"},
+	{ActRefresh, []string{"r"}, "reload"},
+	{ActQuit, []string{"q"}, "quit (ctrl+c always does)"},
+	{ActBack, []string{"esc"}, "clear marks/filter, leave view, quit"},
+	{ActHelp, []string{"?"}, "this help"},
+}
+
+// scopedActions are the actions whose keys apply only in one place, and
+// elsewhere keep any other action bound to them: n denies a waiting agent
+// and creates a session anywhere else, p retries a failed attach as plain
+// attach and reloads the preview anywhere else.
+var scopedActions = []Action{ActDeny, ActPlainAttach, ActCopyCommand}
+
+// Keymap maps keys to the actions they trigger. The zero value uses the
+// default bindings.
+type Keymap struct {
+	bindings []Binding
+	byKey    map[string]Action
+	scoped   map[string]Action // keys of scopedActions
+}
+
+var defaultKeymap, _ = NewKeymap(nil)
+
+// NewKeymap returns the default bindings with the given actions rebound,
+// e.g. {"down": {"n", "down"}, "deny": {"D"}}. A rebound action takes its
+// keys from any default action bound to them; an empty list unbinds an
+// action. Keys are named as bubbletea names them ("ctrl+d", "pgup", "enter",
+// "esc"), with "space" for the space bar. Deny's keys may be shared with
+// another action, which they trigger unless an agent is waiting.
+func NewKeymap(rebind map[string][]string) (Keymap, error) {
+	km := Keymap{byKey: map[string]Action{}, scoped: map[string]Action{}}
+	rebound := map[string]Action{}
+	for _, a := range slices.Sorted(maps.Keys(rebind)) {
+		if !slices.ContainsFunc(defaultBindings, func(b Binding) bool { return string(b.Action) == a }) {
+			return Keymap{}, fmt.Errorf("keys: unknown action %q", a)
+		}
+		for _, k := range rebind[a] {
+			if k == "ctrl+c" {
+				return Keymap{}, fmt.Errorf("keys: ctrl+c always quits and can't be bound to %s", a)
+			}
+			if other, ok := rebound[keyName(k)]; ok {
+				return Keymap{}, fmt.Errorf("keys: %q is bound to both %s and %s", k, other, a)
+			}
+			rebound[keyName(k)] = Action(a)
+			km.keys(Action(a))[keyName(k)] = Action(a)
+		}
+	}
+	for _, b := range defaultBindings {
+		keys, ok := rebind[string(b.Action)]
+		if !ok {
+			keys = nil
+			for _, k := range b.Keys {
+				_, taken := km.keys(b.Action)[keyName(k)]
+				if _, claimed := rebound[keyName(k)]; !taken && !claimed {
+					km.keys(b.Action)[keyName(k)] = b.Action
+					keys = append(keys, k)
+				}
+			}
+		}
+		km.bindings = append(km.bindings, Binding{b.Action, keys, b.Help})
+	}
+	return km, nil
+}
+
+// keys returns the map holding a's keys.
+func (km Keymap) keys(a Action) map[string]Action {
+	if slices.Contains(scopedActions, a) {
+		return km.scoped
+	}
+	return km.byKey
+}
+
+// Bindings returns every action with its keys, in help order.
+func (km Keymap) Bindings() []Binding {
+	if km.byKey == nil {
+		return defaultKeymap.bindings
+	}
+	return km.bindings
+}
+
+// action returns the action bound to key, or "" if there is none. Keys
+// of scopedActions count only for those in scope.
+func (km Keymap) action(key string, scope ...Action) Action {
+	if km.byKey == nil {
+		km = defaultKeymap
+	}
+	if a, ok := km.scoped[key]; ok && slices.Contains(scope, a) {
+		return a
+	}
+	return km.byKey[key]
+}
+
+// keysFor returns the keys bound to a.
+func (km Keymap) keysFor(a Action) []string {
+	for _, b := range km.Bindings() {
+		if b.Action == a {
+			return b.Keys
+		}
+	}
+	return nil
+}
+
+// label shows the keys bound to actions for the footer: all of an action's
+// keys joined by "/", or for several actions the first key of each ("↑/↓").
+func (km Keymap) label(actions ...Action) string {
+	var labels []string
+	for _, a := range actions {
+		keys := km.keysFor(a)
+		if len(actions) > 1 && len(keys) > 1 {
+			keys = keys[:1]
+		}
+		for _, k := range keys {
+			labels = append(labels, keyLabel(keyName(k)))
+		}
+	}
+	return strings.Join(labels, "/")
+}
+
+// help renders footer entries, "[keys] what", leaving out those whose
+// actions are all unbound.
+func (km Keymap) help(entries ...helpEntry) string {
+	var out []string
+	for _, e := range entries {
+		if l := km.label(e.actions...); l != "" {
+			out = append(out, "["+l+"] "+e.text)
+		}
+	}
+	return strings.Join(out, "  ")
+}
+
+// helpEntry is one footer entry: the actions it describes and what they do.
+type helpEntry struct {
+	text    string
+	actions []Action
+}
+
+func entry(text string, actions ...Action) helpEntry {
+	return helpEntry{text, actions}
+}
+
+// action returns the action the key pressed is bound to. ctrl+c always
+// quits, so no binding can leave the navigator without a way out.
+func (m Model) action(msg tea.KeyMsg) Action {
+	if msg.Type == tea.KeyCtrlC {
+		return ActQuit
+	}
+	var scope []Action
+	switch {
+	case m.mode == modeAttachFailed:
+		scope = []Action{ActPlainAttach, ActCopyCommand}
+	case m.answering():
+		scope = []Action{ActDeny}
+	}
+	return m.Keys.action(msg.String(), scope...)
+}
+
+// answering reports whether the answer keys apply: the selected agent
+// waits for an answer in a view that can give one.
+func (m Model) answering() bool {
+	return (m.mode == modeList || m.mode == modeAttention || m.mode == modeGrid) && m.selectedNeedsAttention()
+}
+
+// keyName turns a configured key name into bubbletea's.
+func keyName(k string) string {
+	if k == "space" {
+		return " "
+	}
+	return k
+}
+
+// keyLabels are shorter or visible names for some keys.
+var keyLabels = map[string]string{
+	"up": "↑", "down": "↓", "left": "←", "right": "→", " ": "space", "pgdown": "pgdn",
+}
+
+// keyLabel names a key for display.
+func keyLabel(k string) string {
+	if l, ok := keyLabels[k]; ok {
+		return l
+	}
+	return k
+}
@@ -0,0 +1,83 @@
+package navui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRebindTakesKeyFromDefault(t *testing.T) {
+	km, err := NewKeymap(map[string][]string{"down": {"n", "down"}, "up": {"e", "up"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := km.action("n", ActDeny); got != ActDown {
+		t.Errorf("n triggers %q, want down", got)
+	}
+	if got := km.action("j"); got != "" {
+		t.Errorf("j still triggers %q after down was rebound", got)
+	}
+	if keys := km.keysFor(ActNewSession); len(keys) != 0 {
+		t.Errorf("new-session keeps %v, want it to lose n", keys)
+	}
+	if keys := km.keysFor(ActDeny); !slices.Equal(keys, []string{"N"}) {
+		t.Errorf("deny = %v, want it to lose n", keys)
+	}
+	if keys := km.keysFor(ActKill); !slices.Equal(keys, []string{"d", "x"}) {
+		t.Errorf("kill = %v, want the defaults", keys)
+	}
+
+	m := fixture(80, 24)
+	m.Keys = km
+	if m = keys(m, "n"); m.cursor != 1 {
+		t.Errorf("cursor = %d after n, want 1", m.cursor)
+	}
+	if m = keys(m, "e"); m.cursor != 0 {
+		t.Errorf("cursor = %d after e, want 0", m.cursor)
+	}
+}
+
+func TestRebindRejectsBadBindings(t *testing.T) {
+	for _, rebind := range []map[string][]string{
+		{"jump": {"J"}},
+		{"down": {"n"}, "up": {"n"}},
+		{"quit": {"ctrl+c"}},
+	} {
+		if _, err := NewKeymap(rebind); err == nil {
+			t.Errorf("NewKeymap(%v) succeeded", rebind)
+		}
+	}
+}
+
+func TestReboundKeysShown(t *testing.T) {
+	km, err := NewKeymap(map[string][]string{"kill": {"K"}, "mark": {"space", "m"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := fixture(80, 24)
+	m.Keys = km
+	if footer := m.footerKeys(); !strings.Contains(footer, "[K] kill") {
+		t.Errorf("footer lacks [K] kill:\n%s", footer)
+	}
+	if help := keys(m, "?").renderHelp(); !strings.Contains(help, "space/m") {
+		t.Errorf("help overlay lacks space/m:\n%s", help)
+	}
+}
+
+func TestMacrosCheckedAgainstEffectiveKeys(t *testing.T) {
+	km, err := NewKeymap(map[string][]string{"approve": {"x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := km.CheckMacros([]Macro{{Name: "yes", Key: "y", Text: "yes"}}); err != nil {
+		t.Errorf("y is free once approve moves to x: %v", err)
+	}
+	for _, key := range []string{"x", "n", "ctrl+c"} {
+		if err := km.CheckMacros([]Macro{{Name: "m", Key: key, Text: "m"}}); err == nil {
+			t.Errorf("macro on %q accepted; the attention view uses it", key)
+		}
+	}
+	if err := defaultKeymap.CheckMacros(DefaultMacros); err != nil {
+		t.Errorf("default macros: %v", err)
+	}
+}
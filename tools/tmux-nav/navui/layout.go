@@ -0,0 +1,86 @@
+package navui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultStackBelow is the terminal width under which the preview moves
+// below the list, where side by side both get too narrow to read.
+const defaultStackBelow = 100
+
+// Bounds and step of the list's share of the screen, in percent.
+const (
+	defaultSplit = 50
+	minSplit     = 20
+	maxSplit     = 80
+	splitStep    = 5
+)
+
+// stacked reports whether the preview sits below the list rather than
+// beside it.
+func (m Model) stacked() bool {
+	return m.width < m.StackBelow
+}
+
+// split is the list's share of the screen in percent: of the width side
+// by side, of the height stacked.
+func (m Model) split() int {
+	if m.Split == 0 {
+		return defaultSplit
+	}
+	return max(minSplit, min(m.Split, maxSplit))
+}
+
+// resizeSplit grows (positive) or shrinks the list's share of the screen.
+func (m Model) resizeSplit(dir int) Model {
+	m.Split = max(minSplit, min(m.split()+dir*splitStep, maxSplit))
+	m.statusMsg = fmt.Sprintf("list %d%%, preview %d%%", m.Split, 100-m.Split)
+	return m
+}
+
+// splitWidths returns the content widths of the list and the preview.
+func (m Model) splitWidths() (listW, previewW int) {
+	if m.stacked() {
+		return m.width - 2, m.width - 2
+	}
+	listW = m.width*m.split()/100 - 2
+	return listW, m.width - listW - 4
+}
+
+// stackedListRows is how many lines the list shows when stacked; the
+// preview gets the rest.
+func (m Model) stackedListRows() int {
+	return max(3, (m.height-8)*m.split()/100)
+}
+
+// previewRows is how many lines of output the preview pane shows.
+func (m Model) previewRows() int {
+	if m.stacked() {
+		// The list's lines and its box's border come out of the preview.
+		return max(1, m.height-8-m.stackedListRows())
+	}
+	return max(1, m.height-8)
+}
+
+// layoutBody puts the list and the preview side by side or, on narrow
+// terminals, one above the other.
+func (m Model) layoutBody(listContent, previewContent string) string {
+	listW, previewW := m.splitWidths()
+	if !m.stacked() {
+		left := listBorderStyle.Width(listW).Render(listContent)
+		right := previewBorderStyle.Width(previewW).Render(previewContent)
+		return lipgloss.JoinHorizontal(lipgloss.Top, left, " ", right)
+	}
+	// Clip the list box after rendering, as long rows wrap; keep the
+	// bottom border.
+	rows := m.stackedListRows()
+	top := listBorderStyle.Width(listW).Height(rows).Render(strings.TrimSuffix(listContent, "\n"))
+	if lines := strings.Split(top, "\n"); len(lines) > rows+2 {
+		top = strings.Join(append(lines[:rows+1], lines[len(lines)-1]), "\n")
+	}
+	bottom := previewBorderStyle.Width(previewW).Render(previewContent)
+	return lipgloss.JoinVertical(lipgloss.Left, top, bottom)
+}
@@ -0,0 +1,72 @@
+package navui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/trace"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// logLoadedMsg carries the tail of the event log.
+type logLoadedMsg struct {
+	entries []eventlog.Entry
+	err     error
+}
+
+// loadLog reads the most recent events for the log view.
+func loadLog() tea.Msg {
+	entries, err := eventlog.Tail(200)
+	return logLoadedMsg{entries, err}
+}
+
+func (m Model) handleLogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.action(msg) {
+	case ActQuit:
+		return m, tea.Quit
+	case ActBack, ActLog:
+		m.mode = modeList
+	case ActRefresh:
+		return m, loadLog
+	}
+	return m, nil
+}
+
+// traceLines is how many of the slowest traced calls the log view lists.
+const traceLines = 5
+
+// renderLog shows the newest events that fit, newest last, below the
+// slowest traced calls when tracing is on.
+func (m Model) renderLog(w int) string {
+	var sb strings.Builder
+	rows := m.height - 7
+	if trace.Enabled() {
+		stats := trace.Summary()
+		if len(stats) > traceLines {
+			stats = stats[:traceLines]
+		}
+		sb.WriteString(titleStyle.Render("Trace (by total time)") + "\n")
+		for _, s := range stats {
+			sb.WriteString(normalStyle.Render(truncate(s.String(), w)) + "\n")
+		}
+		sb.WriteString("\n")
+		rows -= len(stats) + 2
+	}
+	sb.WriteString(titleStyle.Render("Event log") + "\n")
+	if m.logErr != nil {
+		return sb.String() + errorStyle.Render("Error: "+m.logErr.Error())
+	}
+	if len(m.log) == 0 {
+		return sb.String() + normalStyle.Render("(no events)")
+	}
+	entries := m.log
+	if rows > 0 && len(entries) > rows {
+		entries = entries[len(entries)-rows:]
+	}
+	for _, e := range entries {
+		line := fmt.Sprintf("%s  %-8s %-24s %s", e.Time.Format("01-02 15:04:05"), e.Kind, e.Session, e.Message)
+		sb.WriteString(normalStyle.Render(truncate(line, w)) + "\n")
+	}
+	return sb.String()
+}
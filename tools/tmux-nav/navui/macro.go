@@ -0,0 +1,89 @@
+package navui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Macro is a canned reply bound to a key in the needs-attention view.
+type Macro struct {
+	Name string
+	Key  string
+	Keys []string // tmux keys sent first, e.g. "Escape"
+	Text string   // prompt submitted after the keys
+}
+
+// DefaultMacros are used when none are configured.
+var DefaultMacros = []Macro{
+	{Name: "continue", Key: "c", Text: "continue"},
+	{Name: "yes to all", Key: "Y", Keys: []string{"2"}},
+	{Name: "stop and summarize", Key: "s", Keys: []string{"Escape"},
+		Text: "Stop here and summarize what you've done and what's left."},
+}
+
+// attentionActions are the actions the needs-attention view handles
+// before trying macros.
+var attentionActions = []Action{ActQuit, ActBack, ActAttention, ActUp, ActDown, ActAttach,
+	ActRefresh, ActApprove, ActDeny, ActInterrupt, ActPrompt, ActHelp}
+
+// CheckMacros reports a macro bound to a key that the needs-attention view
+// already uses under km, where the macro could never be triggered.
+func (km Keymap) CheckMacros(macros []Macro) error {
+	for _, mac := range macros {
+		if a := km.action(keyName(mac.Key), ActDeny); slices.Contains(attentionActions, a) || mac.Key == "ctrl+c" {
+			if a == "" {
+				a = ActQuit
+			}
+			return fmt.Errorf("macro %q: key %q is bound to %s in the attention view", mac.Name, mac.Key, a)
+		}
+	}
+	return nil
+}
+
+// macros returns the configured macros, or the defaults.
+func (m Model) macros() []Macro {
+	if m.Macros != nil {
+		return m.Macros
+	}
+	return DefaultMacros
+}
+
+// macroFor returns the macro bound to key.
+func (m Model) macroFor(key string) (Macro, bool) {
+	for _, mac := range m.macros() {
+		if mac.Key == key {
+			return mac, true
+		}
+	}
+	return Macro{}, false
+}
+
+// sendMacro sends the macro to the highlighted agent in the background.
+func (m Model) sendMacro(mac Macro) tea.Cmd {
+	if len(m.sessions) == 0 {
+		return nil
+	}
+	session := m.sessions[m.cursor].Name
+	if _, ok := m.states[session]; !ok {
+		return nil
+	}
+	return m.ops.run(session, func() tea.Msg {
+		if err := agent.SendReply(session, mac.Keys, mac.Text); err != nil {
+			return actionDoneMsg{err: err}
+		}
+		return actionDoneMsg{status: fmt.Sprintf("sent %q to %q", mac.Name, session)}
+	})
+}
+
+// macroHelp lists the macro bindings for the footer.
+func (m Model) macroHelp() string {
+	var parts []string
+	for _, mac := range m.macros() {
+		parts = append(parts, fmt.Sprintf("[%s] %s", mac.Key, mac.Name))
+	}
+	return strings.Join(parts, "  ")
+}
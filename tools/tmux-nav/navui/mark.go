@@ -0,0 +1,147 @@
+package navui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleMark marks or unmarks the selected session for a bulk kill or
+// broadcast and moves on to the next row.
+func (m Model) toggleMark() (tea.Model, tea.Cmd) {
+	if len(m.sessions) == 0 {
+		return m, nil
+	}
+	name := m.sessions[m.cursor].Name
+	if m.marked[name] {
+		delete(m.marked, name)
+	} else {
+		if m.marked == nil {
+			m.marked = map[string]bool{}
+		}
+		m.marked[name] = true
+	}
+	if m.moveCursor(1) {
+		return m, m.loadPreview()
+	}
+	return m, nil
+}
+
+// markStale marks the detached sessions idle long enough to prune, in
+// place of any marks, and asks to kill them.
+func (m Model) markStale() (tea.Model, tea.Cmd) {
+	stale := agent.Prunable(m.sessions, m.PruneAfter, m.PruneExclude, time.Now())
+	if len(stale) == 0 {
+		m.statusMsg = "no detached sessions idle long enough to prune"
+		return m, nil
+	}
+	m.marked = map[string]bool{}
+	for _, s := range stale {
+		m.marked[s.Name] = true
+	}
+	m.mode = modeConfirmKill
+	m.archiveKill = false
+	m.statusMsg = ""
+	return m, nil
+}
+
+// markedSessions returns the marked sessions in list order.
+func (m Model) markedSessions() []tmuxclient.Session {
+	var marked []tmuxclient.Session
+	for _, s := range m.sessions {
+		if m.marked[s.Name] {
+			marked = append(marked, s)
+		}
+	}
+	return marked
+}
+
+// pruneMarks forgets the marks of sessions that no longer exist.
+func (m *Model) pruneMarks() {
+	for name := range m.marked {
+		if indexOf(m.sessions, name) < 0 {
+			delete(m.marked, name)
+		}
+	}
+}
+
+// killMarked kills every marked session in one operation, once those
+// already dispatched on them end, and reports the ones that failed.
+func (m Model) killMarked() (tea.Model, tea.Cmd) {
+	targets := m.markedSessions()
+	names := make([]string, len(targets))
+	for i, s := range targets {
+		names[i] = s.Name
+		m.ops.cancel(s.Name)
+	}
+	m.marked = nil
+	m.statusMsg = fmt.Sprintf("killing %d sessions…", len(targets))
+	return m, m.ops.runOn(names, func() tea.Msg {
+		var failed []string
+		for _, s := range targets {
+			if err := agent.Remove(s, false, false); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", s.Name, err))
+			}
+		}
+		if len(failed) > 0 {
+			return actionDoneMsg{err: fmt.Errorf("killed %d of %d sessions; failed: %s",
+				len(targets)-len(failed), len(targets), strings.Join(failed, "; "))}
+		}
+		return actionDoneMsg{status: fmt.Sprintf("killed %d sessions", len(targets))}
+	})
+}
+
+// broadcastMarked sends text as a prompt to every marked session in one
+// operation and reports the ones that failed.
+func (m Model) broadcastMarked(text string) (tea.Model, tea.Cmd) {
+	targets := m.markedSessions()
+	names := make([]string, len(targets))
+	for i, s := range targets {
+		names[i] = s.Name
+	}
+	m.marked = nil
+	m.statusMsg = fmt.Sprintf("sending to %d sessions…", len(names))
+	return m, m.ops.runOn(names, func() tea.Msg {
+		var failed []string
+		for _, name := range names {
+			if err := agent.SendPrompt(name, text); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+			}
+		}
+		if len(failed) > 0 {
+			return actionDoneMsg{err: fmt.Errorf("sent to %d of %d sessions; failed: %s",
+				len(names)-len(failed), len(names), strings.Join(failed, "; "))}
+		}
+		return actionDoneMsg{status: fmt.Sprintf("sent prompt to %d sessions", len(names))}
+	})
+}
+
+// confirmKillMarked asks to confirm a bulk kill, naming every session.
+func (m Model) confirmKillMarked() string {
+	marked := m.markedSessions()
+	var names []string
+	worktrees := 0
+	for _, s := range marked {
+		names = append(names, s.Name)
+		if s.Worktree != "" {
+			worktrees++
+		}
+	}
+	prompt := fmt.Sprintf("Kill %d sessions: %s", len(marked), strings.Join(names, ", "))
+	if worktrees > 0 {
+		prompt += fmt.Sprintf(" and remove %d worktree(s)", worktrees)
+	}
+	return confirmStyle.Width(m.width).Render(prompt + "? [y/N]")
+}
+
+// markStatus describes the marks for the header.
+func (m Model) markStatus() string {
+	if len(m.marked) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  %d marked", len(m.marked))
+}
@@ -0,0 +1,154 @@
+package navui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// panesLoadedMsg carries the panes of the window drilled into.
+type panesLoadedMsg struct {
+	session string
+	window  int
+	panes   []tmuxclient.Pane
+	err     error
+}
+
+// loadPanes lists the panes of window index in session in the background.
+func (m Model) loadPanes(session string, index int) tea.Cmd {
+	return m.background(m.pool.Group("panes"), func() tea.Msg {
+		panes, err := tmuxclient.ListPanes(context.Background(), session, index)
+		return panesLoadedMsg{session, index, panes, err}
+	})
+}
+
+// openPanes descends into the highlighted window's pane list.
+func (m Model) openPanes() (tea.Model, tea.Cmd) {
+	w, ok := m.selectedWindow()
+	if !ok {
+		return m, nil
+	}
+	m.mode = modePanes
+	m.paneWindow = w.Index
+	m.panes, m.paneCursor = nil, 0
+	return m, m.loadPanes(m.winSession, w.Index)
+}
+
+// closePanes returns to the window list.
+func (m Model) closePanes() (tea.Model, tea.Cmd) {
+	m.mode = modeWindows
+	m.panes = nil
+	return m, tea.Batch(m.loadPreview(), m.loadWindows(m.winSession))
+}
+
+// storePanes takes in a loaded pane list, keeping the cursor on the same
+// pane or, the first time, putting it on the active one. A window that
+// has gone away returns to the window list.
+func (m Model) storePanes(msg panesLoadedMsg) (tea.Model, tea.Cmd) {
+	if m.mode != modePanes || msg.session != m.winSession || msg.window != m.paneWindow {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.statusMsg = msg.err.Error()
+		return m.closePanes()
+	}
+	cursor := -1
+	for i, p := range msg.panes {
+		if m.panes == nil && p.Active {
+			cursor = i
+		} else if sel, ok := m.selectedPane(); ok && p.Index == sel.Index {
+			cursor = i
+		}
+	}
+	m.panes = msg.panes
+	m.paneCursor = max(0, min(cursor, len(m.panes)-1))
+	return m, m.loadPreview()
+}
+
+// selectedPane returns the highlighted pane, if any.
+func (m Model) selectedPane() (tmuxclient.Pane, bool) {
+	if m.paneCursor >= len(m.panes) {
+		return tmuxclient.Pane{}, false
+	}
+	return m.panes[m.paneCursor], true
+}
+
+// paneKey identifies the cached preview of one pane.
+func paneKey(session string, window, pane int) string {
+	return windowKey(session, window) + "." + strconv.Itoa(pane)
+}
+
+// capturePane loads the preview of the highlighted pane.
+func (m Model) capturePane() tea.Cmd {
+	p, ok := m.selectedPane()
+	if !ok {
+		return nil
+	}
+	session, window := m.winSession, m.paneWindow
+	key, lines := paneKey(session, window, p.Index), m.previewLines()
+	return m.background(m.pool.Group("preview"), func() tea.Msg {
+		content, err := tmuxclient.CapturePane(context.Background(), session, window, p.Index, lines)
+		if err != nil {
+			return previewLoadedMsg{key, "(capture failed: " + err.Error() + ")"}
+		}
+		return previewLoadedMsg{key, content}
+	})
+}
+
+func (m Model) handlePanesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.action(msg) {
+	case ActQuit:
+		return m, tea.Quit
+	case ActBack, ActDrillOut:
+		return m.closePanes()
+	case ActUp:
+		if m.paneCursor > 0 {
+			m.paneCursor--
+			return m, m.loadPreview()
+		}
+	case ActDown:
+		if m.paneCursor < len(m.panes)-1 {
+			m.paneCursor++
+			return m, m.loadPreview()
+		}
+	case ActAttach:
+		if p, ok := m.selectedPane(); ok {
+			m.AttachSession = m.winSession
+			m.AttachWindow = strconv.Itoa(m.paneWindow)
+			m.AttachPane = strconv.Itoa(p.Index)
+			return m, tea.Quit
+		}
+	case ActRefresh:
+		tmuxclient.Invalidate(m.winSession)
+		return m, m.loadPanes(m.winSession, m.paneWindow)
+	}
+	return m, nil
+}
+
+// renderPanes lists the panes of the window drilled into: index, active
+// flag, current command and path.
+func (m Model) renderPanes(w int) string {
+	if m.panes == nil {
+		return normalStyle.Render("(loading panes…)")
+	}
+	var sb strings.Builder
+	title := fmt.Sprintf("Panes: %s:%d", m.winSession, m.paneWindow)
+	sb.WriteString(titleStyle.Render(truncate(title, w-4)) + "\n")
+	for i, p := range m.panes {
+		active := " "
+		if p.Active {
+			active = "*"
+		}
+		label := truncate(fmt.Sprintf("%2d%s %-10s %s", p.Index, active, p.Command, tildePath(p.Path)), w-4)
+		if i == m.paneCursor {
+			sb.WriteString(selectedStyle.Render("▶ "+label) + "\n")
+		} else {
+			sb.WriteString(normalStyle.Render("  "+label) + "\n")
+		}
+	}
+	return sb.String()
+}
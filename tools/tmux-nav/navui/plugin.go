@@ -0,0 +1,121 @@
+package navui
+
+import (
+	"context"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/plugin"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pluginRefresh is how often plugin columns are re-queried; each query
+// starts a process per plugin.
+const pluginRefresh = 10 * time.Second
+
+// tabPlugins is the first plugin preview tab; the rest follow in order.
+const tabPlugins previewTab = tabChanges + 1
+
+// pluginCellsMsg carries the plugin column cells of each session, in
+// plugin order.
+type pluginCellsMsg struct{ cells map[string][]string }
+
+// pluginTab is a preview tab contributed by a plugin.
+type pluginTab struct {
+	p    *plugin.Plugin
+	name string
+}
+
+// pluginTabs lists the plugins' preview tabs in the order tab cycles
+// through them.
+func (m Model) pluginTabs() []pluginTab {
+	var tabs []pluginTab
+	for _, p := range m.Plugins {
+		for _, name := range p.Tabs {
+			tabs = append(tabs, pluginTab{p, name})
+		}
+	}
+	return tabs
+}
+
+// nextTab returns the preview tab after the current one.
+func (m Model) nextTab() previewTab {
+	return (m.previewTab + 1) % (tabPlugins + previewTab(len(m.pluginTabs())))
+}
+
+// pluginSession describes s to plugins.
+func (m Model) pluginSession(s tmuxclient.Session) plugin.Session {
+	state := ""
+	if st, ok := m.states[s.Name]; ok {
+		state = st.String()
+	}
+	return plugin.SessionOf(s, state)
+}
+
+// loadPluginTab reports, as the preview under key, the plugin tab's
+// contents for s.
+func loadPluginTab(key string, t pluginTab, s plugin.Session) tea.Cmd {
+	return func() tea.Msg {
+		text, err := t.p.Preview(t.name, s)
+		if err != nil {
+			return previewLoadedMsg{key, "(" + err.Error() + ")"}
+		}
+		return previewLoadedMsg{key, text}
+	}
+}
+
+// refreshPluginCells returns a query of the plugin columns when any plugin
+// has one and the last query is stale. Plugins that fail show no cells.
+func (m *Model) refreshPluginCells() tea.Cmd {
+	var columns []*plugin.Plugin
+	for _, p := range m.Plugins {
+		if p.Column {
+			columns = append(columns, p)
+		}
+	}
+	if len(columns) == 0 || time.Since(m.pluginsFetched) < pluginRefresh {
+		return nil
+	}
+	m.pluginsFetched = time.Now()
+	sessions := make([]plugin.Session, len(m.sessions))
+	for i, s := range m.sessions {
+		sessions[i] = m.pluginSession(s)
+	}
+	return m.background(context.Background(), func() tea.Msg {
+		cells := make(map[string][]string)
+		for _, p := range columns {
+			got, err := p.Cells(sessions)
+			if err != nil {
+				continue
+			}
+			for name, cell := range got {
+				cells[name] = append(cells[name], cell)
+			}
+		}
+		return pluginCellsMsg{cells}
+	})
+}
+
+// pluginAction returns the command running the plugin action bound to key
+// on the selected session, if there is one. Keys the list already uses
+// never reach it.
+func (m Model) pluginAction(key string) tea.Cmd {
+	if len(m.sessions) == 0 {
+		return nil
+	}
+	s := m.pluginSession(m.sessions[m.cursor])
+	for _, p := range m.Plugins {
+		for _, a := range p.Actions {
+			if a.Key == key {
+				return m.ops.run(s.Name, func() tea.Msg {
+					status, err := p.Do(a.Name, s)
+					if status == "" && err == nil {
+						status = a.Name + ": done"
+					}
+					return actionDoneMsg{status: status, err: err}
+				})
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package navui
+
+import (
+	"context"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/gh"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prRefresh is how often PR status is re-queried; gh goes over the network.
+const prRefresh = 2 * time.Minute
+
+// prStatusMsg carries the pull request of each session on a branch.
+type prStatusMsg struct{ prs map[string]gh.PR }
+
+// loadPRs asks gh for the pull request of every session on a worktree
+// branch. Sessions whose lookup fails (no gh, not a GitHub repo) are left
+// out.
+func loadPRs(sessions []tmuxclient.Session) tea.Cmd {
+	return func() tea.Msg {
+		prs := make(map[string]gh.PR)
+		for _, s := range sessions {
+			dir := s.Repo
+			if dir == "" {
+				dir = s.Path
+			}
+			if s.Branch == "" || dir == "" {
+				continue
+			}
+			if pr, ok, err := gh.Lookup(dir, s.Branch); err == nil && ok {
+				prs[s.Name] = pr
+			}
+		}
+		return prStatusMsg{prs}
+	}
+}
+
+// refreshPRs returns a PR status query when the column is enabled and the
+// last one is stale.
+func (m *Model) refreshPRs() tea.Cmd {
+	if !m.PRStatus || time.Since(m.prFetched) < prRefresh {
+		return nil
+	}
+	m.prFetched = time.Now()
+	return m.background(context.Background(), loadPRs(m.sessions))
+}
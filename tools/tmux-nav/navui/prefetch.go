@@ -0,0 +1,210 @@
+package navui
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prefetchRadius is how many rows either side of the cursor have their
+// previews captured ahead of time, so j/k shows them instantly.
+const prefetchRadius = 1
+
+// defaultPreviewMaxBytes caps a cached preview unless PreviewMaxBytes says
+// otherwise, so a pane with megabytes of output can't balloon memory.
+const defaultPreviewMaxBytes = 256 << 10
+
+// defaultPreviewLines is how many lines a preview captures unless
+// PreviewLines says otherwise.
+const defaultPreviewLines = 40
+
+// previewLines is how many lines a preview captures.
+func (m Model) previewLines() int {
+	if m.PreviewLines > 0 {
+		return m.PreviewLines
+	}
+	return defaultPreviewLines
+}
+
+// previewLoadedMsg carries a captured preview for the session and tab
+// identified by key.
+type previewLoadedMsg struct{ key, content string }
+
+// previewKey identifies a cached preview.
+func previewKey(tab previewTab, session string) string {
+	switch {
+	case tab == tabChanges:
+		return "changes:" + session
+	case tab >= tabPlugins:
+		return "plugin" + strconv.Itoa(int(tab-tabPlugins)) + ":" + session
+	}
+	return "pane:" + session
+}
+
+// scrolledKey identifies a page of scrollback, back lines above the bottom
+// of the session's pane.
+func scrolledKey(session string, back int) string {
+	return previewKey(tabPane, session) + "@" + strconv.Itoa(back)
+}
+
+// selectedKey identifies the preview shown for the selected session.
+func (m Model) selectedKey() string {
+	name := m.sessions[m.cursor].Name
+	if p, ok := m.selectedPane(); ok && m.viewMode() == modePanes {
+		return paneKey(m.winSession, m.paneWindow, p.Index)
+	}
+	if w, ok := m.selectedWindow(); ok && m.viewMode() == modeWindows {
+		return windowKey(m.winSession, w.Index)
+	}
+	if m.previewTab == tabPane && m.previewScroll > 0 {
+		return scrolledKey(name, m.previewScroll)
+	}
+	return previewKey(m.previewTab, name)
+}
+
+// loadPreview captures the selected session's preview and prefetches its
+// neighbours'. Cached previews show at once and are refreshed in place.
+func (m Model) loadPreview() tea.Cmd {
+	if len(m.sessions) == 0 {
+		return nil
+	}
+	switch m.viewMode() {
+	case modeWindows:
+		return m.captureWindow()
+	case modePanes:
+		return m.capturePane()
+	}
+	batch := m.pool.Group("preview")
+	cmds := []tea.Cmd{m.background(batch, m.capturePreview(m.sessions[m.cursor], m.previewScroll))}
+	for _, i := range m.neighbours() {
+		cmds = append(cmds, m.background(batch, m.capturePreview(m.sessions[i], 0)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// capturePreview loads one session's preview for the current tab. Scrolled
+// back, it fetches just the page on screen rather than the whole history.
+func (m Model) capturePreview(s tmuxclient.Session, back int) tea.Cmd {
+	key := previewKey(m.previewTab, s.Name)
+	if m.previewTab == tabChanges {
+		return loadChanges(key, s)
+	}
+	if m.previewTab >= tabPlugins {
+		return loadPluginTab(key, m.pluginTabs()[m.previewTab-tabPlugins], m.pluginSession(s))
+	}
+	if back > 0 {
+		rows := m.previewRows()
+		end := max(s.PaneHeight, rows) - 1 - back
+		key := scrolledKey(s.Name, back)
+		return func() tea.Msg {
+			content, err := tmuxclient.CaptureRange(context.Background(), s.Name, end-rows+1, end)
+			if err != nil {
+				return previewLoadedMsg{key, "(capture failed: " + err.Error() + ")"}
+			}
+			return previewLoadedMsg{key, content}
+		}
+	}
+	lines := m.previewLines()
+	return func() tea.Msg {
+		content, err := tmuxclient.CapturePanes(context.Background(), s.Name, lines)
+		if err != nil {
+			return previewLoadedMsg{key, "(capture failed: " + err.Error() + ")"}
+		}
+		return previewLoadedMsg{key, content}
+	}
+}
+
+// neighbours returns the session indices within prefetchRadius rows of
+// the cursor, in navigation order.
+func (m Model) neighbours() []int {
+	rows := m.navRows()
+	pos := 0
+	for p, i := range rows {
+		if i == m.cursor {
+			pos = p
+		}
+	}
+	var idx []int
+	for p := max(0, pos-prefetchRadius); p <= min(len(rows)-1, pos+prefetchRadius); p++ {
+		if rows[p] != m.cursor {
+			idx = append(idx, rows[p])
+		}
+	}
+	return idx
+}
+
+// storePreview caches a loaded preview, capped to its tail, dropping those
+// of sessions that have moved out of prefetch range and pages of
+// scrollback no longer on screen.
+func (m *Model) storePreview(msg previewLoadedMsg) {
+	keep := map[string]bool{}
+	if len(m.sessions) > 0 {
+		for _, i := range append(m.neighbours(), m.cursor) {
+			for _, tab := range []previewTab{tabPane, tabChanges, m.previewTab} {
+				keep[previewKey(tab, m.sessions[i].Name)] = true
+			}
+		}
+		keep[m.selectedKey()] = true
+	}
+	if !keep[msg.key] {
+		return
+	}
+	next := make(map[string]string, len(keep))
+	for k, v := range m.previews {
+		if keep[k] {
+			next[k] = v
+		}
+	}
+	limit := m.PreviewMaxBytes
+	if limit <= 0 {
+		limit = defaultPreviewMaxBytes
+	}
+	next[msg.key] = capTail(msg.content, limit)
+	m.previews = next
+}
+
+// capTail returns at most the last n bytes of s, starting on a line
+// boundary.
+func capTail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[len(s)-n:]
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[i+1:]
+	}
+	return ""
+}
+
+// currentPreview returns the cached preview of the selected session. A page
+// of scrollback still loading shows the live pane meanwhile.
+func (m Model) currentPreview() string {
+	if len(m.sessions) == 0 {
+		return ""
+	}
+	if m.following() && m.viewMode() == modeList {
+		return followText(m.followBuf)
+	}
+	if p, ok := m.previews[m.selectedKey()]; ok {
+		return p
+	}
+	return m.previews[previewKey(m.previewTab, m.sessions[m.cursor].Name)]
+}
+
+// scrollPreview moves the pane preview back (positive) or forward through
+// the selected session's scrollback by half pages, and loads the new page.
+func (m Model) scrollPreview(dir int) (tea.Model, tea.Cmd) {
+	if len(m.sessions) == 0 || m.previewTab != tabPane {
+		return m, nil
+	}
+	back := m.previewScroll + dir*max(1, m.previewRows()/2)
+	back = max(0, min(back, m.sessions[m.cursor].History))
+	if back == m.previewScroll {
+		return m, nil
+	}
+	m.previewScroll = back
+	return m, m.background(m.pool.Group("preview"), m.capturePreview(m.sessions[m.cursor], back))
+}
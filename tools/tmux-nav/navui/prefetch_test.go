@@ -0,0 +1,76 @@
+package navui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+)
+
+func TestPrefetchedNeighbourShowsOnMove(t *testing.T) {
+	m := fixture(80, 24)
+	m = send(m, previewLoadedMsg{previewKey(tabPane, "agent-web-docs"), "docs output"})
+	m = keys(m, "j")
+	if got := m.currentPreview(); got != "docs output" {
+		t.Errorf("preview after j = %q, want the prefetched capture", got)
+	}
+	if !strings.Contains(m.View(), "docs output") {
+		t.Error("view doesn't show the prefetched capture")
+	}
+}
+
+func TestPreviewCacheDropsFarSessions(t *testing.T) {
+	m := fixture(80, 24)
+	m = send(m, previewLoadedMsg{previewKey(tabPane, "dotfiles"), "far away"})
+	if _, ok := m.previews[previewKey(tabPane, "dotfiles")]; ok {
+		t.Error("cached a preview three rows from the cursor")
+	}
+	m = keys(m, "j", "j")
+	m = send(m, previewLoadedMsg{previewKey(tabPane, "agent-web-perf"), "perf output"})
+	if _, ok := m.previews[previewKey(tabPane, "agent-api-fix-login")]; ok {
+		t.Error("kept the preview of a session that left prefetch range")
+	}
+}
+
+func TestScrollBackCapturesOnlyThePage(t *testing.T) {
+	f := tmuxtest.New().On("capture-pane", "older output\n", nil)
+	tmuxtest.Install(t, f)
+
+	m := fixture(80, 24)
+	m.StackBelow = 0 // side by side: 16 preview rows
+	m.sessions[0].PaneHeight = 40
+	m.sessions[0].History = 10
+	next, cmd := m.scrollPreview(1)
+	m = next.(Model)
+	if m.previewScroll != 8 {
+		t.Fatalf("scrolled back %d lines, want half a page (8)", m.previewScroll)
+	}
+	m = send(m, cmd())
+	want := []string{"capture-pane", "-p", "-e", "-t", "=agent-api-fix-login:", "-S", "16", "-E", "31"}
+	if calls := f.Calls(); len(calls) != 1 || !slices.Equal(calls[0], want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+	if got := m.currentPreview(); got != "older output\n" {
+		t.Errorf("preview = %q, want the scrolled page", got)
+	}
+
+	next, _ = m.scrollPreview(1)
+	m = next.(Model)
+	if m.previewScroll != 10 {
+		t.Errorf("scrolled back %d lines, want the history size (10)", m.previewScroll)
+	}
+	m = keys(m, "j")
+	if m.previewScroll != 0 {
+		t.Errorf("scroll = %d after moving the cursor, want 0", m.previewScroll)
+	}
+}
+
+func TestPreviewIsCappedToItsTail(t *testing.T) {
+	m := fixture(80, 24)
+	m.PreviewMaxBytes = 10
+	m = send(m, previewLoadedMsg{previewKey(tabPane, "agent-api-fix-login"), "first line\nsecond\nthird\n"})
+	if got := m.currentPreview(); got != "third\n" {
+		t.Errorf("capped preview = %q, want the whole lines of the tail", got)
+	}
+}
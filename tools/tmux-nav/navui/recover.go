@@ -0,0 +1,129 @@
+package navui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/attach"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// attachFailure remembers a failed attach so the user can recover from it
+// without losing the selection.
+type attachFailure struct {
+	session string
+	window  string
+	pane    string
+	opts    attach.Options
+	err     error
+}
+
+// Reopen returns a new model with m's settings, its exported fields but
+// the attach request, for running the navigator again after m's program
+// exited. Changes made while it ran, such as the strategy, carry over.
+func (m Model) Reopen() Model {
+	n := New()
+	n.Strategy = m.Strategy
+	n.Notifier = m.Notifier
+	n.PRStatus = m.PRStatus
+	n.Macros = m.Macros
+	n.PreviewMaxBytes = m.PreviewMaxBytes
+	n.Split = m.Split
+	n.StackBelow = m.StackBelow
+	n.PreviewLines = m.PreviewLines
+	n.Refresh = m.Refresh
+	n.Ignore = m.Ignore
+	n.PruneAfter = m.PruneAfter
+	n.PruneExclude = m.PruneExclude
+	n.Plugins = m.Plugins
+	n.Keys = m.Keys
+	return n
+}
+
+// WithAttachError returns the model reopened on a failed attach: the failed
+// session is reselected and a recovery menu offers to retry, switch
+// strategy, copy the attach command or fall back to a plain attach.
+func (m Model) WithAttachError(session string, opts attach.Options, err error) Model {
+	m.failure = &attachFailure{
+		session: session,
+		window:  opts.Window,
+		pane:    opts.Pane,
+		opts:    opts,
+		err:     err,
+	}
+	m.selectName = session
+	m.mode = modeAttachFailed
+	return m
+}
+
+func (m Model) handleRecoveryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := m.failure
+	switch m.action(msg) {
+	case ActRefresh, ActAttach:
+		return m.retryAttach()
+
+	case ActStrategy:
+		m.Strategy = nextStrategy(m.Strategy)
+		return m, nil
+
+	case ActPlainAttach:
+		m.Strategy = attach.PlainAttach
+		return m.retryAttach()
+
+	case ActCopyCommand:
+		line := attach.CommandLine(f.session, m.Strategy, f.opts)
+		if err := copyToClipboard(line); err != nil {
+			m.statusMsg = "copy failed: " + err.Error()
+		} else {
+			m.statusMsg = "copied: " + line
+		}
+		return m, nil
+
+	case ActQuit:
+		return m, tea.Quit
+
+	case ActBack:
+		m.failure = nil
+		m.mode = modeList
+		return m, nil
+	}
+	return m, nil
+}
+
+// retryAttach records the failed target again and quits so main.go attaches.
+func (m Model) retryAttach() (tea.Model, tea.Cmd) {
+	f := m.failure
+	m.AttachSession = f.session
+	m.AttachWindow = f.window
+	m.AttachPane = f.pane
+	m.DetachOthers = f.opts.DetachOthers
+	return m, tea.Quit
+}
+
+func (m Model) renderRecovery() string {
+	f := m.failure
+	var sb strings.Builder
+	sb.WriteString(errorStyle.Render(fmt.Sprintf("Attach to %q failed", f.session)) + "\n\n")
+	sb.WriteString(normalStyle.Render(f.err.Error()) + "\n\n")
+	sb.WriteString(normalStyle.Render("Strategy: "+attach.StrategyLabel(m.Strategy)) + "\n\n")
+	km := m.Keys
+	sb.WriteString(helpStyle.Render(km.help(entry("retry", ActRefresh, ActAttach), entry("next strategy", ActStrategy),
+		entry("plain attach", ActPlainAttach))) + "\n")
+	sb.WriteString(helpStyle.Render(km.help(entry("copy command", ActCopyCommand), entry("back to list", ActBack),
+		entry("quit", ActQuit))))
+	if m.statusMsg != "" {
+		sb.WriteString("\n\n" + normalStyle.Render(m.statusMsg))
+	}
+	return modalStyle.Render(sb.String())
+}
+
+// nextStrategy cycles through the available attach strategies.
+func nextStrategy(s attach.Strategy) attach.Strategy {
+	all := attach.Strategies()
+	for i, v := range all {
+		if v == s {
+			return all[(i+1)%len(all)]
+		}
+	}
+	return all[0]
+}
@@ -0,0 +1,87 @@
+package navui
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/attach"
+)
+
+func TestReopenKeepsSettings(t *testing.T) {
+	request := []string{"AttachSession", "AttachWindow", "AttachPane", "DetachOthers"}
+	keys, err := NewKeymap(map[string][]string{"down": {"n"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Give every exported field a value New doesn't.
+	m := New()
+	v := reflect.ValueOf(&m).Elem()
+	for i := range v.NumField() {
+		f, sf := v.Field(i), v.Type().Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		switch f.Kind() {
+		case reflect.Bool:
+			f.SetBool(true)
+		case reflect.Int, reflect.Int64:
+			f.SetInt(f.Int() + 7)
+		case reflect.String:
+			f.SetString("api")
+		case reflect.Slice:
+			f.Set(reflect.MakeSlice(f.Type(), 1, 1))
+		case reflect.Pointer:
+			f.Set(reflect.New(f.Type().Elem()))
+		case reflect.Struct:
+			f.Set(reflect.ValueOf(keys))
+		default:
+			t.Fatalf("no test value for %s of kind %s", sf.Name, f.Kind())
+		}
+	}
+
+	r := reflect.ValueOf(m.Reopen())
+	for i := range v.NumField() {
+		sf := v.Type().Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		got := r.Field(i).Interface()
+		if slices.Contains(request, sf.Name) {
+			if !r.Field(i).IsZero() {
+				t.Errorf("Reopen kept the attach request's %s = %v", sf.Name, got)
+			}
+		} else if !reflect.DeepEqual(got, v.Field(i).Interface()) {
+			t.Errorf("Reopen dropped %s", sf.Name)
+		}
+	}
+}
+
+func TestRecoveryMenuUsesKeymap(t *testing.T) {
+	km, err := NewKeymap(map[string][]string{"strategy": {"t"}, "back": {"b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := fixture(80, 24)
+	m.Keys = km
+	m = m.WithAttachError("agent-web-docs", attach.Options{}, errors.New("no display"))
+
+	if menu := m.renderRecovery(); !strings.Contains(menu, "[t] next strategy") || !strings.Contains(menu, "[b] back to list") {
+		t.Errorf("menu doesn't show the rebound keys:\n%s", menu)
+	}
+	if got := keys(m, "S"); got.Strategy != m.Strategy {
+		t.Error("S still cycles the strategy after it was rebound to t")
+	}
+	if got := keys(m, "t"); got.Strategy == m.Strategy {
+		t.Error("t didn't cycle the strategy")
+	}
+	if got := keys(m, "p"); got.Strategy != attach.PlainAttach || got.AttachSession != "agent-web-docs" {
+		t.Errorf("p left strategy %s, attach %q; want a plain attach retried", attach.StrategyName(got.Strategy), got.AttachSession)
+	}
+	if got := keys(m, "b"); got.mode != modeList {
+		t.Errorf("b left mode %v, want the list", got.mode)
+	}
+}
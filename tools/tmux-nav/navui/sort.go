@@ -0,0 +1,82 @@
+package navui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sortOrder is how the session list is ordered.
+type sortOrder int
+
+const (
+	sortTmux     sortOrder = iota // as tmux lists them
+	sortActivity                  // most recently active first
+	sortName                      // by name
+	sortWindows                   // most windows first
+	sortAttached                  // attached first, then by activity
+	numSortOrders
+)
+
+var sortLabels = [numSortOrders]string{"", "activity", "name", "windows", "attached"}
+
+// less reports whether a sorts before b.
+func (o sortOrder) less(a, b tmuxclient.Session) bool {
+	switch o {
+	case sortActivity:
+		return a.LastUsed.After(b.LastUsed)
+	case sortName:
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	case sortWindows:
+		return a.Windows > b.Windows
+	case sortAttached:
+		if a.Attached != b.Attached {
+			return a.Attached
+		}
+		return a.LastUsed.After(b.LastUsed)
+	}
+	return false
+}
+
+// sortSessions orders the sessions by the current sort order, keeping the
+// cursor on the same session. Grouping, when on, is applied on top.
+func (m *Model) sortSessions() {
+	if len(m.sessions) == 0 || m.sort == sortTmux {
+		return
+	}
+	current := m.sessions[m.cursor].Name
+	sort.SliceStable(m.sessions, func(a, b int) bool {
+		return m.sort.less(m.sessions[a], m.sessions[b])
+	})
+	m.selectSession(current)
+}
+
+// cycleSort switches to the next sort order. Returning to tmux's order
+// reloads to restore it.
+func (m Model) cycleSort() (tea.Model, tea.Cmd) {
+	m.sort = (m.sort + 1) % numSortOrders
+	if m.sort == sortTmux {
+		m.statusMsg = "sorted as tmux lists sessions"
+		if len(m.sessions) > 0 {
+			m.selectName = m.sessions[m.cursor].Name
+		}
+		return m, m.refresh()
+	}
+	m.statusMsg = "sorted by " + sortLabels[m.sort]
+	m.sortSessions()
+	if m.grouped {
+		m.sortByGroup()
+	}
+	m.keepCursorVisible()
+	return m, nil
+}
+
+// sortStatus names the sort order for the header.
+func (m Model) sortStatus() string {
+	if m.sort == sortTmux {
+		return ""
+	}
+	return "  by " + sortLabels[m.sort]
+}
@@ -0,0 +1,181 @@
+package navui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the navigator's palette. Colours are lipgloss colours: an ANSI
+// number ("0"–"255") or a hex value ("#5f87d7").
+type Theme struct {
+	Title    lipgloss.Color // headings, group headers
+	Selected lipgloss.Color // the highlighted row or card
+	Text     lipgloss.Color
+	Muted    lipgloss.Color // key help, detached sessions, output lines
+	Border   lipgloss.Color
+	Error    lipgloss.Color // errors, errored agents, the attach failure menu
+	Warning  lipgloss.Color // confirmations and prompts
+	Attached lipgloss.Color // the attached badge
+	Agent    lipgloss.Color // the agent badge of agents in no known state
+	Working  lipgloss.Color
+	Waiting  lipgloss.Color // agents waiting on input or permission
+	Idle     lipgloss.Color
+	Stuck    lipgloss.Color // stuck and rate-limited agents
+}
+
+// The built-in themes.
+var (
+	// DarkTheme suits dark terminal backgrounds; it is the default.
+	DarkTheme = Theme{
+		Title: "86", Selected: "212", Text: "252", Muted: "241", Border: "62",
+		Error: "196", Warning: "214", Attached: "46", Agent: "141",
+		Working: "42", Waiting: "220", Idle: "245", Stuck: "208",
+	}
+	// LightTheme suits light terminal backgrounds.
+	LightTheme = Theme{
+		Title: "25", Selected: "162", Text: "235", Muted: "244", Border: "67",
+		Error: "160", Warning: "130", Attached: "28", Agent: "91",
+		Working: "28", Waiting: "136", Idle: "244", Stuck: "166",
+	}
+	// HighContrastTheme keeps to the bright ANSI colours, which terminals
+	// tune to stand out on their own background.
+	HighContrastTheme = Theme{
+		Title: "14", Selected: "11", Text: "15", Muted: "7", Border: "15",
+		Error: "9", Warning: "11", Attached: "10", Agent: "13",
+		Working: "10", Waiting: "11", Idle: "7", Stuck: "9",
+	}
+)
+
+// themes are the built-in themes by name.
+var themes = map[string]Theme{
+	"dark":          DarkTheme,
+	"light":         LightTheme,
+	"high-contrast": HighContrastTheme,
+}
+
+// themeColor names a colour of a theme for overriding.
+type themeColor struct {
+	name  string
+	color func(*Theme) *lipgloss.Color
+}
+
+// themeColors are the colours of a theme config can override.
+var themeColors = []themeColor{
+	{"title", func(t *Theme) *lipgloss.Color { return &t.Title }},
+	{"selected", func(t *Theme) *lipgloss.Color { return &t.Selected }},
+	{"text", func(t *Theme) *lipgloss.Color { return &t.Text }},
+	{"muted", func(t *Theme) *lipgloss.Color { return &t.Muted }},
+	{"border", func(t *Theme) *lipgloss.Color { return &t.Border }},
+	{"error", func(t *Theme) *lipgloss.Color { return &t.Error }},
+	{"warning", func(t *Theme) *lipgloss.Color { return &t.Warning }},
+	{"attached", func(t *Theme) *lipgloss.Color { return &t.Attached }},
+	{"agent", func(t *Theme) *lipgloss.Color { return &t.Agent }},
+	{"working", func(t *Theme) *lipgloss.Color { return &t.Working }},
+	{"waiting", func(t *Theme) *lipgloss.Color { return &t.Waiting }},
+	{"idle", func(t *Theme) *lipgloss.Color { return &t.Idle }},
+	{"stuck", func(t *Theme) *lipgloss.Color { return &t.Stuck }},
+}
+
+// NewTheme returns the built-in theme called name ("" for the default)
+// with the given colours overridden, e.g. {"selected": "#d75f00"}.
+func NewTheme(name string, colors map[string]string) (Theme, error) {
+	if name == "" {
+		name = "dark"
+	}
+	t, ok := themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("theme: unknown theme %q (want dark, light or high-contrast)", name)
+	}
+	for key, c := range colors {
+		i := slices.IndexFunc(themeColors, func(tc themeColor) bool { return tc.name == key })
+		if i < 0 {
+			return Theme{}, fmt.Errorf("theme: unknown colour %q", key)
+		}
+		*themeColors[i].color(&t) = lipgloss.Color(c)
+	}
+	return t, nil
+}
+
+// The navigator's styles, drawn from the theme in use.
+var (
+	titleStyle, selectedStyle, normalStyle, helpStyle  lipgloss.Style
+	errorStyle, confirmStyle, modalStyle, overlayStyle lipgloss.Style
+	listBorderStyle, previewBorderStyle                lipgloss.Style
+	cardStyle, selectedCardStyle, groupHeaderStyle     lipgloss.Style
+	attachedBadge, detachedBadge, agentBadge           lipgloss.Style
+	stuckColor                                         lipgloss.Color
+	stateColors                                        map[agent.State]lipgloss.Color
+)
+
+func init() { UseTheme(DarkTheme) }
+
+// UseTheme styles the navigator with t. Call it before running a Model.
+func UseTheme(t Theme) {
+	titleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Title).
+		Padding(0, 1)
+
+	selectedStyle = lipgloss.NewStyle().
+		Foreground(t.Selected).
+		Bold(true)
+
+	normalStyle = lipgloss.NewStyle().
+		Foreground(t.Text)
+
+	helpStyle = lipgloss.NewStyle().
+		Foreground(t.Muted)
+
+	errorStyle = lipgloss.NewStyle().
+		Foreground(t.Error)
+
+	confirmStyle = lipgloss.NewStyle().
+		Foreground(t.Warning).
+		Bold(true)
+
+	modalStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Error).
+		Padding(1, 2)
+
+	overlayStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Border).
+		Padding(1, 2)
+
+	listBorderStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Border).
+		Padding(0, 1)
+	previewBorderStyle = listBorderStyle
+
+	cardStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Border).
+		Padding(0, 1).
+		Width(cardWidth)
+	selectedCardStyle = cardStyle.BorderForeground(t.Selected)
+
+	groupHeaderStyle = lipgloss.NewStyle().
+		Foreground(t.Title).
+		Bold(true)
+
+	attachedBadge = lipgloss.NewStyle().Foreground(t.Attached).SetString("●")
+	detachedBadge = lipgloss.NewStyle().Foreground(t.Muted).SetString("○")
+	agentBadge = lipgloss.NewStyle().Foreground(t.Agent).SetString("◆")
+
+	// The agent badge is coloured by state: waiting when a human is
+	// needed, working, idle or in trouble.
+	stuckColor = t.Stuck
+	stateColors = map[agent.State]lipgloss.Color{
+		agent.StateWorking:     t.Working,
+		agent.StateWaiting:     t.Waiting,
+		agent.StatePermission:  t.Waiting,
+		agent.StateIdle:        t.Idle,
+		agent.StateErrored:     t.Error,
+		agent.StateRateLimited: t.Stuck,
+	}
+}
@@ -0,0 +1,24 @@
+package navui
+
+import "testing"
+
+func TestNewThemeOverridesColours(t *testing.T) {
+	th, err := NewTheme("light", map[string]string{"selected": "#d75f00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Selected != "#d75f00" || th.Title != LightTheme.Title {
+		t.Errorf("theme = %+v, want light with selected overridden", th)
+	}
+	if th, _ := NewTheme("", nil); th != DarkTheme {
+		t.Errorf("default theme = %+v, want dark", th)
+	}
+	for _, bad := range []struct {
+		name   string
+		colors map[string]string
+	}{{"solarized", nil}, {"dark", map[string]string{"highlight": "1"}}} {
+		if _, err := NewTheme(bad.name, bad.colors); err == nil {
+			t.Errorf("NewTheme(%q, %v) succeeded", bad.name, bad.colors)
+		}
+	}
+}
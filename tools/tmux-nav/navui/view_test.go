@@ -0,0 +1,232 @@
+package navui
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/attach"
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/transcript"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Run `go test ./navui -update` to rewrite the golden files after an
+// intended layout change, and review the diff.
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestMain(m *testing.M) {
+	// Plain text keeps the golden files readable and terminal-independent.
+	lipgloss.SetColorProfile(termenv.Ascii)
+	os.Exit(m.Run())
+}
+
+// sizes are the terminal sizes every view is rendered at.
+var sizes = []struct{ w, h int }{{80, 24}, {120, 40}, {60, 20}}
+
+// fixture is a model with a mix of agent and plain sessions loaded. Ages
+// are relative to now so that they render the same on every run.
+func fixture(w, h int) Model {
+	now := time.Now()
+	sessions := []tmuxclient.Session{
+		{Name: "agent-api-fix-login", Windows: 1, LastUsed: now.Add(-90 * time.Minute), Created: now.Add(-3 * time.Hour),
+			AgentTag: "api", Task: "Fix the login redirect loop", Branch: "agent/fix-login", Worktree: "/src/api-fix-login"},
+		{Name: "agent-web-docs", Windows: 2, LastUsed: now.Add(-5 * time.Minute), Created: now.Add(-time.Hour),
+			AgentTag: "web", Task: "Document the build"},
+		{Name: "agent-web-perf", Windows: 1, LastUsed: now.Add(-2 * time.Hour), Created: now.Add(-5 * time.Hour),
+			AgentTag: "web", Task: "Profile the landing page"},
+		{Name: "dotfiles", Windows: 3, Attached: true, LastUsed: now.Add(-30 * time.Second), Created: now.Add(-48 * time.Hour)},
+	}
+	m := New()
+	m.Strategy = attach.PlainAttach
+	m = send(m, tea.WindowSizeMsg{Width: w, Height: h})
+	m = send(m, sessionsLoadedMsg{
+		sessions: sessions,
+		states: map[string]agent.State{
+			"agent-api-fix-login": agent.StatePermission,
+			"agent-web-docs":      agent.StateWorking,
+			"agent-web-perf":      agent.StateIdle,
+		},
+		details: map[string]transcript.Info{
+			"agent-api-fix-login": {
+				Task: "Fix the login redirect loop", LastTool: "Bash(go test ./auth/...)", Turns: 4,
+				Usage: transcript.Usage{InputTokens: 12000, OutputTokens: 3400, CostUSD: 0.42},
+				Todos: []transcript.Todo{
+					{Content: "Reproduce the loop", Status: "completed"},
+					{Content: "Fix the cookie path", Status: "in_progress"},
+					{Content: "Add a regression test", Status: "pending"},
+				},
+			},
+		},
+		stuck: map[string]bool{},
+	})
+	return send(m, previewLoadedMsg{previewKey(tabPane, "agent-api-fix-login"), "$ go test ./auth/...\nok  \tauth\t0.012s\n\nDo you want to proceed?\n❯ 1. Yes\n  2. No"})
+}
+
+// send feeds msg to the model, discarding any command it returns: golden
+// tests render state, they don't run tmux.
+func send(m Model, msg tea.Msg) Model {
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
+
+// keys sends key presses, e.g. keys(m, "j", "d").
+func keys(m Model, ks ...string) Model {
+	for _, k := range ks {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m = send(m, msg)
+	}
+	return m
+}
+
+// views are the states the golden tests render, each set up from the
+// fixture.
+var views = []struct {
+	name  string
+	setup func(Model) Model
+}{
+	{"list", func(m Model) Model { return m }},
+	{"list-second", func(m Model) Model { return keys(m, "j") }},
+	{"confirm-kill", func(m Model) Model { return keys(m, "d") }},
+	{"confirm-kill-marked", func(m Model) Model { return keys(m, " ", "j", " ", "d") }},
+	{"confirm-archive", func(m Model) Model { return keys(m, "X") }},
+	{"confirm-prune", func(m Model) Model {
+		m.PruneAfter, m.PruneExclude = time.Hour, []string{"agent-web-d*"}
+		return keys(m, "P")
+	}},
+	{"attention", func(m Model) Model { return keys(m, "!") }},
+	{"grid", func(m Model) Model {
+		m = keys(m, "g")
+		return send(m, gridLinesMsg{map[string]string{"agent-web-docs": "Reading docs/build.md"}})
+	}},
+	{"grouped", func(m Model) Model {
+		m.groups = map[string]string{
+			"agent-api-fix-login": "/src/api",
+			"agent-web-docs":      "/src/web",
+			"agent-web-perf":      "/src/web",
+		}
+		return keys(m, "G")
+	}},
+	{"sorted", func(m Model) Model { return keys(m, "s") }},
+	{"needs-attention", func(m Model) Model { return keys(m, "j", "f") }},
+	{"help", func(m Model) Model { return keys(m, "?") }},
+	{"list-wider", func(m Model) Model { return keys(m, ">", ">") }},
+	{"long-list", func(m Model) Model {
+		now := time.Now()
+		var sessions []tmuxclient.Session
+		for i := range 60 {
+			sessions = append(sessions, tmuxclient.Session{Name: fmt.Sprintf("scratch-%02d", i), Windows: 1,
+				LastUsed: now.Add(-time.Duration(i+1) * time.Hour)})
+		}
+		m = send(m, sessionsLoadedMsg{sessions: sessions, stuck: map[string]bool{}})
+		return keys(m, strings.Split(strings.Repeat("j", 25), "")...)
+	}},
+	{"filter-typing", func(m Model) Model { return keys(m, "/", "w", "p") }},
+	{"filtered", func(m Model) Model { return keys(m, "/", "w", "d", "enter") }},
+	{"filter-no-match", func(m Model) Model { return keys(m, "/", "z", "z", "enter") }},
+	{"windows", func(m Model) Model {
+		m = keys(m, "l")
+		m = send(m, windowsLoadedMsg{session: "agent-api-fix-login", windows: []tmuxclient.Window{
+			{Index: 0, Name: "claude", Panes: 1, Command: "claude"},
+			{Index: 1, Name: "tests", Active: true, Panes: 2, Command: "zsh"},
+		}})
+		return send(m, previewLoadedMsg{windowKey("agent-api-fix-login", 1), "$ go test ./...\nok  \tauth\t0.012s"})
+	}},
+	{"panes", func(m Model) Model {
+		m = keys(m, "l")
+		m = send(m, windowsLoadedMsg{session: "agent-api-fix-login", windows: []tmuxclient.Window{
+			{Index: 0, Name: "claude", Active: true, Panes: 2, Command: "claude"},
+		}})
+		m = keys(m, "l")
+		return send(m, panesLoadedMsg{session: "agent-api-fix-login", window: 0, panes: []tmuxclient.Pane{
+			{Index: 0, Active: true, Command: "claude", Path: "/src/api-fix-login"},
+			{Index: 1, Command: "go", Path: "/src/api-fix-login/auth"},
+		}})
+	}},
+	{"rename-input", func(m Model) Model { return keys(m, "R") }},
+	{"prompt-input", func(m Model) Model { return keys(m, "i", "h", "i") }},
+	{"send-input", func(m Model) Model { return keys(m, ":", "l", "s") }},
+	{"broadcast-input", func(m Model) Model { return keys(m, " ", " ", "B", "g", "o") }},
+	{"log", func(m Model) Model {
+		m = keys(m, "L")
+		at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.Local)
+		return send(m, logLoadedMsg{entries: []eventlog.Entry{
+			{Time: at, Session: "agent-web-perf", Kind: "restart", Message: "restarted after exit status 1 (1/3)"},
+			{Time: at.Add(time.Minute), Session: "agent-api-fix-login", Kind: "archive", Message: "archived after 2h0m0s"},
+		}})
+	}},
+	{"attach-failed", func(m Model) Model {
+		return m.WithAttachError("agent-web-docs", attach.Options{}, errors.New("open terminal: no display"))
+	}},
+	{"error", func(m Model) Model { return send(m, errMsg{errors.New("tmux list-sessions: exit status 1")}) }},
+	{"empty", func(m Model) Model { return send(m, sessionsLoadedMsg{}) }},
+	{"first-run", func(m Model) Model {
+		m.hadServer = false
+		return send(m, serverGoneMsg{})
+	}},
+}
+
+// TestViews renders every view at every size. The footer's key help is
+// left out, so that rewording it doesn't touch every view: TestFooterKeys
+// covers it.
+func TestViews(t *testing.T) {
+	for _, v := range views {
+		for _, sz := range sizes {
+			name := fmt.Sprintf("%s-%dx%d", v.name, sz.w, sz.h)
+			t.Run(name, func(t *testing.T) {
+				golden(t, name, v.setup(fixture(sz.w, sz.h)).render("[keys]"))
+			})
+		}
+	}
+}
+
+// TestFooterKeys renders the footer's key help of every view into one
+// golden file.
+func TestFooterKeys(t *testing.T) {
+	var sb strings.Builder
+	for _, v := range views {
+		fmt.Fprintf(&sb, "%s: %s\n", v.name, v.setup(fixture(120, 40)).footerKeys())
+	}
+	golden(t, "footer-keys", sb.String())
+}
+
+// golden compares got with testdata/<name>.golden.
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./navui -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("view differs from %s:\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package navui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// listRows is how many lines the session list's box holds.
+func (m Model) listRows() int {
+	if m.stacked() {
+		return m.stackedListRows()
+	}
+	// As many as the preview beside it: header, footer and both boxes'
+	// borders and titles come off.
+	return max(3, m.height-7)
+}
+
+// pageList fits the list's rows into its box, w wide: when they overflow
+// it shows the page holding row cur, which may wrap over several lines,
+// and a last line with the cursor's position ("12/57") and arrows for the
+// pages above and below.
+func (m Model) pageList(rows []string, cur, w int) string {
+	fit := m.listRows()
+	wrap := lipgloss.NewStyle().Width(max(1, w-2)) // the box's padding
+	heights := make([]int, len(rows))
+	total := 0
+	for i, r := range rows {
+		heights[i] = lipgloss.Height(wrap.Render(r))
+		total += heights[i]
+	}
+	if total <= fit {
+		return strings.Join(rows, "\n") + "\n"
+	}
+
+	// Break pages from the top so they stay put as the cursor moves
+	// within one; a row taller than a page gets one of its own.
+	fit = max(1, fit-1) // the position line
+	start, used := 0, 0
+	for i, h := range heights {
+		if used > 0 && used+h > fit {
+			if i > cur {
+				break
+			}
+			start, used = i, 0
+		}
+		used += h
+	}
+	end := start
+	for used = 0; end < len(rows) && (end == start || used+heights[end] <= fit); end++ {
+		used += heights[end]
+	}
+
+	nav := m.navRows()
+	pos := fmt.Sprintf("%d/%d", slices.Index(nav, m.cursor)+1, len(nav))
+	if start > 0 {
+		pos = "↑ " + pos
+	}
+	if end < len(rows) {
+		pos += " ↓"
+	}
+	shown := slices.Clone(rows[start:end])
+	return strings.Join(append(shown, helpStyle.Render(pos)), "\n")
+}
@@ -0,0 +1,157 @@
+package navui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// windowsLoadedMsg carries the windows of the session drilled into.
+type windowsLoadedMsg struct {
+	session string
+	windows []tmuxclient.Window
+	err     error
+	gone    bool // the session no longer exists
+}
+
+// loadWindows lists the windows of session in the background.
+func (m Model) loadWindows(session string) tea.Cmd {
+	return m.background(m.pool.Group("windows"), func() tea.Msg {
+		windows, err := tmuxclient.ListWindows(context.Background(), session)
+		return windowsLoadedMsg{session, windows, err, err != nil && !tmuxclient.HasSession(context.Background(), session)}
+	})
+}
+
+// openWindows descends into the selected session's window list.
+func (m Model) openWindows() (tea.Model, tea.Cmd) {
+	if len(m.sessions) == 0 {
+		return m, nil
+	}
+	m.mode = modeWindows
+	m.winSession = m.sessions[m.cursor].Name
+	m.windows, m.winCursor = nil, 0
+	m.previewScroll = 0
+	return m, m.loadWindows(m.winSession)
+}
+
+// closeWindows returns to the session list.
+func (m Model) closeWindows() (tea.Model, tea.Cmd) {
+	m.mode = modeList
+	m.windows, m.panes, m.winSession = nil, nil, ""
+	return m, m.loadPreview()
+}
+
+// storeWindows takes in a loaded window list, keeping the cursor on the
+// same window or, the first time, putting it on the active one.
+func (m Model) storeWindows(msg windowsLoadedMsg) (tea.Model, tea.Cmd) {
+	if m.mode != modeWindows || msg.session != m.winSession {
+		return m, nil
+	}
+	if msg.gone {
+		m.statusMsg = fmt.Sprintf("session %q is gone", msg.session)
+		return m.closeWindows()
+	}
+	if msg.err != nil {
+		m.statusMsg = msg.err.Error()
+		return m, nil
+	}
+	cursor := -1
+	for i, w := range msg.windows {
+		if m.windows == nil && w.Active {
+			cursor = i
+		} else if sel, ok := m.selectedWindow(); ok && w.Index == sel.Index {
+			cursor = i
+		}
+	}
+	m.windows = msg.windows
+	m.winCursor = max(0, min(cursor, len(m.windows)-1))
+	return m, m.loadPreview()
+}
+
+// selectedWindow returns the highlighted window, if any.
+func (m Model) selectedWindow() (tmuxclient.Window, bool) {
+	if m.winCursor >= len(m.windows) {
+		return tmuxclient.Window{}, false
+	}
+	return m.windows[m.winCursor], true
+}
+
+// windowKey identifies the cached preview of one window.
+func windowKey(session string, index int) string {
+	return "window:" + session + ":" + strconv.Itoa(index)
+}
+
+// captureWindow loads the preview of the highlighted window.
+func (m Model) captureWindow() tea.Cmd {
+	w, ok := m.selectedWindow()
+	if !ok {
+		return nil
+	}
+	session := m.winSession
+	key, lines := windowKey(session, w.Index), m.previewLines()
+	return m.background(m.pool.Group("preview"), func() tea.Msg {
+		content, err := tmuxclient.CaptureWindow(context.Background(), session, w.Index, lines)
+		if err != nil {
+			return previewLoadedMsg{key, "(capture failed: " + err.Error() + ")"}
+		}
+		return previewLoadedMsg{key, content}
+	})
+}
+
+func (m Model) handleWindowsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.action(msg) {
+	case ActQuit:
+		return m, tea.Quit
+	case ActBack, ActDrillOut:
+		return m.closeWindows()
+	case ActDrillIn:
+		return m.openPanes()
+	case ActUp:
+		if m.winCursor > 0 {
+			m.winCursor--
+			return m, m.loadPreview()
+		}
+	case ActDown:
+		if m.winCursor < len(m.windows)-1 {
+			m.winCursor++
+			return m, m.loadPreview()
+		}
+	case ActAttach:
+		if w, ok := m.selectedWindow(); ok {
+			m.AttachSession = m.winSession
+			m.AttachWindow = strconv.Itoa(w.Index)
+			return m, tea.Quit
+		}
+	case ActRefresh:
+		tmuxclient.Invalidate(m.winSession)
+		return m, m.loadWindows(m.winSession)
+	}
+	return m, nil
+}
+
+// renderWindows lists the windows of the session drilled into: index,
+// name, active flag and pane count.
+func (m Model) renderWindows(w int) string {
+	if m.windows == nil {
+		return normalStyle.Render("(loading windows…)")
+	}
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render(truncate("Windows: "+m.winSession, w-4)) + "\n")
+	for i, win := range m.windows {
+		active := " "
+		if win.Active {
+			active = "*"
+		}
+		label := truncate(fmt.Sprintf("%2d%s %-16s %dp  %s", win.Index, active, win.Name, win.Panes, win.Command), w-4)
+		if i == m.winCursor {
+			sb.WriteString(selectedStyle.Render("▶ "+label) + "\n")
+		} else {
+			sb.WriteString(normalStyle.Render("  "+label) + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,42 @@
+package navui
+
+import (
+	"context"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxWorkers bounds the navigator's background jobs (refreshes with their
+// state detection, preview captures, git, gh and plugin calls) however
+// many a burst of updates asks for. Actions the user asked for run
+// directly, so they never queue behind them.
+const maxWorkers = 4
+
+// background returns a command running job on the worker pool as part of
+// ctx's batch. A superseded batch's jobs still waiting for a worker are
+// dropped (see workpool.Pool.Group).
+func (m Model) background(ctx context.Context, job tea.Cmd) tea.Cmd {
+	pool := m.pool
+	return func() tea.Msg {
+		defer catchJob()
+		var msg tea.Msg
+		if pool.Run(ctx, func() { msg = job() }) != nil {
+			return nil
+		}
+		return msg
+	}
+}
+
+// refresh reloads the sessions in the background; a newer refresh
+// supersedes one still waiting for a worker.
+func (m Model) refresh() tea.Cmd {
+	return m.background(m.pool.Group("sessions"), loadSessions)
+}
+
+// reload is refresh bypassing cached tmux output, for explicit refreshes
+// and changes tmux-nav didn't make itself.
+func (m Model) reload() tea.Cmd {
+	tmuxclient.Invalidate("")
+	return m.refresh()
+}
@@ -0,0 +1,18 @@
+package notify
+
+import "github.com/bjornslib/tmux-nav/platform"
+
+// Desktop shows native desktop notifications with the platform's own tool:
+// terminal-notifier or osascript on macOS, notify-send on Linux, a Windows
+// notification under WSL.
+type Desktop struct{}
+
+// Send implements Sink.
+func (Desktop) Send(e Event) error {
+	return Show("tmux-nav", e.Text())
+}
+
+// Show displays a desktop notification with the given title and body.
+func Show(title, body string) error {
+	return platform.Current().Notify(title, body)
+}
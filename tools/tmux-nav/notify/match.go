@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxLine caps the matching line carried by a Matched event.
+const maxLine = 200
+
+// Matcher raises Matched events when a pattern shows up in what sessions
+// print, at most once per session per cooldown.
+type Matcher struct {
+	re       *regexp.Regexp
+	cooldown time.Duration
+	seen     map[string]map[string]bool // lines of the last capture, by session
+	fired    map[string]time.Time       // last event, by session
+}
+
+// NewMatcher creates a matcher for re.
+func NewMatcher(re *regexp.Regexp, cooldown time.Duration) *Matcher {
+	return &Matcher{re: re, cooldown: cooldown, seen: map[string]map[string]bool{}, fired: map[string]time.Time{}}
+}
+
+// Scan looks for the pattern in the lines of a session's latest capture
+// that weren't in its previous one. The first capture of a session only
+// sets the baseline: output printed before watching began isn't news.
+func (m *Matcher) Scan(session, capture string, now time.Time) (Event, bool) {
+	lines := strings.Split(capture, "\n")
+	prev, known := m.seen[session]
+	seen := make(map[string]bool, len(lines))
+	var hit string
+	for _, l := range lines {
+		l = strings.TrimRight(l, " ")
+		seen[l] = true
+		if hit == "" && known && !prev[l] && m.re.MatchString(l) {
+			hit = strings.TrimSpace(l)
+		}
+	}
+	m.seen[session] = seen
+	if hit == "" || now.Sub(m.fired[session]) < m.cooldown {
+		return Event{}, false
+	}
+	m.fired[session] = now
+	if r := []rune(hit); len(r) > maxLine {
+		hit = string(r[:maxLine]) + "…"
+	}
+	return Event{Kind: Matched, Session: session, Line: hit, Time: now}, true
+}
+
+// Forget drops what the matcher remembers about sessions not in live.
+func (m *Matcher) Forget(live map[string]bool) {
+	for name := range m.seen {
+		if !live[name] {
+			delete(m.seen, name)
+			delete(m.fired, name)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Package notify turns agent state transitions into events and delivers
+// them to sinks such as desktop notifications, according to rules.
+package notify
+
+import (
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+)
+
+// Event kinds.
+const (
+	Finished = "finished" // agent went from working to idle
+	Errored  = "error"    // agent hit an error
+	Waiting  = "waiting"  // agent has been waiting on a human past a threshold
+	Matched  = "match"    // a session printed a line matching a watched pattern
+)
+
+// Event is something worth telling a human about.
+type Event struct {
+	Kind     string        `json:"kind"`
+	Session  string        `json:"session"`
+	State    agent.State   `json:"state"`
+	Duration time.Duration `json:"duration"`       // time working (finished) or waiting
+	Line     string        `json:"line,omitempty"` // the matching line (match)
+	Time     time.Time     `json:"time"`
+}
+
+// Text is a one-line human description, e.g.
+// "agent api-refactor finished after 42m".
+func (e Event) Text() string {
+	d := e.Duration.Round(time.Second)
+	switch e.Kind {
+	case Finished:
+		return fmt.Sprintf("agent %s finished after %s", e.Session, d)
+	case Errored:
+		return fmt.Sprintf("agent %s hit an error", e.Session)
+	case Waiting:
+		return fmt.Sprintf("agent %s has been waiting for input for %s", e.Session, d)
+	case Matched:
+		return fmt.Sprintf("%s printed: %s", e.Session, e.Line)
+	}
+	return fmt.Sprintf("agent %s: %s", e.Session, e.Kind)
+}
+
+// Rule selects which events are delivered.
+type Rule struct {
+	Kind  string        // event kind
+	After time.Duration // for Waiting: how long before notifying
+	Match string        // optional session-name glob
+}
+
+// DefaultRules notify on every finish and error, and after a minute of
+// waiting.
+var DefaultRules = []Rule{
+	{Kind: Finished},
+	{Kind: Errored},
+	{Kind: Waiting, After: time.Minute},
+}
+
+func (r Rule) matches(session string) bool {
+	if r.Match == "" {
+		return true
+	}
+	ok, _ := path.Match(r.Match, session)
+	return ok
+}
+
+// Sink delivers events somewhere.
+type Sink interface {
+	Send(Event) error
+}
+
+// track is what the notifier remembers about one session.
+type track struct {
+	state State
+	since time.Time       // when the current state began
+	fired map[string]bool // waiting rules already fired this episode
+}
+
+// State aliases agent.State for brevity within the package.
+type State = agent.State
+
+// Notifier watches agent states and sends events matching its rules to its
+// sinks. It is safe to share between goroutines.
+type Notifier struct {
+	mu     sync.Mutex
+	rules  []Rule
+	sinks  []Sink
+	tracks map[string]*track
+	onErr  func(error)
+}
+
+// New creates a notifier. A nil rules slice uses DefaultRules.
+func New(rules []Rule, sinks ...Sink) *Notifier {
+	if rules == nil {
+		rules = DefaultRules
+	}
+	return &Notifier{rules: rules, sinks: sinks, tracks: map[string]*track{}}
+}
+
+// OnError sets a callback for delivery failures (default: ignored).
+func (n *Notifier) OnError(f func(error)) {
+	n.onErr = f
+}
+
+// Observe feeds the latest agent states (by session name) to the notifier.
+// Matching events are delivered in the background and also returned.
+func (n *Notifier) Observe(states map[string]State, now time.Time) []Event {
+	if n == nil {
+		return nil
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var events []Event
+	for name, st := range states {
+		t := n.tracks[name]
+		if t == nil {
+			// First sighting: don't report transitions we didn't witness.
+			n.tracks[name] = &track{state: st, since: now, fired: map[string]bool{}}
+			continue
+		}
+		if st != t.state {
+			switch {
+			case t.state == agent.StateWorking && st == agent.StateIdle:
+				events = append(events, Event{Kind: Finished, Session: name, State: st, Duration: now.Sub(t.since), Time: now})
+			case st == agent.StateErrored:
+				events = append(events, Event{Kind: Errored, Session: name, State: st, Time: now})
+			}
+			t.state, t.since, t.fired = st, now, map[string]bool{}
+		}
+		if st.NeedsAttention() {
+			events = append(events, n.waitingEvents(name, t, now)...)
+		}
+	}
+	for name := range n.tracks {
+		if _, ok := states[name]; !ok {
+			delete(n.tracks, name)
+		}
+	}
+
+	var matched []Event
+	for _, e := range events {
+		if n.wanted(e) {
+			matched = append(matched, e)
+		}
+	}
+	n.deliver(matched)
+	return matched
+}
+
+// waitingEvents fires each waiting rule once per waiting episode, when the
+// wait exceeds the rule's threshold.
+func (n *Notifier) waitingEvents(name string, t *track, now time.Time) []Event {
+	var events []Event
+	waited := now.Sub(t.since)
+	for i, r := range n.rules {
+		key := fmt.Sprint(i)
+		if r.Kind != Waiting || t.fired[key] || waited < r.After || !r.matches(name) {
+			continue
+		}
+		t.fired[key] = true
+		events = append(events, Event{Kind: Waiting, Session: name, State: t.state, Duration: waited, Time: now})
+	}
+	return events
+}
+
+// wanted reports whether a finished/error event matches a rule. Waiting
+// events were already matched against their rule.
+func (n *Notifier) wanted(e Event) bool {
+	if e.Kind == Waiting {
+		return true
+	}
+	for _, r := range n.rules {
+		if r.Kind == e.Kind && r.matches(e.Session) {
+			return true
+		}
+	}
+	return false
+}
+
+// Deliver sends events to the notifier's sinks in the background,
+// bypassing the rules, for events raised elsewhere such as by a Matcher.
+func (n *Notifier) Deliver(events []Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.deliver(events)
+}
+
+func (n *Notifier) deliver(events []Event) {
+	if len(events) == 0 || len(n.sinks) == 0 {
+		return
+	}
+	sinks, onErr := n.sinks, n.onErr
+	go func() {
+		for _, e := range events {
+			for _, s := range sinks {
+				if err := s.Send(e); err != nil && onErr != nil {
+					onErr(err)
+				}
+			}
+		}
+	}()
+}
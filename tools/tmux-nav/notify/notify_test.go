@@ -0,0 +1,106 @@
+package notify_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/notify"
+)
+
+// observe feeds each step's states to n a minute apart and returns the
+// events raised at each step as "kind session" strings.
+func observe(n *notify.Notifier, steps ...map[string]agent.State) [][]string {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	var got [][]string
+	for i, states := range steps {
+		var step []string
+		for _, e := range n.Observe(states, start.Add(time.Duration(i)*time.Minute)) {
+			step = append(step, e.Kind+" "+e.Session)
+		}
+		got = append(got, step)
+	}
+	return got
+}
+
+func TestRules(t *testing.T) {
+	const (
+		idle    = agent.StateIdle
+		working = agent.StateWorking
+		waiting = agent.StateWaiting
+		errored = agent.StateErrored
+	)
+	tests := []struct {
+		name  string
+		rules []notify.Rule
+		steps []map[string]agent.State
+		want  [][]string
+	}{
+		{
+			name:  "first sighting is not a transition",
+			steps: []map[string]agent.State{{"api": idle}, {"api": idle}},
+			want:  [][]string{nil, nil},
+		},
+		{
+			name:  "working to idle finishes",
+			steps: []map[string]agent.State{{"api": working}, {"api": idle}, {"api": idle}},
+			want:  [][]string{nil, {"finished api"}, nil},
+		},
+		{
+			name:  "errors",
+			steps: []map[string]agent.State{{"api": working}, {"api": errored}},
+			want:  [][]string{nil, {"error api"}},
+		},
+		{
+			name:  "waiting fires once per episode after the threshold",
+			steps: []map[string]agent.State{{"api": working}, {"api": waiting}, {"api": waiting}, {"api": waiting}, {"api": working}, {"api": waiting}, {"api": waiting}},
+			want:  [][]string{nil, nil, {"waiting api"}, nil, nil, nil, {"waiting api"}},
+		},
+		{
+			name:  "rules without a kind stay quiet",
+			rules: []notify.Rule{{Kind: notify.Errored}},
+			steps: []map[string]agent.State{{"api": working}, {"api": idle}, {"api": waiting}, {"api": waiting}},
+			want:  [][]string{nil, nil, nil, nil},
+		},
+		{
+			name:  "session globs",
+			rules: []notify.Rule{{Kind: notify.Finished, Match: "agent-*"}},
+			steps: []map[string]agent.State{{"agent-api": working, "scratch": working}, {"agent-api": idle, "scratch": idle}},
+			want:  [][]string{nil, {"finished agent-api"}},
+		},
+		{
+			name:  "a vanished session starts over",
+			steps: []map[string]agent.State{{"api": working}, {}, {"api": idle}},
+			want:  [][]string{nil, nil, nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := observe(notify.New(tt.rules), tt.steps...)
+			for i := range tt.want {
+				if !slices.Equal(got[i], tt.want[i]) {
+					t.Errorf("step %d raised %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+type sink chan notify.Event
+
+func (s sink) Send(e notify.Event) error { s <- e; return nil }
+
+func TestObserveDelivers(t *testing.T) {
+	s := make(sink, 1)
+	n := notify.New(nil, s)
+	observe(n, map[string]agent.State{"api": agent.StateWorking}, map[string]agent.State{"api": agent.StateIdle})
+	select {
+	case e := <-s:
+		if e.Text() != "agent api finished after 1m0s" {
+			t.Errorf("delivered %q", e.Text())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the finished event never reached the sink")
+	}
+}
@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Webhook formats.
+const (
+	FormatJSON  = "json"  // the Event as JSON, plus a "text" field
+	FormatSlack = "slack" // Slack incoming-webhook payload: {"text": ...}
+)
+
+// Webhook POSTs events to an HTTP endpoint.
+type Webhook struct {
+	URL     string
+	Format  string            // FormatJSON (default) or FormatSlack
+	Headers map[string]string // extra request headers, e.g. Authorization
+	Client  *http.Client      // defaults to a client with a 10s timeout
+}
+
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// Send implements Sink.
+func (w Webhook) Send(e Event) error {
+	body, err := w.payload(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	client := w.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook %s: %s: %s", w.URL, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+func (w Webhook) payload(e Event) ([]byte, error) {
+	if w.Format == FormatSlack {
+		return json.Marshal(map[string]string{"text": e.Text()})
+	}
+	return json.Marshal(struct {
+		Event
+		Text string `json:"text"`
+	}{e, e.Text()})
+}
@@ -0,0 +1,69 @@
+package notify_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/notify"
+)
+
+func TestWebhookFormats(t *testing.T) {
+	e := notify.Event{Kind: notify.Finished, Session: "api", State: agent.StateIdle, Duration: 42 * time.Minute,
+		Time: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		format string
+		want   map[string]any
+	}{
+		{notify.FormatJSON, map[string]any{"kind": "finished", "session": "api", "state": "idle", "duration": float64(42 * time.Minute),
+			"time": "2026-01-01T09:00:00Z", "text": "agent api finished after 42m0s"}},
+		{notify.FormatSlack, map[string]any{"text": "agent api finished after 42m0s"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var got map[string]any
+			var header http.Header
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Error(err)
+				}
+			}))
+			defer srv.Close()
+
+			w := notify.Webhook{URL: srv.URL, Format: tt.format, Headers: map[string]string{"Authorization": "Bearer s3cret"}}
+			if err := w.Send(e); err != nil {
+				t.Fatal(err)
+			}
+			if header.Get("Content-Type") != "application/json" || header.Get("Authorization") != "Bearer s3cret" {
+				t.Errorf("headers = %v, want JSON with the configured Authorization", header)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("payload = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("payload[%q] = %v, want %v", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	err := notify.Webhook{URL: srv.URL, Format: notify.FormatSlack}.Send(notify.Event{Kind: notify.Errored, Session: "api"})
+	if err == nil || !strings.Contains(err.Error(), "403 Forbidden: invalid_token") {
+		t.Errorf("Send = %v, want the status and response body", err)
+	}
+}
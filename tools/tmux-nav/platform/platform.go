@@ -0,0 +1,316 @@
+// Package platform detects the system tmux-nav runs on — macOS, a Linux
+// desktop or console, or WSL — and implements the desktop actions the
+// navigator offers (copying to the clipboard, notifications, opening a
+// directory) with that system's own tools.
+//
+// Each action lists, per kind of platform, the helpers that can perform
+// it, most preferred first; the first one installed is used. Audit reports
+// what was picked, or what to install, for `tmux-nav platform`.
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Kind is a family of platforms sharing the same desktop tools.
+type Kind string
+
+const (
+	MacOS Kind = "macos"
+	Linux Kind = "linux" // and the BSDs, which use the same tools
+	WSL   Kind = "wsl"   // Linux under the Windows Subsystem for Linux
+)
+
+// Kinds lists every kind of platform.
+var Kinds = []Kind{MacOS, Linux, WSL}
+
+// Platform is the system the process runs on.
+type Platform struct {
+	Kind Kind
+	// Display is the graphical session Linux programs can open windows
+	// on: "wayland", "x11", or "" on a console or over SSH. Under WSL it
+	// is WSLg's.
+	Display string
+}
+
+func (p Platform) String() string {
+	if p.Display == "" {
+		return string(p.Kind)
+	}
+	return string(p.Kind) + " (" + p.Display + ")"
+}
+
+// Desktop reports whether windows and notifications can be shown: always
+// on macOS and WSL (on the Windows desktop), and on Linux when a display
+// is reachable.
+func (p Platform) Desktop() bool {
+	return p.Kind != Linux || p.Display != ""
+}
+
+var current = sync.OnceValue(func() Platform {
+	release, _ := os.ReadFile("/proc/sys/kernel/osrelease")
+	return detect(runtime.GOOS, os.Getenv, string(release))
+})
+
+// Current returns the platform the process runs on.
+func Current() Platform {
+	return current()
+}
+
+// detect works out the platform from GOOS, the environment and, on Linux,
+// the kernel release (which names Microsoft under WSL).
+func detect(goos string, getenv func(string) string, release string) Platform {
+	var p Platform
+	switch {
+	case goos == "darwin":
+		return Platform{Kind: MacOS}
+	case getenv("WSL_DISTRO_NAME") != "" || strings.Contains(strings.ToLower(release), "microsoft"):
+		p.Kind = WSL
+	default:
+		p.Kind = Linux
+	}
+	switch {
+	case getenv("WAYLAND_DISPLAY") != "":
+		p.Display = "wayland"
+	case getenv("DISPLAY") != "":
+		p.Display = "x11"
+	}
+	return p
+}
+
+// Action is a desktop action with per-platform implementations.
+type Action string
+
+const (
+	Clipboard Action = "clipboard"
+	Notify    Action = "notify"
+	Open      Action = "open"
+)
+
+// Actions lists every action, in the order Audit reports them.
+var Actions = []Action{Clipboard, Notify, Open}
+
+// input is what an action acts on.
+type input struct {
+	text, title, path string
+}
+
+// helper is a program that performs an action.
+type helper struct {
+	bin string
+	cmd func(bin string, in input) (*exec.Cmd, error)
+	// exitOK ignores the exit status, for programs that report success
+	// as failure (explorer.exe).
+	exitOK bool
+}
+
+// helpers lists, per action and kind, the programs performing it, most
+// preferred first.
+var helpers = map[Action]map[Kind][]helper{
+	Clipboard: {
+		MacOS: {{bin: "pbcopy", cmd: piped()}},
+		Linux: {
+			{bin: "wl-copy", cmd: piped()},
+			{bin: "xclip", cmd: piped("-selection", "clipboard")},
+			{bin: "xsel", cmd: piped("--clipboard", "--input")},
+		},
+		WSL: {
+			{bin: "clip.exe", cmd: piped()},
+			{bin: "wl-copy", cmd: piped()},
+		},
+	},
+	Notify: {
+		MacOS: {
+			{bin: "terminal-notifier", cmd: func(bin string, in input) (*exec.Cmd, error) {
+				return exec.Command(bin, "-title", in.title, "-message", in.text, "-group", "tmux-nav"), nil
+			}},
+			{bin: "osascript", cmd: func(bin string, in input) (*exec.Cmd, error) {
+				script := fmt.Sprintf("display notification %s with title %s", appleString(in.text), appleString(in.title))
+				return exec.Command(bin, "-e", script), nil
+			}},
+		},
+		Linux: {
+			{bin: "notify-send", cmd: notifySend},
+		},
+		WSL: {
+			{bin: "wsl-notify-send.exe", cmd: func(bin string, in input) (*exec.Cmd, error) {
+				return exec.Command(bin, "--category", in.title, in.text), nil
+			}},
+			{bin: "powershell.exe", cmd: func(bin string, in input) (*exec.Cmd, error) {
+				return exec.Command(bin, "-NoProfile", "-NonInteractive", "-Command", balloonScript(in.title, in.text)), nil
+			}},
+		},
+	},
+	Open: {
+		MacOS: {{bin: "open", cmd: withPath()}},
+		Linux: {
+			{bin: "xdg-open", cmd: withPath()},
+			{bin: "gio", cmd: withPath("open")},
+		},
+		WSL: {
+			{bin: "wslview", cmd: withPath()},
+			{bin: "explorer.exe", cmd: windowsPath, exitOK: true},
+		},
+	},
+}
+
+// hints say what to install when no helper for an action is.
+var hints = map[Action]map[Kind]string{
+	Clipboard: {MacOS: "pbcopy", Linux: "wl-clipboard, xclip or xsel", WSL: "clip.exe (Windows interop)"},
+	Notify:    {MacOS: "terminal-notifier", Linux: "notify-send (libnotify)", WSL: "wsl-notify-send or Windows interop"},
+	Open:      {MacOS: "open", Linux: "xdg-utils", WSL: "wslu"},
+}
+
+// tmuxInstall says how to install tmux itself, which tmux-nav can't do
+// without.
+var tmuxInstall = map[Kind]string{
+	MacOS: "brew install tmux",
+	Linux: "your package manager, e.g. sudo apt install tmux",
+	WSL:   "sudo apt install tmux inside the WSL distribution",
+}
+
+// InstallTmux says how to install tmux here.
+func (p Platform) InstallTmux() string {
+	return tmuxInstall[p.Kind]
+}
+
+// ErrNoHelper is returned when no program performing an action is installed.
+var ErrNoHelper = errors.New("no helper installed")
+
+// Copy puts text on the system clipboard.
+func (p Platform) Copy(text string) error {
+	return p.run(Clipboard, input{text: text})
+}
+
+// Notify shows a desktop notification.
+func (p Platform) Notify(title, body string) error {
+	if !p.Desktop() {
+		return fmt.Errorf("notify: no desktop (DISPLAY and WAYLAND_DISPLAY are unset)")
+	}
+	return p.run(Notify, input{title: title, text: body})
+}
+
+// Open opens path (a directory or file) with the desktop's default
+// application, e.g. a directory in the file manager.
+func (p Platform) Open(path string) error {
+	if !p.Desktop() {
+		return fmt.Errorf("open: no desktop (DISPLAY and WAYLAND_DISPLAY are unset)")
+	}
+	return p.run(Open, input{path: path})
+}
+
+// run performs action with the first installed helper that succeeds.
+func (p Platform) run(action Action, in input) error {
+	var errs []error
+	for _, h := range helpers[action][p.Kind] {
+		if _, err := exec.LookPath(h.bin); err != nil {
+			continue
+		}
+		cmd, err := h.cmd(h.bin, in)
+		if err == nil {
+			err = runHelper(cmd, h.exitOK)
+		}
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", h.bin, err))
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("%s: %w (install %s)", action, ErrNoHelper, hints[action][p.Kind])
+	}
+	return fmt.Errorf("%s: %w", action, errors.Join(errs...))
+}
+
+func runHelper(cmd *exec.Cmd, exitOK bool) error {
+	out, err := cmd.CombinedOutput()
+	var ee *exec.ExitError
+	if exitOK && errors.As(err, &ee) {
+		return nil
+	}
+	if err != nil && len(out) > 0 {
+		err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return err
+}
+
+// Support is how an action is performed on a platform.
+type Support struct {
+	Action Action
+	Helper string // the helper that would be used; "" if none is installed
+	Hint   string // what to install when none is
+}
+
+// Audit reports, for each action, the helper that would perform it here.
+func (p Platform) Audit() []Support {
+	var out []Support
+	for _, a := range Actions {
+		s := Support{Action: a, Hint: hints[a][p.Kind]}
+		for _, h := range helpers[a][p.Kind] {
+			if _, err := exec.LookPath(h.bin); err == nil {
+				s.Helper = h.bin
+				break
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// piped returns a helper command reading the text on stdin.
+func piped(args ...string) func(string, input) (*exec.Cmd, error) {
+	return func(bin string, in input) (*exec.Cmd, error) {
+		cmd := exec.Command(bin, args...)
+		cmd.Stdin = strings.NewReader(in.text)
+		return cmd, nil
+	}
+}
+
+// withPath returns a helper command taking the path as its last argument.
+func withPath(args ...string) func(string, input) (*exec.Cmd, error) {
+	return func(bin string, in input) (*exec.Cmd, error) {
+		return exec.Command(bin, append(args, in.path)...), nil
+	}
+}
+
+func notifySend(bin string, in input) (*exec.Cmd, error) {
+	return exec.Command(bin, "--app-name=tmux-nav", in.title, in.text), nil
+}
+
+// windowsPath opens the path with a Windows program, translating it with
+// wslpath first.
+func windowsPath(bin string, in input) (*exec.Cmd, error) {
+	out, err := exec.Command("wslpath", "-w", in.path).Output()
+	if err != nil {
+		return nil, fmt.Errorf("wslpath: %w", err)
+	}
+	return exec.Command(bin, strings.TrimSpace(string(out))), nil
+}
+
+// appleString quotes s as an AppleScript string literal.
+func appleString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// balloonScript is a PowerShell script showing a tray balloon notification,
+// which needs nothing beyond Windows itself.
+func balloonScript(title, text string) string {
+	return fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(5000, %s, %s, 'Info')
+Start-Sleep -Seconds 5
+$n.Dispose()`, psString(title), psString(text))
+}
+
+// psString quotes s as a PowerShell single-quoted string literal.
+func psString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
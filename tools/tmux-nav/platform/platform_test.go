@@ -0,0 +1,52 @@
+package platform
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		goos    string
+		env     map[string]string
+		release string
+		want    Platform
+	}{
+		{"macos", "darwin", map[string]string{"DISPLAY": ":0"}, "", Platform{Kind: MacOS}},
+		{"wayland", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, "6.8.0-generic", Platform{Linux, "wayland"}},
+		{"x11", "linux", map[string]string{"DISPLAY": ":0"}, "6.8.0-generic", Platform{Linux, "x11"}},
+		{"console", "linux", nil, "6.8.0-generic", Platform{Kind: Linux}},
+		{"wsl", "linux", map[string]string{"WSL_DISTRO_NAME": "Ubuntu"}, "", Platform{Kind: WSL}},
+		{"wsl1", "linux", nil, "4.4.0-19041-Microsoft", Platform{Kind: WSL}},
+		{"wslg", "linux", map[string]string{"DISPLAY": ":0"}, "5.15.153.1-microsoft-standard-WSL2", Platform{WSL, "x11"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detect(tt.goos, func(k string) string { return tt.env[k] }, tt.release)
+			if got != tt.want {
+				t.Errorf("detect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if (Platform{Kind: Linux}).Desktop() || !(Platform{Kind: WSL}).Desktop() {
+		t.Error("Desktop() should need a display on Linux only")
+	}
+}
+
+// TestParity fails when an action lacks an implementation, or advice on
+// what to install, on any platform.
+func TestParity(t *testing.T) {
+	for _, a := range Actions {
+		for _, k := range Kinds {
+			if len(helpers[a][k]) == 0 {
+				t.Errorf("%s has no helper on %s", a, k)
+			}
+			if hints[a][k] == "" {
+				t.Errorf("%s has no install hint on %s", a, k)
+			}
+		}
+	}
+	for _, k := range Kinds {
+		if tmuxInstall[k] == "" {
+			t.Errorf("no advice on installing tmux on %s", k)
+		}
+	}
+}
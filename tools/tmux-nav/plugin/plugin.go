@@ -0,0 +1,155 @@
+// Package plugin runs external executables that extend the navigator with
+// list columns, preview tabs and actions bound to keys.
+//
+// A plugin is a command (run with sh -c) that answers one JSON request on
+// stdin with one JSON response on stdout and exits. Every request has a
+// "method":
+//
+//	describe  {}                                  → {"column": true, "tabs": ["Ticket"],
+//	                                                 "actions": [{"key": "J", "name": "open ticket"}]}
+//	columns   {"sessions": [<session>, ...]}      → {"cells": {"<session name>": "PROJ-12"}}
+//	preview   {"tab": "Ticket", "session": <s>}   → {"text": "..."}
+//	action    {"action": "open ticket", "session": <s>}
+//	                                              → {"status": "opened PROJ-12"}
+//
+// A response with "error" set, a non-zero exit or a call running past
+// Timeout fails the request. Sessions are described by Session.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// Timeout bounds each call to a plugin.
+const Timeout = 5 * time.Second
+
+// Session is what a plugin is told about a tmux session.
+type Session struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`               // active pane's working directory
+	Worktree string `json:"worktree,omitempty"` // agent's git worktree
+	Branch   string `json:"branch,omitempty"`
+	Repo     string `json:"repo,omitempty"`
+	Agent    string `json:"agent,omitempty"` // agent tag; empty for plain sessions
+	Task     string `json:"task,omitempty"`
+	State    string `json:"state,omitempty"` // agent state, e.g. "waiting"
+	Attached bool   `json:"attached"`
+}
+
+// SessionOf describes s, whose agent state (if any) is state, to plugins.
+func SessionOf(s tmuxclient.Session, state string) Session {
+	return Session{
+		Name: s.Name, Path: s.Path, Worktree: s.Worktree, Branch: s.Branch, Repo: s.Repo,
+		Agent: s.AgentTag, Task: s.Task, State: state, Attached: s.Attached,
+	}
+}
+
+// Action is a plugin command bound to a key in the session list.
+type Action struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// Manifest is what a plugin contributes, as answered to "describe".
+type Manifest struct {
+	Column  bool     `json:"column"` // adds a cell to each row of the list
+	Tabs    []string `json:"tabs"`   // preview tabs, cycled with tab
+	Actions []Action `json:"actions"`
+}
+
+// Plugin is a described plugin, ready to be called.
+type Plugin struct {
+	Name    string
+	Command string
+	Manifest
+}
+
+// request is the JSON sent to a plugin.
+type request struct {
+	Method   string    `json:"method"`
+	Sessions []Session `json:"sessions,omitempty"`
+	Session  *Session  `json:"session,omitempty"`
+	Tab      string    `json:"tab,omitempty"`
+	Action   string    `json:"action,omitempty"`
+}
+
+// response is the JSON a plugin answers with; which fields are set depends
+// on the method.
+type response struct {
+	Manifest
+	Error  string            `json:"error"`
+	Cells  map[string]string `json:"cells"`
+	Text   string            `json:"text"`
+	Status string            `json:"status"`
+}
+
+// Load asks the plugin named name, run as command, what it contributes.
+func Load(name, command string) (*Plugin, error) {
+	p := &Plugin{Name: name, Command: command}
+	resp, err := p.call(request{Method: "describe"})
+	if err != nil {
+		return nil, err
+	}
+	p.Manifest = resp.Manifest
+	return p, nil
+}
+
+// Cells returns the plugin's column cell for each of sessions, by session
+// name. Sessions it has nothing to say about are left out.
+func (p *Plugin) Cells(sessions []Session) (map[string]string, error) {
+	resp, err := p.call(request{Method: "columns", Sessions: sessions})
+	return resp.Cells, err
+}
+
+// Preview returns the contents of the plugin's preview tab for s.
+func (p *Plugin) Preview(tab string, s Session) (string, error) {
+	resp, err := p.call(request{Method: "preview", Tab: tab, Session: &s})
+	return resp.Text, err
+}
+
+// Do runs the plugin's action on s and returns its status line.
+func (p *Plugin) Do(action string, s Session) (string, error) {
+	resp, err := p.call(request{Method: "action", Action: action, Session: &s})
+	return resp.Status, err
+}
+
+// call runs the plugin once with req on stdin and decodes its answer.
+func (p *Plugin) call(req request) (response, error) {
+	in, err := json.Marshal(req)
+	if err != nil {
+		return response{}, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", p.Command)
+	cmd.Stdin = bytes.NewReader(in)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return response{}, fmt.Errorf("plugin %s: %s timed out after %v", p.Name, req.Method, Timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return response{}, fmt.Errorf("plugin %s: %s: %w", p.Name, req.Method, err)
+	}
+	var resp response
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return response{}, fmt.Errorf("plugin %s: %s: bad response: %w", p.Name, req.Method, err)
+	}
+	if resp.Error != "" {
+		return response{}, fmt.Errorf("plugin %s: %s", p.Name, resp.Error)
+	}
+	return resp, nil
+}
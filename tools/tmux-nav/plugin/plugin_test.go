@@ -0,0 +1,56 @@
+package plugin_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/plugin"
+)
+
+// script is a plugin answering each method with a canned response.
+const script = `#!/bin/sh
+req=$(cat)
+case "$req" in
+*'"method":"describe"'*) echo '{"column": true, "tabs": ["Ticket"], "actions": [{"key": "J", "name": "open"}]}' ;;
+*'"method":"columns"'*)  echo '{"cells": {"api": "PROJ-12"}}' ;;
+*'"method":"preview"'*)  echo '{"text": "PROJ-12: Fix login"}' ;;
+*'"action":"open"'*)     echo '{"error": "no browser"}' ;;
+*)                       exit 3 ;;
+esac
+`
+
+func install(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "plugin")
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestProtocol(t *testing.T) {
+	p, err := plugin.Load("jira", install(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := plugin.Manifest{Column: true, Tabs: []string{"Ticket"}, Actions: []plugin.Action{{Key: "J", Name: "open"}}}
+	if !reflect.DeepEqual(p.Manifest, want) {
+		t.Errorf("manifest = %+v, want %+v", p.Manifest, want)
+	}
+
+	s := plugin.Session{Name: "api"}
+	if cells, err := p.Cells([]plugin.Session{s}); err != nil || cells["api"] != "PROJ-12" {
+		t.Errorf("Cells() = %v, %v", cells, err)
+	}
+	if text, err := p.Preview("Ticket", s); err != nil || text != "PROJ-12: Fix login" {
+		t.Errorf("Preview() = %q, %v", text, err)
+	}
+	if _, err := p.Do("open", s); err == nil || !strings.Contains(err.Error(), "no browser") {
+		t.Errorf("Do() error = %v, want the plugin's error", err)
+	}
+	if _, err := p.Do("unknown", s); err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("Do() error = %v, want the exit status", err)
+	}
+}
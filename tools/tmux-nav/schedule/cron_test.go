@@ -0,0 +1,96 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/schedule"
+)
+
+// at is a local time on the given day, at hh:mm.
+func at(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.Local)
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, expr := range []string{
+		"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+		"* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "1-b * * * *",
+		"@yearly",
+	} {
+		if _, err := schedule.Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded", expr)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	// 2026-03-02 is a Monday, 2026-03-01 a Sunday.
+	for _, tc := range []struct {
+		expr string
+		t    time.Time
+		want bool
+	}{
+		{"* * * * *", at(2026, 3, 2, 13, 37), true},
+		{"@hourly", at(2026, 3, 2, 13, 0), true},
+		{"@hourly", at(2026, 3, 2, 13, 1), false},
+		{"@nightly", at(2026, 3, 2, 2, 0), true},
+		{"*/15 * * * *", at(2026, 3, 2, 13, 45), true},
+		{"*/15 * * * *", at(2026, 3, 2, 13, 50), false},
+		// a/n runs from a to the end of the range.
+		{"5/20 * * * *", at(2026, 3, 2, 13, 45), true},
+		{"5/20 * * * *", at(2026, 3, 2, 13, 5), true},
+		{"5/20 * * * *", at(2026, 3, 2, 13, 20), false},
+		{"10-30/10 * * * *", at(2026, 3, 2, 13, 30), true},
+		{"10-30/10 * * * *", at(2026, 3, 2, 13, 40), false},
+		{"0 9,17 * * *", at(2026, 3, 2, 17, 0), true},
+		{"0 9,17 * * *", at(2026, 3, 2, 12, 0), false},
+		{"0 2 * * 1-5", at(2026, 3, 2, 2, 0), true},
+		{"0 2 * * 1-5", at(2026, 3, 1, 2, 0), false},
+		// 0 and 7 are both Sunday.
+		{"0 0 * * 7", at(2026, 3, 1, 0, 0), true},
+		{"0 0 * * 0", at(2026, 3, 1, 0, 0), true},
+		{"0 0 * * 7", at(2026, 3, 2, 0, 0), false},
+		// With both days restricted, either matches.
+		{"0 0 15 * 1", at(2026, 3, 2, 0, 0), true},
+		{"0 0 15 * 1", at(2026, 3, 15, 0, 0), true},
+		{"0 0 15 * 1", at(2026, 3, 3, 0, 0), false},
+		// With only one restricted, only it counts.
+		{"0 0 15 * *", at(2026, 3, 2, 0, 0), false},
+		{"0 0 1 6 *", at(2026, 3, 1, 0, 0), false},
+	} {
+		s, err := schedule.Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tc.expr, err)
+		}
+		if got := s.Match(tc.t); got != tc.want {
+			t.Errorf("%q matches %s = %v, want %v", tc.expr, tc.t.Format("Mon 2006-01-02 15:04"), got, tc.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	from := at(2026, 3, 2, 13, 37)
+	for _, tc := range []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", at(2026, 3, 2, 13, 38)},
+		{"37 13 * * *", at(2026, 3, 3, 13, 37)}, // strictly after
+		{"@daily", at(2026, 3, 3, 0, 0)},
+		{"@monthly", at(2026, 4, 1, 0, 0)},
+		{"0 2 * * 6", at(2026, 3, 7, 2, 0)},
+		{"30 9 31 * *", at(2026, 3, 31, 9, 30)},
+		{"0 0 1 1 *", at(2027, 1, 1, 0, 0)},
+		{"0 0 29 2 *", at(2028, 2, 29, 0, 0)},
+		{"0 0 31 2 *", time.Time{}},
+	} {
+		s, err := schedule.Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tc.expr, err)
+		}
+		if got := s.Next(from); !got.Equal(tc.want) {
+			t.Errorf("%q next after %s = %s, want %s", tc.expr, from.Format(time.DateTime), got.Format(time.DateTime), tc.want.Format(time.DateTime))
+		}
+	}
+}
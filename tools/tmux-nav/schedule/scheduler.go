@@ -0,0 +1,66 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/agent"
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// Job launches an agent session from a project on a cron schedule.
+type Job struct {
+	Name     string
+	Spec     Spec
+	Project  string
+	Task     string
+	Worktree bool
+}
+
+// Scheduler fires jobs whose schedule matches the current minute. Each
+// run is recorded in the event log (kind "schedule"), which the TUI's log
+// view shows as the run history.
+type Scheduler struct {
+	jobs  []Job
+	fired map[string]time.Time // last minute each job fired
+	last  map[string]string    // session of each job's last run
+}
+
+// NewScheduler returns a scheduler for jobs.
+func NewScheduler(jobs []Job) *Scheduler {
+	return &Scheduler{jobs: jobs, fired: map[string]time.Time{}, last: map[string]string{}}
+}
+
+// Tick launches the jobs due at now. It is safe to call more than once a
+// minute; a job fires at most once per matching minute. A job whose
+// previous run is still alive is skipped rather than doubled up.
+func (s *Scheduler) Tick(now time.Time) {
+	minute := now.Truncate(time.Minute)
+	for _, j := range s.jobs {
+		if !j.Spec.Match(now) || s.fired[j.Name].Equal(minute) {
+			continue
+		}
+		s.fired[j.Name] = minute
+		if prev := s.last[j.Name]; prev != "" && tmuxclient.HasSession(context.Background(), prev) {
+			_ = eventlog.Append(prev, "schedule", "%s: skipped, previous run still active", j.Name)
+			continue
+		}
+		p, err := agent.LookupProject(j.Project)
+		if err == nil {
+			var name string
+			name, err = agent.Spawn(agent.Spec{Project: p, Task: j.Task, Worktree: j.Worktree})
+			if err == nil {
+				s.last[j.Name] = name
+				_ = eventlog.Append(name, "schedule", "%s: started (%s)", j.Name, j.Spec)
+				continue
+			}
+		}
+		_ = eventlog.Append("", "schedule", "%s: failed to start: %v", j.Name, err)
+	}
+}
+
+// Jobs returns the scheduled jobs.
+func (s *Scheduler) Jobs() []Job {
+	return s.jobs
+}
@@ -0,0 +1,122 @@
+package tmuxclient_test
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+)
+
+// sizes are the session counts the parsing benchmarks run at.
+var sizes = []int{10, 100, 500}
+
+// listOutput fakes list-sessions output for n sessions, every other one an
+// agent in a worktree.
+func listOutput(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		name, tag, wt, branch := fmt.Sprintf("scratch-%03d", i), "", "", ""
+		if i%2 == 0 {
+			name, tag = fmt.Sprintf("agent-api-task-%03d", i), "api"
+			wt, branch = "/src/api-"+strconv.Itoa(i), "agent/task-"+strconv.Itoa(i)
+		}
+		sb.WriteString(sessionLine(name, "2", "", "1700000000", "0.0", "claude", "/src/api",
+			tag, wt, branch, "/src/api", "1", "working", "1700000100", "0", "", "0",
+			"1690000000", "Fix the login bug", "50", "12000"))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// fakeServer answers from f with the cache off, so every call parses.
+func fakeServer(b *testing.B, f *tmuxtest.Fake) {
+	tmuxtest.Install(b, f)
+	tmuxclient.SetCacheTTL(0)
+	b.Cleanup(func() { tmuxclient.SetCacheTTL(tmuxclient.DefaultCacheTTL) })
+}
+
+func BenchmarkParseSessions(b *testing.B) {
+	for _, n := range sizes {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			fakeServer(b, tmuxtest.New().On("list-sessions", listOutput(n), nil))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if got, err := tmuxclient.ListSessions(b.Context()); err != nil || len(got) != n {
+					b.Fatalf("ListSessions() = %d sessions, %v", len(got), err)
+				}
+			}
+		})
+	}
+}
+
+// The benchmarks below run against a real tmux server.
+
+// benchServer starts a private tmux server with n sessions for the
+// duration of the benchmark (or test), skipping it when tmux isn't
+// installed.
+func benchServer(b testing.TB, n int) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		b.Skip("tmux not installed")
+	}
+	b.Setenv("TMUX", "")
+	b.Setenv("TMUX_TMPDIR", b.TempDir())
+	for i := 0; i < n; i++ {
+		if err := tmuxclient.NewSession(context.Background(), tmuxclient.NewSessionOptions{Name: fmt.Sprintf("bench-%02d", i)}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.Cleanup(func() { exec.Command("tmux", "kill-server").Run() })
+	tmuxclient.SetCacheTTL(0)
+	b.Cleanup(func() { tmuxclient.SetCacheTTL(tmuxclient.DefaultCacheTTL) })
+}
+
+// connect routes commands through a control-mode connection for the rest
+// of the benchmark or test.
+func connect(b testing.TB) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := tmuxclient.Connect(ctx)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { cancel(); c.Close() })
+}
+
+func BenchmarkListSessions(b *testing.B) {
+	for _, mode := range []string{"process", "control"} {
+		b.Run(mode, func(b *testing.B) {
+			benchServer(b, 40)
+			if mode == "control" {
+				connect(b)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := tmuxclient.ListSessions(b.Context()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCapture(b *testing.B) {
+	for _, mode := range []string{"process", "control"} {
+		b.Run(mode, func(b *testing.B) {
+			benchServer(b, 40)
+			if mode == "control" {
+				connect(b)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := tmuxclient.CapturePanes(b.Context(), "bench-07", 40); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
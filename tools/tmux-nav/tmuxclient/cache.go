@@ -0,0 +1,140 @@
+package tmuxclient
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long read-only tmux output (session lists, pane
+// captures) is reused. It is short enough to look live while collapsing
+// the duplicate calls a refresh, a prefetch and the daemon's housekeeping
+// make within the same moment.
+const DefaultCacheTTL = time.Second
+
+// Timeout bounds every tmux command but streams and waits, so that a
+// server hung under load fails calls instead of blocking them, and the
+// navigator with them, for good.
+const Timeout = 5 * time.Second
+
+// cacheable are the read-only commands whose output is cached.
+var cacheable = map[string]bool{
+	"list-sessions":   true,
+	"list-windows":    true,
+	"list-panes":      true,
+	"capture-pane":    true,
+	"display-message": true,
+}
+
+// passive are commands that neither read cached state nor change it.
+var passive = map[string]bool{
+	"has-session":   true,
+	"wait-for":      true,
+	"switch-client": true,
+	"set-buffer":    true,
+	"delete-buffer": true,
+}
+
+type cacheEntry struct {
+	out     []byte
+	at      time.Time
+	session string // session the output is about; "" for server-wide
+}
+
+// outputCache holds the output of cacheable commands by their arguments.
+type outputCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+var cache = &outputCache{ttl: DefaultCacheTTL, entries: map[string]cacheEntry{}}
+
+// SetCacheTTL changes how long read-only output is reused; zero disables
+// caching.
+func SetCacheTTL(d time.Duration) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.ttl = d
+	cache.entries = map[string]cacheEntry{}
+}
+
+// Invalidate drops cached output about session, along with server-wide
+// output such as the session list. An empty session drops everything.
+// Commands run through this package invalidate what they change
+// themselves; this is for changes made behind its back.
+func Invalidate(session string) {
+	cache.invalidate(session)
+}
+
+func (c *outputCache) invalidate(session string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if session == "" || e.session == "" || e.session == session {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// run runs a tmux command through the current runner, answering
+// read-only commands from the cache while fresh and invalidating the
+// cache after commands that change state.
+func run(ctx context.Context, args ...string) ([]byte, error) {
+	if len(args) == 0 {
+		return runner.Run(ctx)
+	}
+	if !cacheable[args[0]] {
+		out, err := runTimed(ctx, args...)
+		if !passive[args[0]] {
+			cache.invalidate(targetSession(args))
+		}
+		return out, err
+	}
+
+	key := strings.Join(args, "\x00")
+	cache.mu.Lock()
+	ttl := cache.ttl
+	e, ok := cache.entries[key]
+	cache.mu.Unlock()
+	if ok && time.Since(e.at) < ttl {
+		return e.out, nil
+	}
+	out, err := runTimed(ctx, args...)
+	if err == nil && ttl > 0 {
+		cache.mu.Lock()
+		cache.entries[key] = cacheEntry{out: out, at: time.Now(), session: targetSession(args)}
+		cache.mu.Unlock()
+	}
+	return out, err
+}
+
+// runTimed runs a tmux command through the current runner, giving up
+// after Timeout.
+func runTimed(ctx context.Context, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	out, err := runner.Run(ctx, args...)
+	if err != nil && ctx.Err() != nil {
+		return nil, &Error{Command: args[0], Err: ctx.Err()}
+	}
+	return out, commandError(args, "", err)
+}
+
+// targetSession extracts the session a command addresses with -t or -s,
+// or "" when it addresses none or a pane or window by id.
+func targetSession(args []string) string {
+	for i := 1; i < len(args)-1; i++ {
+		if args[i] != "-t" && args[i] != "-s" {
+			continue
+		}
+		t := strings.TrimPrefix(args[i+1], "=")
+		if strings.HasPrefix(t, "%") || strings.HasPrefix(t, "@") || strings.HasPrefix(t, "$") {
+			return ""
+		}
+		session, _, _ := strings.Cut(t, ":")
+		return session
+	}
+	return ""
+}
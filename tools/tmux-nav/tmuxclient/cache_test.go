@@ -0,0 +1,78 @@
+package tmuxclient_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+)
+
+// count returns how many times the fake ran cmd.
+func count(f *tmuxtest.Fake, cmd string) int {
+	n := 0
+	for _, c := range f.Calls() {
+		if c[0] == cmd {
+			n++
+		}
+	}
+	return n
+}
+
+func TestListSessionsIsCached(t *testing.T) {
+	f := tmuxtest.New().On("list-sessions", "", nil).On("kill-session", "", nil)
+	tmuxtest.Install(t, f)
+
+	tmuxclient.ListSessions(t.Context())
+	tmuxclient.ListSessions(t.Context())
+	if n := count(f, "list-sessions"); n != 1 {
+		t.Fatalf("list-sessions ran %d times, want 1", n)
+	}
+	tmuxclient.KillSession(t.Context(), "api")
+	tmuxclient.ListSessions(t.Context())
+	if n := count(f, "list-sessions"); n != 2 {
+		t.Errorf("list-sessions ran %d times after a kill, want 2", n)
+	}
+}
+
+func TestCaptureInvalidatedPerSession(t *testing.T) {
+	f := tmuxtest.New().On("capture-pane", "out", nil).On("send-keys", "", nil)
+	tmuxtest.Install(t, f)
+
+	tmuxclient.CaptureText(t.Context(), "api", 10)
+	tmuxclient.CaptureText(t.Context(), "web", 10)
+	tmuxclient.SendKeys(t.Context(), "api:", "Enter")
+	tmuxclient.CaptureText(t.Context(), "api", 10)
+	tmuxclient.CaptureText(t.Context(), "web", 10)
+	if n := count(f, "capture-pane"); n != 3 {
+		t.Errorf("capture-pane ran %d times, want 3 (api twice, web once)", n)
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	f := tmuxtest.New().
+		On("capture-pane", "", errors.New("no pane")).
+		On("capture-pane", "", errors.New("no pane")).
+		On("capture-pane", "back", nil)
+	tmuxtest.Install(t, f)
+
+	if _, err := tmuxclient.CaptureText(t.Context(), "api", 10); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got, err := tmuxclient.CaptureText(t.Context(), "api", 10); err != nil || got != "back" {
+		t.Errorf("CaptureText() = %q, %v; want a fresh capture", got, err)
+	}
+}
+
+func TestCacheDisabled(t *testing.T) {
+	tmuxclient.SetCacheTTL(0)
+	t.Cleanup(func() { tmuxclient.SetCacheTTL(tmuxclient.DefaultCacheTTL) })
+	f := tmuxtest.New().On("list-sessions", "", nil)
+	tmuxtest.Install(t, f)
+
+	tmuxclient.ListSessions(t.Context())
+	tmuxclient.ListSessions(t.Context())
+	if n := count(f, "list-sessions"); n != 2 {
+		t.Errorf("list-sessions ran %d times with caching off, want 2", n)
+	}
+}
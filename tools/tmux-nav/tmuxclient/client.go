@@ -0,0 +1,613 @@
+package tmuxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// Session represents a tmux session with its metadata.
+type Session struct {
+	Name       string
+	Windows    int
+	Attached   bool // a terminal client is attached (control-mode clients without one don't count)
+	LastUsed   time.Time
+	Created    time.Time
+	ActivePane string    // "window.pane" of the active pane
+	Command    string    // pane_current_command of the active pane
+	Path       string    // pane_current_path of the active pane
+	AgentTag   string    // value of the @agent user option, if set
+	Task       string    // @task: task the agent was spawned or dispatched with
+	Worktree   string    // @worktree: git worktree the session was spawned in
+	Branch     string    // @branch: branch checked out in that worktree
+	Repo       string    // @repo: main repository the worktree belongs to
+	Piped      bool      // whether the active pane is piped (pipe-pane)
+	HookState  string    // @agent_state, pushed by Claude Code hooks
+	HookAt     time.Time // @agent_state_at: when HookState was set
+	Dead       bool      // the active pane's process exited (remain-on-exit)
+	DeadStatus string    // its exit status, when tmux knows it
+	Restarts   int       // @restarts: times the agent was respawned
+	PaneHeight int       // rows of the active pane
+	History    int       // lines of scrollback above them
+}
+
+// fieldSep separates the fields of sessionFormat. It must be printable
+// (tmux 3.x rewrites control characters such as tabs to "_") and unlikely
+// to appear in names, commands or paths.
+const fieldSep = "|^|"
+
+// sessionFormat lists the fields fetched by ListSessions.
+var sessionFormat = strings.Join([]string{
+	"#{session_name}",
+	"#{session_windows}",
+	"#{session_attached_list}",
+	"#{session_activity}",
+	"#{window_index}.#{pane_index}",
+	"#{pane_current_command}",
+	"#{pane_current_path}",
+	"#{@agent}",
+	"#{@worktree}",
+	"#{@branch}",
+	"#{@repo}",
+	"#{pane_pipe}",
+	"#{@agent_state}",
+	"#{@agent_state_at}",
+	"#{pane_dead}",
+	"#{pane_dead_status}",
+	"#{@restarts}",
+	"#{session_created}",
+	"#{@task}",
+	"#{pane_height}",
+	"#{history_size}",
+}, fieldSep)
+
+// Ping checks that the tmux server is up. It returns ErrNoServer when there
+// is none; ListSessions, which reports that as no sessions, can't tell the
+// two apart.
+func Ping(ctx context.Context) error {
+	_, err := run(ctx, "list-sessions", "-F", "#{session_id}")
+	if errors.Is(err, ErrNoServer) {
+		return ErrNoServer
+	}
+	return err
+}
+
+// ListSessions returns all active tmux sessions.
+func ListSessions(ctx context.Context) ([]Session, error) {
+	out, err := run(ctx, "list-sessions", "-F", sessionFormat)
+	if err != nil {
+		// tmux exits non-zero when no sessions exist: the server exits
+		// with its last session.
+		if len(out) == 0 && errors.Is(err, ErrNoServer) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("tmux list-sessions: %w", err)
+	}
+
+	var sessions []Session
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, fieldSep)
+		if len(parts) < 21 {
+			continue
+		}
+		windows, _ := strconv.Atoi(parts[1])
+		attached := hasTerminalClient(parts[2])
+		activitySec, _ := strconv.ParseInt(parts[3], 10, 64)
+		lastUsed := time.Unix(activitySec, 0)
+		restarts, _ := strconv.Atoi(parts[16])
+		createdSec, _ := strconv.ParseInt(parts[17], 10, 64)
+		height, _ := strconv.Atoi(parts[19])
+		history, _ := strconv.Atoi(parts[20])
+
+		sessions = append(sessions, Session{
+			Name:       parts[0],
+			Windows:    windows,
+			Attached:   attached,
+			LastUsed:   lastUsed,
+			Created:    time.Unix(createdSec, 0),
+			Task:       parts[18],
+			PaneHeight: height,
+			History:    history,
+			ActivePane: parts[4],
+			Command:    parts[5],
+			Path:       parts[6],
+			AgentTag:   parts[7],
+			Worktree:   parts[8],
+			Branch:     parts[9],
+			Repo:       parts[10],
+			Piped:      parts[11] == "1",
+			HookState:  parts[12],
+			Dead:       parts[14] == "1",
+			DeadStatus: parts[15],
+			Restarts:   restarts,
+		})
+		if at, err := strconv.ParseInt(parts[13], 10, 64); err == nil {
+			sessions[len(sessions)-1].HookAt = time.Unix(at, 0)
+		}
+	}
+	return sessions, nil
+}
+
+// hasTerminalClient reports whether a session_attached_list names a client
+// with a terminal. Clients without one, such as control-mode watchers, are
+// named "client-<pid>" by tmux.
+func hasTerminalClient(clients string) bool {
+	for _, c := range strings.Split(clients, ",") {
+		if c != "" && !strings.HasPrefix(c, "client-") {
+			return true
+		}
+	}
+	return false
+}
+
+// Window is one window of a session.
+type Window struct {
+	Index   int
+	Name    string
+	Active  bool // the session's current window
+	Panes   int
+	Command string // pane_current_command of the window's active pane
+}
+
+// windowFormat lists the fields fetched by ListWindows.
+var windowFormat = strings.Join([]string{
+	"#{window_index}",
+	"#{window_name}",
+	"#{window_active}",
+	"#{window_panes}",
+	"#{pane_current_command}",
+}, fieldSep)
+
+// ListWindows returns the windows of session in index order.
+func ListWindows(ctx context.Context, session string) ([]Window, error) {
+	out, err := run(ctx, "list-windows", "-t", ExactTarget(session), "-F", windowFormat)
+	if err != nil {
+		return nil, fmt.Errorf("tmux list-windows: %w", err)
+	}
+	var windows []Window
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.Split(line, fieldSep)
+		if len(parts) < 5 {
+			continue
+		}
+		index, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+		panes, _ := strconv.Atoi(parts[3])
+		windows = append(windows, Window{
+			Index:   index,
+			Name:    parts[1],
+			Active:  parts[2] == "1",
+			Panes:   panes,
+			Command: parts[4],
+		})
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Index < windows[j].Index })
+	return windows, nil
+}
+
+// Pane is one pane of a window.
+type Pane struct {
+	Index   int
+	Active  bool // the window's current pane
+	Command string
+	Path    string
+	Width   int
+	Height  int
+}
+
+// paneFormat lists the fields fetched by ListPanes.
+var paneFormat = strings.Join([]string{
+	"#{pane_index}",
+	"#{pane_active}",
+	"#{pane_current_command}",
+	"#{pane_current_path}",
+	"#{pane_width}",
+	"#{pane_height}",
+}, fieldSep)
+
+// ListPanes returns the panes of window index in session, in index order.
+func ListPanes(ctx context.Context, session string, index int) ([]Pane, error) {
+	t := Target{Session: session, Window: strconv.Itoa(index)}
+	out, err := run(ctx, "list-panes", "-t", t.WindowTarget(), "-F", paneFormat)
+	if err != nil {
+		return nil, fmt.Errorf("tmux list-panes: %w", err)
+	}
+	var panes []Pane
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.Split(line, fieldSep)
+		if len(parts) < 6 {
+			continue
+		}
+		index, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+		width, _ := strconv.Atoi(parts[4])
+		height, _ := strconv.Atoi(parts[5])
+		panes = append(panes, Pane{
+			Index:   index,
+			Active:  parts[1] == "1",
+			Command: parts[2],
+			Path:    parts[3],
+			Width:   width,
+			Height:  height,
+		})
+	}
+	sort.Slice(panes, func(i, j int) bool { return panes[i].Index < panes[j].Index })
+	return panes, nil
+}
+
+// CapturePanes returns the last `lines` lines of the active pane in `session`,
+// with colour escape sequences preserved.
+// It tries the active window/pane first, falling back to window 0 pane 0.
+func CapturePanes(ctx context.Context, session string, lines int) (string, error) {
+	return capture(ctx, session, lines, true)
+}
+
+// CaptureText is like CapturePanes but returns plain text without escape
+// sequences, for pattern matching.
+func CaptureText(ctx context.Context, session string, lines int) (string, error) {
+	return capture(ctx, session, lines, false)
+}
+
+func capture(ctx context.Context, session string, lines int, escapes bool) (string, error) {
+	target := ActivePaneTarget(session)
+	args := []string{
+		"capture-pane",
+		"-t", target,
+		"-p",                            // print to stdout
+		"-S", fmt.Sprintf("-%d", lines), // start N lines back
+	}
+	if escapes {
+		args = append(args, "-e") // preserve escape sequences
+	}
+	out, err := run(ctx, args...)
+	if err != nil {
+		// Fall back to explicit 0.0
+		target = Target{Session: session, Window: "0", Pane: "0"}.PaneTarget()
+		args[2] = target
+		out, err = run(ctx, args...)
+		if err != nil {
+			return "", fmt.Errorf("capture-pane: %w", err)
+		}
+	}
+	return string(out), nil
+}
+
+// CaptureWindow returns the last `lines` lines of the active pane of
+// window index in session, with escape sequences preserved.
+func CaptureWindow(ctx context.Context, session string, index, lines int) (string, error) {
+	return captureTarget(ctx, Target{Session: session, Window: strconv.Itoa(index)}.WindowTarget(), lines)
+}
+
+// CapturePane is CaptureWindow for pane pane of window index.
+func CapturePane(ctx context.Context, session string, index, pane, lines int) (string, error) {
+	t := Target{Session: session, Window: strconv.Itoa(index), Pane: strconv.Itoa(pane)}
+	return captureTarget(ctx, t.PaneTarget(), lines)
+}
+
+func captureTarget(ctx context.Context, target string, lines int) (string, error) {
+	out, err := run(ctx, "capture-pane", "-p", "-e", "-t", target, "-S", fmt.Sprintf("-%d", lines))
+	if err != nil {
+		return "", fmt.Errorf("capture-pane: %w", err)
+	}
+	return string(out), nil
+}
+
+// CaptureRange returns lines start through end of the session's active
+// pane, with escape sequences, where 0 is the first visible line and
+// negative numbers reach back into the history. Only the requested lines
+// are transferred, however deep the scrollback.
+func CaptureRange(ctx context.Context, session string, start, end int) (string, error) {
+	out, err := run(ctx, "capture-pane", "-p", "-e", "-t", ActivePaneTarget(session),
+		"-S", strconv.Itoa(start), "-E", strconv.Itoa(end))
+	if err != nil {
+		return "", fmt.Errorf("capture-pane: %w", err)
+	}
+	return string(out), nil
+}
+
+// CaptureHistory returns the whole scrollback of the session's active pane
+// as plain text, with wrapped lines joined. Deep histories are better read
+// with StreamHistory.
+func CaptureHistory(ctx context.Context, session string) (string, error) {
+	out, err := run(ctx, "capture-pane", "-p", "-J", "-S", "-", "-t", ActivePaneTarget(session))
+	if err != nil {
+		return "", fmt.Errorf("capture-pane: %w", err)
+	}
+	return string(out), nil
+}
+
+// CheckName reports whether name can be a session name as given: tmux
+// rejects empty names and silently replaces . and : in others.
+func CheckName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("session name is empty")
+	case strings.ContainsAny(name, ".:"):
+		return fmt.Errorf("session name %q: tmux doesn't allow . or :", name)
+	}
+	return nil
+}
+
+// SafeName replaces the characters tmux doesn't allow in session names
+// with underscores, as tmux itself would.
+func SafeName(name string) string {
+	return strings.NewReplacer(".", "_", ":", "_").Replace(name)
+}
+
+// formatLiteral escapes s for an argument tmux expands as a format, such
+// as a new session name, so a # in it is kept rather than expanded.
+func formatLiteral(s string) string {
+	return strings.ReplaceAll(s, "#", "##")
+}
+
+// RenameSession renames a session.
+func RenameSession(ctx context.Context, old, name string) error {
+	if _, err := run(ctx, "rename-session", "-t", ExactTarget(old), formatLiteral(name)); err != nil {
+		return fmt.Errorf("rename-session: %w", err)
+	}
+	return nil
+}
+
+// KillSession kills the named session.
+func KillSession(ctx context.Context, session string) error {
+	if _, err := run(ctx, "kill-session", "-t", ExactTarget(session)); err != nil {
+		return fmt.Errorf("kill-session: %w", err)
+	}
+	return nil
+}
+
+// SwitchClient switches the current tmux client to `session`.
+// Used when already inside tmux (non-iTerm2).
+func SwitchClient(ctx context.Context, session string) error {
+	_, err := run(ctx, "switch-client", "-t", ExactTarget(session))
+	return err
+}
+
+// Target addresses a session and, optionally, one of its windows and panes,
+// as written in tmux's `session:window.pane` syntax.
+type Target struct {
+	Session string
+	Window  string // window index or name; empty for the active window
+	Pane    string // pane index; empty for the active pane
+}
+
+// ParseTarget splits a `session[:window[.pane]]` string into its parts.
+func ParseTarget(s string) Target {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return Target{Session: s}
+	}
+	t := Target{Session: s[:i], Window: s[i+1:]}
+	if w, p, ok := strings.Cut(t.Window, "."); ok {
+		t.Window, t.Pane = w, p
+	}
+	return t
+}
+
+// ExactTarget addresses the session named exactly name. A bare name is
+// also matched by tmux as a prefix or pattern of another session's name,
+// and read as session:window.pane when it contains a colon or dot.
+func ExactTarget(name string) string {
+	return "=" + name
+}
+
+// ActivePaneTarget addresses the active pane of the session named exactly
+// session, for commands that take a pane or window, and for options.
+func ActivePaneTarget(session string) string {
+	return ExactTarget(session) + ":"
+}
+
+// String formats the target back into tmux target syntax, as a user would
+// write it.
+func (t Target) String() string {
+	s := t.Session
+	if t.Window != "" {
+		s += ":" + t.Window
+		if t.Pane != "" {
+			s += "." + t.Pane
+		}
+	}
+	return s
+}
+
+// WindowTarget returns the `=session:window` part of the target, or ""
+// when no window was given.
+func (t Target) WindowTarget() string {
+	if t.Window == "" {
+		return ""
+	}
+	return ExactTarget(t.Session) + ":" + t.Window
+}
+
+// PaneTarget returns the full `=session:window.pane` target, or "" when no
+// pane was given.
+func (t Target) PaneTarget() string {
+	if t.Window == "" || t.Pane == "" {
+		return ""
+	}
+	return t.WindowTarget() + "." + t.Pane
+}
+
+// SendKeys sends tmux key names (e.g. "Enter", "Escape", "y") to `target`.
+func SendKeys(ctx context.Context, target string, keys ...string) error {
+	_, err := run(ctx, append([]string{"send-keys", "-t", target}, keys...)...)
+	return err
+}
+
+// SendLine types text into target literally, as if at the keyboard, and
+// presses Enter.
+func SendLine(ctx context.Context, target, text string) error {
+	if _, err := run(ctx, "send-keys", "-t", target, "-l", "--", text); err != nil {
+		return fmt.Errorf("send-keys: %w", err)
+	}
+	return SendKeys(ctx, target, "Enter")
+}
+
+// PasteText pastes text into `target` through a tmux buffer using bracketed
+// paste, so embedded newlines are inserted rather than submitted by
+// applications that support it.
+func PasteText(ctx context.Context, target, text string) error {
+	buf := fmt.Sprintf("tmux-nav-%d-%d", os.Getpid(), pastes.Add(1))
+	// set-buffer takes the text as an argument, which Linux caps at 128
+	// KiB, so long text is appended a piece at a time.
+	for i, chunk := range chunks(text, pasteChunk) {
+		args := []string{"set-buffer", "-b", buf, "--", chunk}
+		if i > 0 {
+			args = []string{"set-buffer", "-a", "-b", buf, "--", chunk}
+		}
+		if _, err := run(ctx, args...); err != nil {
+			_, _ = run(ctx, "delete-buffer", "-b", buf)
+			return fmt.Errorf("set-buffer: %w", err)
+		}
+	}
+	if _, err := run(ctx, "paste-buffer", "-p", "-d", "-b", buf, "-t", target); err != nil {
+		return fmt.Errorf("paste-buffer: %w", err)
+	}
+	return nil
+}
+
+// pastes numbers PasteText's buffers, so concurrent pastes don't share one.
+var pastes atomic.Int64
+
+// pasteChunk is the most text PasteText passes to one set-buffer.
+const pasteChunk = 64 << 10
+
+// chunks splits s into pieces of at most n bytes, not splitting runes.
+// The empty string is one empty piece.
+func chunks(s string, n int) []string {
+	var out []string
+	for len(s) > n {
+		i := n
+		for i > 0 && !utf8.RuneStart(s[i]) {
+			i--
+		}
+		out = append(out, s[:i])
+		s = s[i:]
+	}
+	return append(out, s)
+}
+
+// NewSessionOptions describes a session to create.
+type NewSessionOptions struct {
+	Name    string
+	Dir     string            // start directory; empty for tmux's default
+	Command string            // shell command to run; empty for a shell
+	Env     map[string]string // extra environment for the session
+}
+
+// NewSession creates a detached session.
+func NewSession(ctx context.Context, opts NewSessionOptions) error {
+	args := []string{"new-session", "-d", "-s", formatLiteral(opts.Name)}
+	if opts.Dir != "" {
+		args = append(args, "-c", opts.Dir)
+	}
+	keys := make([]string, 0, len(opts.Env))
+	for k := range opts.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "-e", k+"="+opts.Env[k])
+	}
+	if opts.Command != "" {
+		args = append(args, opts.Command)
+	}
+	if _, err := run(ctx, args...); err != nil {
+		return fmt.Errorf("new-session: %w", err)
+	}
+	return nil
+}
+
+// HasSession reports whether a session with exactly this name exists.
+func HasSession(ctx context.Context, name string) bool {
+	_, err := run(ctx, "has-session", "-t", ExactTarget(name))
+	return err == nil
+}
+
+// SetOption sets a session option (typically a user option like @agent).
+func SetOption(ctx context.Context, session, key, value string) error {
+	_, err := run(ctx, "set-option", "-t", ActivePaneTarget(session), key, value)
+	return err
+}
+
+// SetWindowOption sets a window option on every window of session.
+func SetWindowOption(ctx context.Context, session, key, value string) error {
+	_, err := run(ctx, "set-option", "-w", "-t", ActivePaneTarget(session), key, value)
+	return err
+}
+
+// RespawnPane restarts the dead pane at target with the command it was
+// created with.
+func RespawnPane(ctx context.Context, target string) error {
+	if _, err := run(ctx, "respawn-pane", "-t", target); err != nil {
+		return fmt.Errorf("respawn-pane: %w", err)
+	}
+	return nil
+}
+
+// UnsetOption removes a session option.
+func UnsetOption(ctx context.Context, session, key string) error {
+	_, err := run(ctx, "set-option", "-u", "-t", ActivePaneTarget(session), key)
+	return err
+}
+
+// SessionOfPane returns the name of the session containing pane (e.g. the
+// value of $TMUX_PANE).
+func SessionOfPane(ctx context.Context, pane string) (string, error) {
+	out, err := run(ctx, "display-message", "-p", "-t", pane, "#{session_name}")
+	if err != nil {
+		return "", fmt.Errorf("display-message: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// DisplayPopup opens a width×height popup (e.g. "80%") on the current
+// client running the shell command command, closed when it exits.
+func DisplayPopup(ctx context.Context, width, height, command string) error {
+	if _, err := run(ctx, "display-popup", "-E", "-w", width, "-h", height, command); err != nil {
+		return fmt.Errorf("display-popup: %w", err)
+	}
+	return nil
+}
+
+// BindKey binds key, pressed after the prefix, to a tmux command.
+func BindKey(ctx context.Context, key string, command ...string) error {
+	if _, err := run(ctx, append([]string{"bind-key", key}, command...)...); err != nil {
+		return fmt.Errorf("bind-key: %w", err)
+	}
+	return nil
+}
+
+// Signal wakes clients blocked in WaitFor on channel.
+func Signal(ctx context.Context, channel string) error {
+	_, err := run(ctx, "wait-for", "-S", channel)
+	return err
+}
+
+// WaitFor blocks until channel is signalled, ctx is done or timeout
+// elapses, in which case it returns context.DeadlineExceeded. The timeout
+// bounds how long a waiting tmux client can outlive its caller.
+func WaitFor(ctx context.Context, channel string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	// Not run: its Timeout would cut the wait short.
+	_, err := runner.Run(ctx, "wait-for", channel)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return commandError([]string{"wait-for", channel}, "", err)
+}
@@ -0,0 +1,304 @@
+package tmuxclient_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+)
+
+// sessionLine builds a list-sessions output line from its fields, in
+// sessionFormat order.
+func sessionLine(fields ...string) string {
+	return strings.Join(fields, "|^|")
+}
+
+func TestListSessions(t *testing.T) {
+	out := strings.Join([]string{
+		sessionLine("api", "2", "/dev/pts/3,client-4242", "1700000000", "1.0", "claude", "/src/api",
+			"api", "/src/api-wt", "agent/fix", "/src/api", "1",
+			"working", "1700000100", "0", "", "2", "1690000000", "Fix the login bug", "50", "12000"),
+		sessionLine("scratch", "1", "client-4242", "1700000200", "0.0", "zsh", "/tmp",
+			"", "", "", "", "0", "", "", "1", "1", "", "1690000500", "", "24", "0"),
+		"truncated|^|line",
+		"",
+	}, "\n")
+	f := tmuxtest.New().On("list-sessions", out, nil)
+	tmuxtest.Install(t, f)
+
+	got, err := tmuxclient.ListSessions(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []tmuxclient.Session{
+		{
+			Name: "api", Windows: 2, Attached: true,
+			LastUsed: time.Unix(1700000000, 0), Created: time.Unix(1690000000, 0),
+			ActivePane: "1.0", Command: "claude", Path: "/src/api",
+			AgentTag: "api", Task: "Fix the login bug",
+			Worktree: "/src/api-wt", Branch: "agent/fix", Repo: "/src/api",
+			Piped: true, HookState: "working", HookAt: time.Unix(1700000100, 0),
+			Restarts: 2, PaneHeight: 50, History: 12000,
+		},
+		{
+			Name: "scratch", Windows: 1,
+			LastUsed: time.Unix(1700000200, 0), Created: time.Unix(1690000500, 0),
+			ActivePane: "0.0", Command: "zsh", Path: "/tmp",
+			Dead: true, DeadStatus: "1", PaneHeight: 24,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListSessions() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestListSessionsNoServer(t *testing.T) {
+	tmuxtest.Install(t, tmuxtest.New().On("list-sessions", "", errors.New("no server running")))
+	got, err := tmuxclient.ListSessions(t.Context())
+	if err != nil || got != nil {
+		t.Errorf("ListSessions() = %v, %v; want no sessions and no error", got, err)
+	}
+}
+
+func TestListSessionsError(t *testing.T) {
+	tmuxtest.Install(t, tmuxtest.New().On("list-sessions", "partial", errors.New("exit status 1")))
+	if _, err := tmuxclient.ListSessions(t.Context()); err == nil || !strings.Contains(err.Error(), "list-sessions") {
+		t.Errorf("ListSessions() error = %v, want a list-sessions error", err)
+	}
+}
+
+// hungRunner is a tmux server that never answers.
+type hungRunner struct{}
+
+func (hungRunner) Run(ctx context.Context, args ...string) ([]byte, error) {
+	<-ctx.Done()
+	return nil, errors.New("signal: killed")
+}
+
+func TestListSessionsGivesUpOnHungServer(t *testing.T) {
+	prev := tmuxclient.SetRunner(hungRunner{})
+	t.Cleanup(func() { tmuxclient.SetRunner(prev) })
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := tmuxclient.ListSessions(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ListSessions() error = %v, want it to time out", err)
+	}
+}
+
+func TestListWindows(t *testing.T) {
+	out := strings.Join([]string{
+		sessionLine("2", "logs", "0", "1", "tail"),
+		sessionLine("0", "editor", "1", "3", "nvim"),
+		"bogus",
+	}, "\n")
+	f := tmuxtest.New().On("list-windows", out, nil)
+	tmuxtest.Install(t, f)
+
+	got, err := tmuxclient.ListWindows(t.Context(), "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []tmuxclient.Window{
+		{Index: 0, Name: "editor", Active: true, Panes: 3, Command: "nvim"},
+		{Index: 2, Name: "logs", Panes: 1, Command: "tail"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListWindows() = %+v, want %+v", got, want)
+	}
+	if call := f.Calls()[0]; call[2] != "=api" {
+		t.Errorf("call = %q, want the exact session targeted", call)
+	}
+}
+
+func TestListPanes(t *testing.T) {
+	out := strings.Join([]string{
+		sessionLine("1", "1", "claude", "/src/api", "80", "24"),
+		sessionLine("0", "0", "zsh", "/src/api/web", "80", "12"),
+	}, "\n")
+	f := tmuxtest.New().On("list-panes", out, nil)
+	tmuxtest.Install(t, f)
+
+	got, err := tmuxclient.ListPanes(t.Context(), "api", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []tmuxclient.Pane{
+		{Index: 0, Command: "zsh", Path: "/src/api/web", Width: 80, Height: 12},
+		{Index: 1, Active: true, Command: "claude", Path: "/src/api", Width: 80, Height: 24},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListPanes() = %+v, want %+v", got, want)
+	}
+	if call := f.Calls()[0]; call[2] != "=api:2" {
+		t.Errorf("call = %q, want window 2 of the exact session targeted", call)
+	}
+}
+
+func TestCapturePanesFallsBackToFirstPane(t *testing.T) {
+	f := tmuxtest.New().
+		On("capture-pane", "", errors.New("can't find window")).
+		On("capture-pane", "hello\n", nil)
+	tmuxtest.Install(t, f)
+
+	got, err := tmuxclient.CapturePanes(t.Context(), "api", 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "hello\n" {
+		t.Errorf("CapturePanes() = %q, want %q", got, "hello\n")
+	}
+	calls := f.Calls()
+	want := [][]string{
+		{"capture-pane", "-t", "=api:", "-p", "-S", "-40", "-e"},
+		{"capture-pane", "-t", "=api:0.0", "-p", "-S", "-40", "-e"},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestCaptureTextError(t *testing.T) {
+	tmuxtest.Install(t, tmuxtest.New().On("capture-pane", "", errors.New("no such session")))
+	_, err := tmuxclient.CaptureText(t.Context(), "gone", 10)
+	if err == nil || !strings.Contains(err.Error(), "no such session") {
+		t.Errorf("CaptureText() error = %v, want the tmux error", err)
+	}
+}
+
+func TestKillSession(t *testing.T) {
+	f := tmuxtest.New().
+		On("kill-session", "", nil).
+		On("kill-session", "", errors.New("can't find session: gone"))
+	tmuxtest.Install(t, f)
+
+	if err := tmuxclient.KillSession(t.Context(), "api"); err != nil {
+		t.Errorf("KillSession(api) = %v", err)
+	}
+	err := tmuxclient.KillSession(t.Context(), "gone")
+	if err == nil || !strings.Contains(err.Error(), "kill-session: can't find session") {
+		t.Errorf("KillSession(gone) = %v, want a wrapped kill-session error", err)
+	}
+	if got := f.Calls()[0]; !reflect.DeepEqual(got, []string{"kill-session", "-t", "=api"}) {
+		t.Errorf("first call = %q", got)
+	}
+}
+
+func TestTargetsKeepDotsAndColonsInNames(t *testing.T) {
+	tests := []struct{ got, want string }{
+		{tmuxclient.ExactTarget("v1.2"), "=v1.2"},
+		{tmuxclient.ActivePaneTarget("v1.2"), "=v1.2:"},
+		{tmuxclient.Target{Session: "v1.2", Window: "3"}.WindowTarget(), "=v1.2:3"},
+		{tmuxclient.Target{Session: "v1.2", Window: "3", Pane: "1"}.PaneTarget(), "=v1.2:3.1"},
+		{tmuxclient.Target{Session: "v1.2", Window: "3", Pane: "1"}.String(), "v1.2:3.1"},
+		{tmuxclient.SafeName("api/release-1.2:rc"), "api/release-1_2_rc"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("target = %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestRenameSessionTargetsExactName(t *testing.T) {
+	f := tmuxtest.New().On("rename-session", "", nil)
+	tmuxtest.Install(t, f)
+	if err := tmuxclient.RenameSession(t.Context(), "old", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Calls()[0]; !reflect.DeepEqual(got, []string{"rename-session", "-t", "=old", "new"}) {
+		t.Errorf("call = %q", got)
+	}
+	// tmux expands the new name as a format; ## keeps a #.
+	if err := tmuxclient.RenameSession(t.Context(), "new", "fix#12"); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Calls()[1]; !reflect.DeepEqual(got, []string{"rename-session", "-t", "=new", "fix##12"}) {
+		t.Errorf("call = %q", got)
+	}
+}
+
+func TestSendLineTypesLiterally(t *testing.T) {
+	f := tmuxtest.New().On("send-keys", "", nil).On("send-keys", "", nil)
+	tmuxtest.Install(t, f)
+	if err := tmuxclient.SendLine(t.Context(), "=api:", "-n 3; Enter"); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"send-keys", "-t", "=api:", "-l", "--", "-n 3; Enter"},
+		{"send-keys", "-t", "=api:", "Enter"},
+	}
+	if got := f.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %q, want %q", got, want)
+	}
+}
+
+func TestBindKeyPassesCommandThrough(t *testing.T) {
+	f := tmuxtest.New().On("bind-key", "", nil)
+	tmuxtest.Install(t, f)
+	if err := tmuxclient.BindKey(t.Context(), "S", "display-popup", "-E", "tmux-nav popup --inside"); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"bind-key", "S", "display-popup", "-E", "tmux-nav popup --inside"}}
+	if got := f.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls = %q, want %q", got, want)
+	}
+}
+
+func TestPasteTextThroughBuffer(t *testing.T) {
+	f := tmuxtest.New().
+		On("set-buffer", "", nil).
+		On("paste-buffer", "", nil).
+		On("paste-buffer", "", errors.New("can't find session: gone"))
+	tmuxtest.Install(t, f)
+
+	text := strings.Repeat("é", 40<<10) + "\n-end"
+	if err := tmuxclient.PasteText(t.Context(), "=api:", text); err != nil {
+		t.Fatal(err)
+	}
+	calls := f.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("calls = %d, want two set-buffers and a paste", len(calls))
+	}
+	buf := calls[0][2]
+	first, second := calls[0], calls[1]
+	if !reflect.DeepEqual(first[:4], []string{"set-buffer", "-b", buf, "--"}) ||
+		!reflect.DeepEqual(second[:5], []string{"set-buffer", "-a", "-b", buf, "--"}) {
+		t.Errorf("set-buffer calls = %q, %q", first[:4], second[:5])
+	}
+	if got := first[4] + second[5]; got != text || !utf8.ValidString(first[4]) {
+		t.Error("set-buffer pieces don't add up to the text, split on rune boundaries")
+	}
+	if want := []string{"paste-buffer", "-p", "-d", "-b", buf, "-t", "=api:"}; !reflect.DeepEqual(calls[2], want) {
+		t.Errorf("paste call = %q, want %q", calls[2], want)
+	}
+
+	if err := tmuxclient.PasteText(t.Context(), "=gone:", "x"); !errors.Is(err, tmuxclient.ErrSessionNotFound) {
+		t.Errorf("PasteText(gone) = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	f := tmuxtest.New().On("wait-for", "", nil).On("wait-for", "", errors.New("no server running"))
+	tmuxtest.Install(t, f)
+	if err := tmuxclient.WaitFor(t.Context(), "tmux-nav-event", time.Second); err != nil {
+		t.Errorf("WaitFor() = %v", err)
+	}
+	if err := tmuxclient.WaitFor(t.Context(), "tmux-nav-event", time.Second); !errors.Is(err, tmuxclient.ErrNoServer) {
+		t.Errorf("WaitFor() = %v, want ErrNoServer", err)
+	}
+	if got := f.Calls()[0]; !reflect.DeepEqual(got, []string{"wait-for", "tmux-nav-event"}) {
+		t.Errorf("call = %q", got)
+	}
+
+	prev := tmuxclient.SetRunner(hungRunner{})
+	t.Cleanup(func() { tmuxclient.SetRunner(prev) })
+	if err := tmuxclient.WaitFor(t.Context(), "tmux-nav-event", 10*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitFor() = %v, want it to time out", err)
+	}
+}
@@ -0,0 +1,297 @@
+package tmuxclient
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Notification is an asynchronous control-mode notification, e.g.
+// "%sessions-changed" or "%session-renamed $2 api".
+type Notification struct {
+	Name string   // without the leading "%", e.g. "sessions-changed"
+	Args []string // space-separated arguments
+}
+
+// ChangesSessions reports whether a notification means the session list
+// (sessions, their names or windows) changed.
+func (n Notification) ChangesSessions() bool {
+	switch n.Name {
+	case "sessions-changed", "session-renamed", "session-changed", "client-session-changed",
+		"window-add", "unlinked-window-add", "window-close", "unlinked-window-close",
+		"window-renamed", "unlinked-window-renamed", "session-window-changed":
+		return true
+	}
+	return false
+}
+
+// ChangesPanes reports whether a notification means a window's panes
+// changed: one was split off, closed or selected.
+func (n Notification) ChangesPanes() bool {
+	switch n.Name {
+	case "layout-change", "window-pane-changed":
+		return true
+	}
+	return false
+}
+
+// errConnClosed is returned by Conn.Run once the connection has ended.
+var errConnClosed = errors.New("control mode: connection closed")
+
+// Conn is a persistent control-mode client (tmux -C). While one is open,
+// every command this package runs goes through it instead of forking a
+// tmux process per call, which dominates the cost of a refresh with many
+// sessions: measured on a 40-session server, list-sessions is about three
+// times and capture-pane about twenty times faster over the connection
+// (see BenchmarkListSessions and BenchmarkCapture; run them with
+// `go test ./tmuxclient -run - -bench .`). Commands fall back to a process
+// whenever the connection can't carry them, and commands that act on the
+// client running them (switch-client, display-popup, …) always run as a
+// process: over the connection they would act on its hidden client rather
+// than the user's terminal.
+//
+// The client attaches to the first session without pane output or a say
+// in window sizes, and doesn't count as attaching it (see
+// Session.Attached).
+type Conn struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	mu      sync.Mutex // serialises commands: replies come back in order
+	replies chan reply
+	notes   chan Notification
+	done    chan struct{}
+}
+
+type reply struct {
+	out []byte
+	err error
+}
+
+var (
+	connMu sync.Mutex
+	active *Conn // the connection commands are routed through
+)
+
+// Connect opens a control-mode connection to the server and routes this
+// package's commands through it until it closes, when ctx is cancelled or
+// the attached session goes away. With no server running, Connect fails
+// and commands keep running as separate processes.
+func Connect(ctx context.Context) (*Conn, error) {
+	sessions, err := ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, errors.New("control mode: no sessions to attach to")
+	}
+	cmd := exec.CommandContext(ctx, "tmux", "-C", "attach-session", "-t", ExactTarget(sessions[0].Name),
+		"-f", "no-output,ignore-size")
+	// Control clients exit when their stdin closes; keep it open.
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("control mode: %w", err)
+	}
+	c := &Conn{
+		cmd:     cmd,
+		stdin:   stdin,
+		replies: make(chan reply, 1),
+		notes:   make(chan Notification, 64),
+		done:    make(chan struct{}),
+	}
+	go c.read(stdout)
+
+	connMu.Lock()
+	if active == nil || active.Closed() {
+		active = c
+	}
+	connMu.Unlock()
+	return c, nil
+}
+
+// Watch opens a connection and returns its notifications; see Connect.
+func Watch(ctx context.Context) (<-chan Notification, error) {
+	c, err := Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.Notifications(), nil
+}
+
+// Notifications streams the connection's notifications until it closes.
+// Notifications nobody reads in time are dropped; they are hints to
+// refresh, not a record.
+func (c *Conn) Notifications() <-chan Notification {
+	return c.notes
+}
+
+// Close ends the connection.
+func (c *Conn) Close() error {
+	c.stdin.Close()
+	<-c.done
+	return nil
+}
+
+// Closed reports whether the connection has ended.
+func (c *Conn) Closed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// read demultiplexes the client's output: command replies, framed by
+// %begin and %end (or %error), and notifications, which never appear
+// inside a reply.
+func (c *Conn) read(stdout io.Reader) {
+	defer c.cmd.Wait()
+	defer close(c.notes)
+	defer close(c.done)
+
+	sc := bufio.NewScanner(stdout)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var (
+		body   []string
+		inBody bool
+		ours   bool // the reply is to one of our commands, not to attaching
+	)
+	for sc.Scan() {
+		line := sc.Text()
+		if inBody {
+			end, isErr := strings.HasPrefix(line, "%end "), strings.HasPrefix(line, "%error ")
+			if !end && !isErr {
+				body = append(body, line)
+				continue
+			}
+			inBody = false
+			if !ours {
+				continue
+			}
+			r := reply{out: []byte(strings.Join(body, "\n"))}
+			if len(body) > 0 {
+				r.out = append(r.out, '\n')
+			}
+			if isErr {
+				r = reply{err: &Error{Stderr: strings.TrimSpace(string(r.out))}}
+			}
+			c.replies <- r
+			continue
+		}
+		if strings.HasPrefix(line, "%begin ") {
+			// "%begin <time> <number> <flags>": flags is 1 for commands
+			// sent by this client.
+			fields := strings.Fields(line)
+			inBody, ours, body = true, len(fields) == 4 && fields[3] == "1", nil
+			continue
+		}
+		if !strings.HasPrefix(line, "%") {
+			continue
+		}
+		fields := strings.Fields(line[1:])
+		n := Notification{Name: fields[0], Args: fields[1:]}
+		if n.Name == "exit" {
+			return
+		}
+		if n.ChangesSessions() {
+			cache.invalidate("")
+		}
+		select {
+		case c.notes <- n:
+		default:
+		}
+	}
+}
+
+// Run sends a command over the connection and waits for its reply. If
+// ctx is done first, the connection is closed, as the reply would
+// otherwise answer the next command.
+func (c *Conn) Run(ctx context.Context, args ...string) ([]byte, error) {
+	line, ok := commandLine(args)
+	if !ok || len(args) > 0 && clientCommands[args[0]] {
+		return nil, errUnsendable
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Closed() {
+		return nil, errConnClosed
+	}
+	if _, err := io.WriteString(c.stdin, line+"\n"); err != nil {
+		return nil, errConnClosed
+	}
+	select {
+	case r := <-c.replies:
+		if e, ok := r.err.(*Error); ok {
+			e.Command = args[0]
+		}
+		return r.out, r.err
+	case <-c.done:
+		return nil, errConnClosed
+	case <-ctx.Done():
+		c.cmd.Process.Kill()
+		<-c.done
+		return nil, ctx.Err()
+	}
+}
+
+// errUnsendable is returned for commands a control-mode line can't carry,
+// or shouldn't.
+var errUnsendable = errors.New("control mode: command can't be sent as one line")
+
+// clientCommands act on the client that runs them, or hold it up.
+var clientCommands = map[string]bool{
+	"switch-client":  true,
+	"display-popup":  true,
+	"display-menu":   true,
+	"detach-client":  true,
+	"refresh-client": true,
+	"wait-for":       true,
+}
+
+// commandLine quotes args as a tmux command line. Arguments containing
+// newlines can't be sent.
+func commandLine(args []string) (string, bool) {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if strings.ContainsAny(a, "\n\r") {
+			return "", false
+		}
+		quoted[i] = quoteArg(a)
+	}
+	return strings.Join(quoted, " "), true
+}
+
+// quoteArg single-quotes an argument unless it is made only of characters
+// tmux's parser takes literally. Single quotes suppress the parser's
+// expansions (~, $VAR, ;, # comments), and an embedded quote is closed,
+// escaped and reopened. They don't stop a command that takes a format from
+// expanding #{…} in it; see formatLiteral.
+func quoteArg(a string) string {
+	if a != "" && strings.Trim(a, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-./:=@,+%") == "" {
+		return a
+	}
+	return "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+}
+
+// activeConn returns the open connection commands are routed through.
+func activeConn() *Conn {
+	connMu.Lock()
+	defer connMu.Unlock()
+	if active != nil && active.Closed() {
+		active = nil
+	}
+	return active
+}
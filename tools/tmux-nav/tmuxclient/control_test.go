@@ -0,0 +1,44 @@
+package tmuxclient_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+func TestClientCommandsBypassConnection(t *testing.T) {
+	real, err := exec.LookPath("tmux")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	// A tmux on $PATH that logs each process started.
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	script := "#!/bin/sh\necho \"$*\" >> " + log + "\nexec " + real + " \"$@\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "tmux"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	benchServer(t, 2)
+	connect(t)
+	if err := os.Truncate(log, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = tmuxclient.ListSessions(t.Context())
+	_ = tmuxclient.SwitchClient(t.Context(), "bench-01")
+	b, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "list-sessions") {
+		t.Errorf("list-sessions ran as a process with a connection open:\n%s", b)
+	}
+	if !strings.Contains(string(b), "switch-client") {
+		t.Errorf("switch-client didn't run as a process; it went to the control client:\n%s", b)
+	}
+}
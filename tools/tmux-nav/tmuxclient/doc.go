@@ -0,0 +1,25 @@
+// Package tmuxclient lists, captures and drives tmux sessions: session
+// metadata (including the agent options tmux-nav sets), pane captures,
+// keys and pasted text, session lifecycle, and control-mode connections
+// that carry commands and change notifications.
+//
+//	sessions, err := tmuxclient.ListSessions(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	for _, s := range sessions {
+//		out, _ := tmuxclient.CapturePanes(ctx, s.Name, 20)
+//		fmt.Println(s.Name, s.Attached, len(out))
+//	}
+//
+// Every command gives up when its context is done, and after Timeout
+// whatever the context, so a hung server can't block its callers for
+// good. Commands go through a Runner; see SetRunner, and package tmuxtest
+// for a scripted fake.
+//
+// Stability: tmuxclient, attach and navui are the module's public API and
+// follow semantic versioning. Within a major version their exported
+// identifiers are only added to, never removed or changed incompatibly.
+// The module's other packages exist to serve the tmux-nav command under
+// cmd/ and may change in any release.
+package tmuxclient
@@ -0,0 +1,89 @@
+package tmuxclient
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// The failures callers tell apart. Errors of this package wrap them, so
+// errors.Is reports them whichever function failed.
+var (
+	// ErrNoServer reports that no tmux server is running on the socket,
+	// as when it was killed or hasn't been started.
+	ErrNoServer = errors.New("no tmux server running")
+	// ErrSessionNotFound reports that the session addressed doesn't exist.
+	ErrSessionNotFound = errors.New("no such tmux session")
+	// ErrTmuxNotInstalled reports that there is no tmux binary on $PATH.
+	ErrTmuxNotInstalled = errors.New("tmux is not installed")
+)
+
+// Error is a failed tmux command.
+type Error struct {
+	Command string // the tmux command, e.g. "kill-session"
+	Stderr  string // what tmux printed on stderr, if anything
+	Err     error  // how it failed: an exit status, a timeout; nil if only stderr tells
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Stderr
+	case e.Stderr == "":
+		return e.Err.Error()
+	}
+	return e.Err.Error() + ": " + e.Stderr
+}
+
+// Unwrap returns how the command failed and, when tmux's message says
+// why, ErrNoServer, ErrSessionNotFound or ErrTmuxNotInstalled.
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if k := e.kind(); k != nil {
+		errs = append(errs, k)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// failureKinds map what tmux prints to the failures they mean.
+var failureKinds = []struct {
+	text string
+	kind error
+}{
+	{"no server running", ErrNoServer},
+	{"error connecting to", ErrNoServer},
+	{"server exited", ErrNoServer},
+	{"lost server", ErrNoServer},
+	{"can't find session", ErrSessionNotFound},
+	{"session not found", ErrSessionNotFound},
+}
+
+func (e *Error) kind() error {
+	if errors.Is(e.Err, exec.ErrNotFound) {
+		return ErrTmuxNotInstalled
+	}
+	msg := e.Error()
+	for _, f := range failureKinds {
+		if strings.Contains(msg, f.text) {
+			return f.kind
+		}
+	}
+	return nil
+}
+
+// commandError makes err, returned by running the tmux command args, an
+// *Error unless it is one already.
+func commandError(args []string, stderr string, err error) error {
+	var te *Error
+	if err == nil || errors.As(err, &te) {
+		return err
+	}
+	cmd := ""
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+	return &Error{Command: cmd, Stderr: strings.TrimSpace(stderr), Err: err}
+}
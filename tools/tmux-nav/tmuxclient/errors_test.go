@@ -0,0 +1,35 @@
+package tmuxclient_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+)
+
+func TestErrorsSayWhy(t *testing.T) {
+	tmuxtest.Install(t, tmuxtest.New().
+		On("kill-session", "", errors.New("can't find session: gone")).
+		On("rename-session", "", errors.New("no server running on /tmp/tmux-1000/default")))
+
+	err := tmuxclient.KillSession(t.Context(), "gone")
+	if !errors.Is(err, tmuxclient.ErrSessionNotFound) || !strings.Contains(err.Error(), "can't find session: gone") {
+		t.Errorf("KillSession() error = %v, want ErrSessionNotFound with tmux's message", err)
+	}
+	var te *tmuxclient.Error
+	if !errors.As(err, &te) || te.Command != "kill-session" {
+		t.Errorf("KillSession() error = %#v, want an *Error for kill-session", err)
+	}
+	if err := tmuxclient.RenameSession(t.Context(), "a", "b"); !errors.Is(err, tmuxclient.ErrNoServer) {
+		t.Errorf("RenameSession() error = %v, want ErrNoServer", err)
+	}
+}
+
+func TestTmuxNotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if _, err := tmuxclient.ListSessions(t.Context()); !errors.Is(err, tmuxclient.ErrTmuxNotInstalled) {
+		t.Errorf("ListSessions() error = %v, want ErrTmuxNotInstalled", err)
+	}
+}
@@ -0,0 +1,95 @@
+package tmuxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrPiped reports that a pane already has a pipe-pane open, such as a
+// capture; tmux allows one per pane.
+var ErrPiped = errors.New("pane is already piped")
+
+// followPoll is how often a follower checks for new output once it has
+// read everything written so far.
+const followPoll = 50 * time.Millisecond
+
+// Follow streams what the session's active pane writes from now on, as
+// the program wrote it (escape sequences included), until the returned
+// reader is closed. It pipes the pane (pipe-pane) into a temporary file
+// and tails it, so it fails with ErrPiped when the pane is already piped.
+func Follow(ctx context.Context, session string) (io.ReadCloser, error) {
+	target := ActivePaneTarget(session)
+	out, err := run(ctx, "display-message", "-p", "-t", target, "#{pane_pipe}")
+	if err != nil {
+		return nil, fmt.Errorf("display-message: %w", err)
+	}
+	if strings.TrimSpace(string(out)) == "1" {
+		return nil, ErrPiped
+	}
+	dir, err := os.MkdirTemp("", "tmux-nav-follow-")
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, "out")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o600)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	cmd := "cat >> '" + strings.ReplaceAll(path, "'", `'\''`) + "'"
+	if _, err := run(ctx, "pipe-pane", "-t", target, cmd); err != nil {
+		f.Close()
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("pipe-pane: %w", err)
+	}
+	return &follower{target: target, dir: dir, f: f, closed: make(chan struct{})}, nil
+}
+
+// follower tails the file a pane is piped into.
+type follower struct {
+	target string
+	dir    string
+	f      *os.File
+	once   sync.Once
+	closed chan struct{}
+}
+
+// Read blocks until the pane writes something or the follower is closed,
+// when it returns io.EOF.
+func (fl *follower) Read(b []byte) (int, error) {
+	for {
+		n, err := fl.f.Read(b)
+		if errors.Is(err, os.ErrClosed) {
+			return n, io.EOF
+		}
+		if n > 0 || (err != nil && err != io.EOF) {
+			return n, err
+		}
+		select {
+		case <-fl.closed:
+			return 0, io.EOF
+		case <-time.After(followPoll):
+		}
+	}
+}
+
+// Close closes the pane's pipe and removes the file.
+func (fl *follower) Close() error {
+	var err error
+	fl.once.Do(func() {
+		close(fl.closed)
+		if _, perr := run(context.Background(), "pipe-pane", "-t", fl.target); perr != nil {
+			err = fmt.Errorf("pipe-pane: %w", perr)
+		}
+		fl.f.Close()
+		os.RemoveAll(fl.dir)
+	})
+	return err
+}
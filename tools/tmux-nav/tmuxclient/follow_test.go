@@ -0,0 +1,53 @@
+package tmuxclient_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+)
+
+func TestFollow(t *testing.T) {
+	f := tmuxtest.New().
+		On("display-message", "0\n", nil).
+		On("pipe-pane", "", nil)
+	tmuxtest.Install(t, f)
+
+	r, err := tmuxclient.Follow(t.Context(), "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipe := f.Calls()[1]
+	path := strings.TrimSuffix(strings.TrimPrefix(pipe[len(pipe)-1], "cat >> '"), "'")
+	if err := os.WriteFile(path, []byte("hello\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 64)
+	n, err := r.Read(buf)
+	if err != nil || string(buf[:n]) != "hello\n" {
+		t.Errorf("Read() = %q, %v; want the pane's output", buf[:n], err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(buf); err != io.EOF {
+		t.Errorf("Read() after Close = %v, want io.EOF", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("the piped file outlived Close: %v", err)
+	}
+	if last := f.Calls()[2]; len(last) != 3 || last[0] != "pipe-pane" {
+		t.Errorf("Close ran %q, want the pipe closed", last)
+	}
+}
+
+func TestFollowPipedPane(t *testing.T) {
+	tmuxtest.Install(t, tmuxtest.New().On("display-message", "1\n", nil))
+	if _, err := tmuxclient.Follow(t.Context(), "api"); !errors.Is(err, tmuxclient.ErrPiped) {
+		t.Errorf("Follow() error = %v, want ErrPiped", err)
+	}
+}
@@ -0,0 +1,32 @@
+package tmuxclient
+
+import "time"
+
+// Record is the JSON shape of a Session for scripts and dashboards: what
+// tmux knows about it, without tmux-nav's bookkeeping options.
+type Record struct {
+	Name       string    `json:"name"`
+	Windows    int       `json:"windows"`
+	Attached   bool      `json:"attached"`
+	LastUsed   time.Time `json:"last_used"`
+	ActivePane string    `json:"active_pane,omitempty"`
+	Command    string    `json:"command,omitempty"`
+	Cwd        string    `json:"cwd,omitempty"`
+	Worktree   string    `json:"worktree,omitempty"`
+	Branch     string    `json:"branch,omitempty"`
+}
+
+// Record returns s as a Record.
+func (s Session) Record() Record {
+	return Record{
+		Name:       s.Name,
+		Windows:    s.Windows,
+		Attached:   s.Attached,
+		LastUsed:   s.LastUsed,
+		ActivePane: s.ActivePane,
+		Command:    s.Command,
+		Cwd:        s.Path,
+		Worktree:   s.Worktree,
+		Branch:     s.Branch,
+	}
+}
@@ -0,0 +1,54 @@
+package tmuxclient
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"time"
+)
+
+// Runner runs tmux commands. The default runs the tmux binary; tests swap
+// in a scripted fake (see package tmuxtest) to exercise parsing and error
+// handling without a tmux server.
+type Runner interface {
+	// Run runs tmux with args and returns its standard output. A failed
+	// command's error carries what tmux printed on stderr, as an *Error
+	// if the runner likes. It gives up when ctx is done.
+	Run(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// defaultRunner sends commands over the open control-mode connection, if
+// any, and otherwise runs a tmux process per command.
+type defaultRunner struct{}
+
+func (defaultRunner) Run(ctx context.Context, args ...string) ([]byte, error) {
+	if c := activeConn(); c != nil {
+		out, err := c.Run(ctx, args...)
+		if err != errConnClosed && err != errUnsendable {
+			return out, err
+		}
+	}
+	cmd := exec.CommandContext(ctx, "tmux", args...)
+	cmd.WaitDelay = waitDelay
+	out, err := cmd.Output()
+	var stderr string
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		stderr = string(ee.Stderr)
+	}
+	return out, commandError(args, stderr, err)
+}
+
+var runner Runner = defaultRunner{}
+
+// waitDelay is how long a killed tmux process's output is waited for.
+const waitDelay = time.Second
+
+// SetRunner replaces the runner tmux commands go through and returns the
+// previous one. Cached output from the previous runner is dropped.
+func SetRunner(r Runner) Runner {
+	prev := runner
+	runner = r
+	cache.invalidate("")
+	return prev
+}
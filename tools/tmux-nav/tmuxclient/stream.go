@@ -0,0 +1,139 @@
+package tmuxclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+// maxLine bounds a single scrollback line when scanning history; joined
+// wrapped lines of a chatty program can run long, but not this long.
+const maxLine = 4 << 20
+
+// Streamer is implemented by runners that can hand over a command's
+// output as it is produced instead of all at once. Runners that don't
+// are read in full and streamed from memory.
+type Streamer interface {
+	// Stream runs tmux with args and returns its standard output. A
+	// failed command's error, with what tmux printed on stderr, is
+	// returned by the read that would otherwise return io.EOF. The
+	// command is stopped when ctx is done.
+	Stream(ctx context.Context, args ...string) (io.ReadCloser, error)
+}
+
+// stream runs a tmux command through the current runner, bypassing the
+// cache: streamed output is too large to keep around.
+func stream(ctx context.Context, args ...string) (io.ReadCloser, error) {
+	if s, ok := runner.(Streamer); ok {
+		return s.Stream(ctx, args...)
+	}
+	out, err := runner.Run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(out)), nil
+}
+
+// Stream always runs a tmux process, even with a control-mode connection
+// open: control mode buffers a command's whole output before handing it
+// over.
+func (defaultRunner) Stream(ctx context.Context, args ...string) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, "tmux", args...)
+	p := &procReader{cmd: cmd}
+	cmd.Stderr = &p.stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, commandError(args, "", err)
+	}
+	p.out = out
+	return p, nil
+}
+
+// procReader reads a tmux process's output and reaps it at the end.
+type procReader struct {
+	cmd    *exec.Cmd
+	out    io.ReadCloser
+	stderr bytes.Buffer
+	done   bool
+}
+
+func (p *procReader) Read(b []byte) (int, error) {
+	n, err := p.out.Read(b)
+	if err == io.EOF && !p.done {
+		p.done = true
+		if werr := p.cmd.Wait(); werr != nil {
+			return n, commandError(p.cmd.Args[1:], p.stderr.String(), werr)
+		}
+	}
+	return n, err
+}
+
+// Close stops the process if its output wasn't read to the end.
+func (p *procReader) Close() error {
+	if p.done {
+		return nil
+	}
+	p.done = true
+	p.cmd.Process.Kill()
+	p.cmd.Wait()
+	return nil
+}
+
+// StreamHistory returns the whole scrollback of the session's active pane
+// as plain text, with wrapped lines joined, as it is read from tmux.
+// Unlike CaptureHistory it never holds the history in memory, however
+// deep it is. The caller must close it.
+func StreamHistory(ctx context.Context, session string) (io.ReadCloser, error) {
+	return streamHistory(ctx, ActivePaneTarget(session))
+}
+
+// StreamPaneHistory is StreamHistory for pane pane of window index.
+func StreamPaneHistory(ctx context.Context, session string, index, pane int) (io.ReadCloser, error) {
+	return streamHistory(ctx, Target{Session: session, Window: strconv.Itoa(index), Pane: strconv.Itoa(pane)}.PaneTarget())
+}
+
+func streamHistory(ctx context.Context, target string) (io.ReadCloser, error) {
+	r, err := stream(ctx, "capture-pane", "-p", "-J", "-S", "-", "-t", target)
+	if err != nil {
+		return nil, fmt.Errorf("capture-pane: %w", err)
+	}
+	return r, nil
+}
+
+// Match is a scrollback line matching a search.
+type Match struct {
+	Line int // 1-based, counting from the oldest line in the history
+	Text string
+}
+
+// SearchHistory streams the session's scrollback and calls fn with each
+// line matching re, oldest first. It stops early, returning fn's error,
+// if fn fails.
+func SearchHistory(ctx context.Context, session string, re *regexp.Regexp, fn func(Match) error) error {
+	r, err := StreamHistory(ctx, session)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxLine)
+	for n := 1; sc.Scan(); n++ {
+		if re.Match(sc.Bytes()) {
+			if err := fn(Match{Line: n, Text: sc.Text()}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("capture-pane: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package tmuxclient_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+)
+
+func TestStreamHistory(t *testing.T) {
+	f := tmuxtest.New().On("capture-pane", "one\ntwo\n", nil)
+	tmuxtest.Install(t, f)
+	r, err := tmuxclient.StreamHistory(t.Context(), "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil || string(got) != "one\ntwo\n" {
+		t.Errorf("StreamHistory = %q, %v", got, err)
+	}
+	want := []string{"capture-pane", "-p", "-J", "-S", "-", "-t", "=api:"}
+	if call := f.Calls()[0]; !reflect.DeepEqual(call, want) {
+		t.Errorf("call = %q, want %q", call, want)
+	}
+}
+
+func TestSearchHistory(t *testing.T) {
+	history := "build ok\nerror: disk full\n" + strings.Repeat("x", 100<<10) + "\nerror: retry\n"
+	tmuxtest.Install(t, tmuxtest.New().On("capture-pane", history, nil))
+	var got []tmuxclient.Match
+	err := tmuxclient.SearchHistory(t.Context(), "api", regexp.MustCompile(`^error:`), func(m tmuxclient.Match) error {
+		got = append(got, m)
+		return nil
+	})
+	want := []tmuxclient.Match{{Line: 2, Text: "error: disk full"}, {Line: 4, Text: "error: retry"}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchHistory = %v, %v; want %v", got, err, want)
+	}
+}
+
+func TestSearchHistoryStops(t *testing.T) {
+	tmuxtest.Install(t, tmuxtest.New().On("capture-pane", "a\na\na\n", nil))
+	stop := errors.New("enough")
+	n := 0
+	err := tmuxclient.SearchHistory(t.Context(), "api", regexp.MustCompile("a"), func(tmuxclient.Match) error {
+		n++
+		return stop
+	})
+	if err != stop || n != 1 {
+		t.Errorf("SearchHistory = %v after %d matches, want it to stop at the first", err, n)
+	}
+}
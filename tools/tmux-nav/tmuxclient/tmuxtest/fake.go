@@ -0,0 +1,73 @@
+// Package tmuxtest provides a scripted fake tmux for unit tests.
+package tmuxtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// Response is a scripted result of one tmux command.
+type Response struct {
+	Out string
+	Err error
+}
+
+// Fake is a tmuxclient.Runner that answers commands from a script instead of
+// running tmux, and records every command it was given.
+type Fake struct {
+	mu     sync.Mutex
+	script map[string][]Response // queued responses by tmux command
+	calls  [][]string
+}
+
+// New creates a fake with an empty script.
+func New() *Fake {
+	return &Fake{script: map[string][]Response{}}
+}
+
+// On queues a response for the next invocation of the tmux command cmd
+// (e.g. "list-sessions"). Responses are used in order; the last one keeps
+// answering once the queue is down to it.
+func (f *Fake) On(cmd, out string, err error) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.script[cmd] = append(f.script[cmd], Response{Out: out, Err: err})
+	return f
+}
+
+// Run implements tmuxclient.Runner. Commands without a scripted response fail.
+func (f *Fake) Run(_ context.Context, args ...string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, append([]string(nil), args...))
+	if len(args) == 0 {
+		return nil, fmt.Errorf("fake tmux: no command")
+	}
+	queue := f.script[args[0]]
+	if len(queue) == 0 {
+		return nil, fmt.Errorf("fake tmux: unscripted command %q", strings.Join(args, " "))
+	}
+	r := queue[0]
+	if len(queue) > 1 {
+		f.script[args[0]] = queue[1:]
+	}
+	return []byte(r.Out), r.Err
+}
+
+// Calls returns the commands run so far, each as its argument list.
+func (f *Fake) Calls() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.calls...)
+}
+
+// Install makes the tmuxclient package use f for the rest of the test.
+func Install(t testing.TB, f *Fake) {
+	prev := tmuxclient.SetRunner(f)
+	t.Cleanup(func() { tmuxclient.SetRunner(prev) })
+}
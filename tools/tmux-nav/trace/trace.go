@@ -0,0 +1,233 @@
+// Package trace records how long tmux calls and navigator update cycles
+// take, and whether they failed, so "the navigator feels slow" can be
+// pinned on something. It is off unless Start is called.
+//
+// While tracing, calls slower than Slow are logged to the event log as
+// they happen (the navigator's log view, L, shows them with a running
+// summary), a summary per call is logged when tracing stops, and every
+// span can be written to a file as JSON lines.
+package trace
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+)
+
+// Slow is the duration past which a span is logged to the event log on its
+// own.
+const Slow = 250 * time.Millisecond
+
+// Span is one timed call.
+type Span struct {
+	Time  time.Time `json:"time"`
+	Kind  string    `json:"kind"` // "tmux", "update" or "view"
+	Name  string    `json:"name"` // tmux command or message type
+	MS    float64   `json:"ms"`
+	Error string    `json:"error,omitempty"`
+}
+
+// Stat summarizes the spans of one kind and name.
+type Stat struct {
+	Kind, Name string
+	Calls      int
+	Failed     int
+	Total, Max time.Duration
+}
+
+// Avg is the mean duration of the spans.
+func (s Stat) Avg() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Calls)
+}
+
+func (s Stat) String() string {
+	line := fmt.Sprintf("%s %s: %d calls, avg %v, max %v", s.Kind, s.Name, s.Calls,
+		s.Avg().Round(10*time.Microsecond), s.Max.Round(10*time.Microsecond))
+	if s.Failed > 0 {
+		line += fmt.Sprintf(", %d failed", s.Failed)
+	}
+	return line
+}
+
+var state struct {
+	sync.Mutex
+	on        bool
+	installed bool
+	out       io.WriteCloser
+	stats     map[[2]string]*Stat
+}
+
+// Start turns tracing on, routing tmux commands through a Runner. Spans are
+// also written to path, appending, unless it is empty.
+func Start(path string) error {
+	state.Lock()
+	defer state.Unlock()
+	if !state.installed {
+		r := &Runner{}
+		r.Next = tmuxclient.SetRunner(r)
+		state.installed = true
+	}
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		state.out = f
+	}
+	state.on = true
+	state.stats = map[[2]string]*Stat{}
+	return nil
+}
+
+// Stop turns tracing off, logging the summary to the event log.
+func Stop() error {
+	stats := Summary()
+	state.Lock()
+	defer state.Unlock()
+	if !state.on {
+		return nil
+	}
+	state.on = false
+	for _, s := range stats {
+		_ = eventlog.Append("", "trace", "%s", s)
+	}
+	if state.out == nil {
+		return nil
+	}
+	err := state.out.Close()
+	state.out = nil
+	return err
+}
+
+// Enabled reports whether tracing is on.
+func Enabled() bool {
+	state.Lock()
+	defer state.Unlock()
+	return state.on
+}
+
+// Record records a span of kind and name that started at start and just
+// ended with err. It does nothing while tracing is off, so callers can
+// write
+//
+//	defer trace.Record("update", name, time.Now(), nil)
+func Record(kind, name string, start time.Time, err error) {
+	d := time.Since(start)
+	state.Lock()
+	defer state.Unlock()
+	if !state.on {
+		return
+	}
+	key := [2]string{kind, name}
+	st := state.stats[key]
+	if st == nil {
+		st = &Stat{Kind: kind, Name: name}
+		state.stats[key] = st
+	}
+	st.Calls++
+	st.Total += d
+	st.Max = max(st.Max, d)
+	sp := Span{Time: start, Kind: kind, Name: name, MS: float64(d.Microseconds()) / 1000}
+	if err != nil {
+		st.Failed++
+		sp.Error = err.Error()
+	}
+	if d >= Slow {
+		_ = eventlog.Append("", "slow", "%s %s took %v", kind, name, d.Round(time.Millisecond))
+	}
+	if state.out != nil {
+		line, _ := json.Marshal(sp)
+		state.out.Write(append(line, '\n'))
+	}
+}
+
+// Summary returns the statistics so far, slowest total first.
+func Summary() []Stat {
+	state.Lock()
+	defer state.Unlock()
+	out := make([]Stat, 0, len(state.stats))
+	for _, s := range state.stats {
+		out = append(out, *s)
+	}
+	slices.SortFunc(out, func(a, b Stat) int { return cmp.Compare(b.Total, a.Total) })
+	return out
+}
+
+// Runner wraps a tmuxclient.Runner, recording each command it runs. Cached
+// output never reaches it, so the spans are the calls that really ran.
+type Runner struct {
+	Next tmuxclient.Runner
+}
+
+// Run implements tmuxclient.Runner.
+func (r Runner) Run(ctx context.Context, args ...string) ([]byte, error) {
+	start := time.Now()
+	out, err := r.Next.Run(ctx, args...)
+	Record("tmux", command(args), start, err)
+	return out, err
+}
+
+// Stream implements tmuxclient.Streamer. The span lasts until the output is
+// closed.
+func (r Runner) Stream(ctx context.Context, args ...string) (io.ReadCloser, error) {
+	start := time.Now()
+	var (
+		rc  io.ReadCloser
+		err error
+	)
+	if s, ok := r.Next.(tmuxclient.Streamer); ok {
+		rc, err = s.Stream(ctx, args...)
+	} else {
+		var out []byte
+		out, err = r.Next.Run(ctx, args...)
+		rc = io.NopCloser(bytes.NewReader(out))
+	}
+	if err != nil {
+		Record("tmux", command(args), start, err)
+		return nil, err
+	}
+	return &tracedStream{ReadCloser: rc, name: command(args), start: start}, nil
+}
+
+// tracedStream records its span when closed, with the error its reads
+// ended on.
+type tracedStream struct {
+	io.ReadCloser
+	name  string
+	start time.Time
+	err   error
+}
+
+func (t *tracedStream) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		t.err = err
+	}
+	return n, err
+}
+
+func (t *tracedStream) Close() error {
+	err := t.ReadCloser.Close()
+	Record("tmux", t.name, t.start, t.err)
+	return err
+}
+
+func command(args []string) string {
+	if len(args) == 0 {
+		return "(none)"
+	}
+	return args[0]
+}
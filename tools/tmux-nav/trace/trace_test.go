@@ -0,0 +1,72 @@
+package trace_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/eventlog"
+	"github.com/bjornslib/tmux-nav/tmuxclient"
+	"github.com/bjornslib/tmux-nav/tmuxclient/tmuxtest"
+	"github.com/bjornslib/tmux-nav/trace"
+)
+
+func TestTrace(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	tmuxtest.Install(t, tmuxtest.New().
+		On("kill-session", "", nil).
+		On("kill-session", "", errors.New("can't find session: gone")))
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	if err := trace.Start(path); err != nil {
+		t.Fatal(err)
+	}
+	tmuxclient.KillSession(t.Context(), "api")
+	tmuxclient.KillSession(t.Context(), "gone")
+	trace.Record("update", "tea.KeyMsg", time.Now().Add(-trace.Slow), nil)
+
+	stats := trace.Summary()
+	if len(stats) != 2 {
+		t.Fatalf("Summary() = %v, want tmux kill-session and update tea.KeyMsg", stats)
+	}
+	if s := stats[0]; s.Name != "tea.KeyMsg" || s.Calls != 1 {
+		t.Errorf("slowest = %v, want the update", s)
+	}
+	if s := stats[1]; s.Kind != "tmux" || s.Name != "kill-session" || s.Calls != 2 || s.Failed != 1 {
+		t.Errorf("tmux stat = %+v", s)
+	}
+	if err := trace.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	trace.Record("update", "ignored", time.Now(), nil)
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var spans []trace.Span
+	for sc := bufio.NewScanner(f); sc.Scan(); {
+		var sp trace.Span
+		if err := json.Unmarshal(sc.Bytes(), &sp); err != nil {
+			t.Fatal(err)
+		}
+		spans = append(spans, sp)
+	}
+	if len(spans) != 3 || spans[1].Error == "" || spans[2].MS < 250 {
+		t.Errorf("trace file = %+v", spans)
+	}
+
+	entries, _ := eventlog.Tail(10)
+	var kinds []string
+	for _, e := range entries {
+		kinds = append(kinds, e.Kind)
+	}
+	if got := strings.Join(kinds, " "); got != "slow trace trace" {
+		t.Errorf("event log kinds = %q, want a slow call then the summary", got)
+	}
+}
@@ -0,0 +1,259 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Info is what a transcript tells about an agent session.
+type Info struct {
+	Usage    Usage     `json:"usage"`
+	Task     string    `json:"task,omitempty"`      // latest prompt typed by the user
+	LastTool string    `json:"last_tool,omitempty"` // e.g. "Bash(go test ./...)"
+	Turns    int       `json:"turns"`               // user prompts so far
+	Model    string    `json:"model,omitempty"`
+	Todos    []Todo    `json:"todos,omitempty"` // the agent's latest todo list
+	Updated  time.Time `json:"updated"`         // timestamp of the last entry
+}
+
+// Todo is one item of the plan an agent keeps with its TodoWrite tool.
+type Todo struct {
+	Content string `json:"content"`
+	Status  string `json:"status"` // "pending", "in_progress" or "completed"
+}
+
+// Done reports whether the item is completed.
+func (t Todo) Done() bool { return t.Status == "completed" }
+
+// entry is the subset of a transcript line tmux-nav uses.
+type entry struct {
+	Type      string    `json:"type"`
+	RequestID string    `json:"requestId"`
+	IsMeta    bool      `json:"isMeta"`
+	Timestamp time.Time `json:"timestamp"`
+	CostUSD   *float64  `json:"costUSD"`
+	Message   struct {
+		ID      string          `json:"id"`
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"`
+		Usage   *struct {
+			InputTokens              int64 `json:"input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// block is one element of an array-valued message content.
+type block struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+// readState remembers how far a transcript has been read so repeated
+// refreshes only parse new lines.
+type readState struct {
+	offset int64
+	info   Info
+	seen   map[string]bool // message ids already counted for usage
+}
+
+var (
+	readMu    sync.Mutex
+	readCache = map[string]*readState{}
+)
+
+// Read returns what the transcript at path records so far. Results are
+// cached and updated incrementally as the file grows.
+func Read(path string) (Info, error) {
+	readMu.Lock()
+	defer readMu.Unlock()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Info{}, err
+	}
+	st := readCache[path]
+	if st == nil || info.Size() < st.offset {
+		st = &readState{seen: map[string]bool{}}
+		readCache[path] = st
+	}
+	if info.Size() == st.offset {
+		return st.info, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return Info{}, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(st.offset, io.SeekStart); err != nil {
+		return Info{}, err
+	}
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// Leave a partial trailing line for the next read.
+			break
+		}
+		st.offset += int64(len(line))
+		st.apply(line)
+	}
+	return st.info, nil
+}
+
+// ReadUsage returns the total usage recorded in the transcript at path.
+func ReadUsage(path string) (Usage, error) {
+	info, err := Read(path)
+	return info.Usage, err
+}
+
+func (st *readState) apply(line []byte) {
+	var e entry
+	if json.Unmarshal(line, &e) != nil {
+		return
+	}
+	if !e.Timestamp.IsZero() {
+		st.info.Updated = e.Timestamp
+	}
+	switch e.Type {
+	case "user":
+		if text := promptText(e.Message.Content); text != "" && !e.IsMeta {
+			st.info.Task = text
+			st.info.Turns++
+		}
+	case "assistant":
+		if e.Message.Model != "" {
+			st.info.Model = e.Message.Model
+		}
+		if tool := lastTool(e.Message.Content); tool != "" {
+			st.info.LastTool = tool
+		}
+		if todos, ok := todoList(e.Message.Content); ok {
+			st.info.Todos = todos
+		}
+		st.info.Usage.Add(st.usage(e))
+	}
+}
+
+// usage prices an assistant entry. Messages are streamed as several lines
+// sharing one id; each message is counted once.
+func (st *readState) usage(e entry) Usage {
+	if e.Message.Usage == nil {
+		return Usage{}
+	}
+	key := e.Message.ID + "/" + e.RequestID
+	if st.seen[key] {
+		return Usage{}
+	}
+	st.seen[key] = true
+
+	mu := e.Message.Usage
+	u := Usage{
+		InputTokens:      mu.InputTokens,
+		OutputTokens:     mu.OutputTokens,
+		CacheWriteTokens: mu.CacheCreationInputTokens,
+		CacheReadTokens:  mu.CacheReadInputTokens,
+	}
+	if e.CostUSD != nil {
+		u.CostUSD = *e.CostUSD
+	} else {
+		p := priceFor(e.Message.Model)
+		u.CostUSD = (float64(u.InputTokens)*p.in + float64(u.OutputTokens)*p.out +
+			float64(u.CacheWriteTokens)*p.write + float64(u.CacheReadTokens)*p.read) / 1e6
+	}
+	return u
+}
+
+// promptText extracts a human prompt from user message content, ignoring
+// tool results and slash-command plumbing.
+func promptText(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) != nil {
+		var blocks []block
+		if json.Unmarshal(raw, &blocks) != nil {
+			return ""
+		}
+		for _, b := range blocks {
+			if b.Type != "text" {
+				return "" // tool_result and friends aren't prompts
+			}
+			text += b.Text
+		}
+	}
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "<") || strings.HasPrefix(text, "Caveat:") {
+		return ""
+	}
+	return text
+}
+
+// lastTool describes the last tool_use block in assistant content.
+func lastTool(raw json.RawMessage) string {
+	var blocks []block
+	if json.Unmarshal(raw, &blocks) != nil {
+		return ""
+	}
+	desc := ""
+	for _, b := range blocks {
+		if b.Type != "tool_use" {
+			continue
+		}
+		desc = b.Name
+		if arg := toolArg(b.Input); arg != "" {
+			desc += "(" + arg + ")"
+		}
+	}
+	return desc
+}
+
+// todoList returns the todo list written by the last TodoWrite call in
+// assistant content. Each call replaces the whole list.
+func todoList(raw json.RawMessage) ([]Todo, bool) {
+	var blocks []block
+	if json.Unmarshal(raw, &blocks) != nil {
+		return nil, false
+	}
+	var todos []Todo
+	found := false
+	for _, b := range blocks {
+		if b.Type != "tool_use" || b.Name != "TodoWrite" {
+			continue
+		}
+		var in struct {
+			Todos []Todo `json:"todos"`
+		}
+		if json.Unmarshal(b.Input, &in) == nil {
+			todos, found = in.Todos, true
+		}
+	}
+	return todos, found
+}
+
+// toolArg picks the most telling input field of a tool call.
+func toolArg(raw json.RawMessage) string {
+	var in map[string]any
+	if json.Unmarshal(raw, &in) != nil {
+		return ""
+	}
+	for _, k := range []string{"command", "file_path", "pattern", "url", "description", "prompt"} {
+		if v, ok := in[k].(string); ok && v != "" {
+			v = strings.Join(strings.Fields(v), " ")
+			if len(v) > 60 {
+				v = v[:57] + "..."
+			}
+			return v
+		}
+	}
+	return ""
+}
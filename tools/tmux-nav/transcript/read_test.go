@@ -0,0 +1,110 @@
+package transcript_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bjornslib/tmux-nav/transcript"
+)
+
+// The lines of a short session: a prompt, an assistant message streamed
+// as two lines, plumbing that isn't a prompt, and a second prompt.
+var session = []string{
+	`{"type":"user","timestamp":"2026-01-01T09:00:00Z","message":{"content":"fix the flaky test"}}`,
+	`{"type":"assistant","requestId":"r1","timestamp":"2026-01-01T09:00:05Z","message":{"id":"m1","model":"claude-sonnet-4-5","content":[{"type":"text","text":"Looking."}],"usage":{"input_tokens":1000000,"output_tokens":0}}}`,
+	`{"type":"assistant","requestId":"r1","timestamp":"2026-01-01T09:00:06Z","message":{"id":"m1","model":"claude-sonnet-4-5","content":[{"type":"tool_use","name":"Read","input":{"file_path":"/src/api/server_test.go"}},{"type":"tool_use","name":"Bash","input":{"command":"go test   ./...\n-run Flaky"}}],"usage":{"input_tokens":1000000,"output_tokens":0}}}`,
+	`{"type":"user","timestamp":"2026-01-01T09:00:07Z","message":{"content":[{"type":"tool_result","content":"ok"}]}}`,
+	`{"type":"user","isMeta":true,"message":{"content":"Caveat: the messages below were generated by the user"}}`,
+	`{"type":"user","message":{"content":"<command-name>/clear</command-name>"}}`,
+	`not json`,
+	`{"type":"user","timestamp":"2026-01-01T09:01:00Z","message":{"content":[{"type":"text","text":"  now the "},{"type":"text","text":"docs  "}]}}`,
+	`{"type":"assistant","requestId":"r2","costUSD":0.5,"timestamp":"2026-01-01T09:01:30Z","message":{"id":"m2","model":"claude-opus-4-5","content":[{"type":"tool_use","name":"TodoWrite","input":{"todos":[{"content":"update README","status":"completed"},{"content":"update CHANGELOG","status":"in_progress"}]}}],"usage":{"output_tokens":200,"cache_read_input_tokens":3000}}}`,
+}
+
+func write(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(strings.Join(lines, "")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func lines(ls []string) []string {
+	out := make([]string, len(ls))
+	for i, l := range ls {
+		out[i] = l + "\n"
+	}
+	return out
+}
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	write(t, path, lines(session)...)
+
+	info, err := transcript.Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Task != "now the docs" || info.Turns != 2 {
+		t.Errorf("task %q after %d turns, want the second prompt after 2", info.Task, info.Turns)
+	}
+	if info.LastTool != "TodoWrite" || info.Model != "claude-opus-4-5" {
+		t.Errorf("last tool %q on %q, want TodoWrite on the latest model", info.LastTool, info.Model)
+	}
+	if len(info.Todos) != 2 || !info.Todos[0].Done() || info.Todos[1].Done() {
+		t.Errorf("todos = %+v, want the list from the last TodoWrite", info.Todos)
+	}
+	if got := info.Updated.Format("15:04:05"); got != "09:01:30" {
+		t.Errorf("updated %s, want the last entry's timestamp", got)
+	}
+	// m1 is counted once at Sonnet prices ($3 per million input tokens);
+	// m2 carries its own cost.
+	u := info.Usage
+	if u.InputTokens != 1000000 || u.OutputTokens != 200 || u.CacheReadTokens != 3000 || math.Abs(u.CostUSD-3.5) > 1e-9 {
+		t.Errorf("usage = %+v, want m1 once plus m2 at $3.50", u)
+	}
+}
+
+func TestReadLastTool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	write(t, path, lines(session[:3])...)
+	info, err := transcript.Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.LastTool != "Bash(go test ./... -run Flaky)" {
+		t.Errorf("last tool = %q, want the Bash call with its command on one line", info.LastTool)
+	}
+}
+
+func TestReadIncrementally(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	write(t, path, session[0]+"\n", session[7][:20])
+	info, err := transcript.Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Task != "fix the flaky test" || info.Turns != 1 {
+		t.Errorf("task %q after %d turns, want the partial line left unread", info.Task, info.Turns)
+	}
+
+	write(t, path, session[7][20:]+"\n")
+	if info, err = transcript.Read(path); err != nil || info.Task != "now the docs" || info.Turns != 2 {
+		t.Errorf("after the line was finished: task %q after %d turns, %v", info.Task, info.Turns, err)
+	}
+
+	// A transcript rewritten shorter is read from the start again.
+	if err := os.WriteFile(path, []byte(session[0]+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if info, err = transcript.Read(path); err != nil || info.Task != "fix the flaky test" || info.Turns != 1 {
+		t.Errorf("after truncation: task %q after %d turns, %v", info.Task, info.Turns, err)
+	}
+}
@@ -0,0 +1,52 @@
+// Package transcript reads Claude Code's JSONL session transcripts under
+// ~/.claude/projects to recover token usage and status details.
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Root returns the directory Claude Code keeps per-project transcripts in.
+func Root() string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "projects")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".claude", "projects")
+}
+
+var unsafePath = regexp.MustCompile(`[^A-Za-z0-9-]`)
+
+// ProjectDir returns the transcript directory for a working directory.
+// Claude Code names it after the absolute path with every character other
+// than letters, digits and dashes replaced by "-".
+func ProjectDir(cwd string) string {
+	return filepath.Join(Root(), unsafePath.ReplaceAllString(cwd, "-"))
+}
+
+// Latest returns the most recently modified transcript for cwd, or "" when
+// there is none.
+func Latest(cwd string) string {
+	entries, err := os.ReadDir(ProjectDir(cwd))
+	if err != nil {
+		return ""
+	}
+	var best string
+	var bestMod int64
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jsonl") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if mod := info.ModTime().UnixNano(); best == "" || mod > bestMod {
+			best, bestMod = filepath.Join(ProjectDir(cwd), e.Name()), mod
+		}
+	}
+	return best
+}
@@ -0,0 +1,52 @@
+package transcript
+
+import "strings"
+
+// Usage is aggregated token usage and estimated cost.
+type Usage struct {
+	InputTokens      int64   `json:"input_tokens"`
+	OutputTokens     int64   `json:"output_tokens"`
+	CacheWriteTokens int64   `json:"cache_write_tokens"`
+	CacheReadTokens  int64   `json:"cache_read_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+// Add accumulates o into u.
+func (u *Usage) Add(o Usage) {
+	u.InputTokens += o.InputTokens
+	u.OutputTokens += o.OutputTokens
+	u.CacheWriteTokens += o.CacheWriteTokens
+	u.CacheReadTokens += o.CacheReadTokens
+	u.CostUSD += o.CostUSD
+}
+
+// Tokens is the total of all token kinds.
+func (u Usage) Tokens() int64 {
+	return u.InputTokens + u.OutputTokens + u.CacheWriteTokens + u.CacheReadTokens
+}
+
+// price is USD per million tokens.
+type price struct{ in, out, write, read float64 }
+
+// prices are matched by model-name prefix, most specific first.
+var prices = []struct {
+	prefix string
+	p      price
+}{
+	{"claude-opus-4-5", price{5, 25, 6.25, 0.50}},
+	{"claude-opus", price{15, 75, 18.75, 1.50}},
+	{"claude-sonnet", price{3, 15, 3.75, 0.30}},
+	{"claude-3-7-sonnet", price{3, 15, 3.75, 0.30}},
+	{"claude-3-5-sonnet", price{3, 15, 3.75, 0.30}},
+	{"claude-haiku-4", price{1, 5, 1.25, 0.10}},
+	{"claude-3-5-haiku", price{0.80, 4, 1, 0.08}},
+}
+
+func priceFor(model string) price {
+	for _, p := range prices {
+		if strings.HasPrefix(model, p.prefix) {
+			return p.p
+		}
+	}
+	return price{3, 15, 3.75, 0.30} // assume Sonnet pricing
+}
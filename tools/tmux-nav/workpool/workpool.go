@@ -0,0 +1,82 @@
+// Package workpool runs background jobs on a bounded number of workers, so
+// a burst of work (a refresh capturing every pane, prefetching previews,
+// querying plugins) queues up instead of starting dozens of subprocesses
+// at once.
+package workpool
+
+import (
+	"context"
+	"sync"
+)
+
+// Pool bounds how many jobs run at once. A nil *Pool runs every job
+// immediately.
+type Pool struct {
+	slots chan struct{}
+
+	mu     sync.Mutex
+	groups map[string]context.CancelFunc
+	closed bool
+}
+
+// New returns a pool running at most workers jobs at a time.
+func New(workers int) *Pool {
+	return &Pool{
+		slots:  make(chan struct{}, max(1, workers)),
+		groups: map[string]context.CancelFunc{},
+	}
+}
+
+// Run waits for a free worker and runs fn on the calling goroutine. If ctx
+// ends first, fn never runs and Run returns ctx's error.
+func (p *Pool) Run(ctx context.Context, fn func()) error {
+	if p == nil {
+		fn()
+		return nil
+	}
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-p.slots }()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fn()
+	return nil
+}
+
+// Group starts a new batch of the named group's jobs and returns its
+// context, cancelling the previous batch: its jobs still waiting for a
+// worker are dropped, as they've been superseded.
+func (p *Pool) Group(name string) context.Context {
+	if p == nil {
+		return context.Background()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cancel := p.groups[name]; cancel != nil {
+		cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if p.closed {
+		cancel()
+	}
+	p.groups[name] = cancel
+	return ctx
+}
+
+// Close drops every group's waiting jobs. Jobs started afterwards in a
+// group are dropped too; those run without one still run.
+func (p *Pool) Close() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for _, cancel := range p.groups {
+		cancel()
+	}
+}
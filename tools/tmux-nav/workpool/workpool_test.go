@@ -0,0 +1,55 @@
+package workpool_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bjornslib/tmux-nav/workpool"
+)
+
+func TestRunBoundsConcurrency(t *testing.T) {
+	p := workpool.New(2)
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Run(context.Background(), func() {
+				n := running.Add(1)
+				for {
+					old := peak.Load()
+					if n <= old || peak.CompareAndSwap(old, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				running.Add(-1)
+			})
+		}()
+	}
+	wg.Wait()
+	if got := peak.Load(); got != 2 {
+		t.Errorf("peak concurrency = %d, want 2", got)
+	}
+}
+
+func TestGroupDropsSupersededJobs(t *testing.T) {
+	p := workpool.New(1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go p.Run(context.Background(), func() { close(started); <-release })
+	<-started
+
+	old := p.Group("preview")
+	done := make(chan error)
+	go func() { done <- p.Run(old, func() { t.Error("superseded job ran") }) }()
+	p.Group("preview")
+	if err := <-done; err == nil {
+		t.Error("Run of a superseded job returned nil")
+	}
+	close(release)
+}